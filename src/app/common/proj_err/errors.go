@@ -2,4 +2,10 @@ package proj_err
 
 import "errors"
 
-var ErrTimeout = errors.New("timeout")
\ No newline at end of file
+var ErrTimeout = errors.New("timeout")
+
+// ErrSessNotExisted 会话不存在或已过期
+var ErrSessNotExisted = errors.New("session not found")
+
+// ErrWrongPassword 旧密码缺失或不正确
+var ErrWrongPassword = errors.New("wrong password")
@@ -4,12 +4,26 @@ import gen_grpc "social_server/src/gen/grpc"
 
 type SessId string
 
+// SessDevice 登录设备，设备名和平台由客户端登录时提供，Ip 取自连接
+type SessDevice struct {
+    DeviceName string
+    Platform   string
+    Ip         string
+}
+
 type SessCtx struct {
     SessId    SessId
     Uid       uint64
     Username  string
+    SessDevice
     CreatedAt uint64
     ExpiresAt uint64
+    // LastSeenAt 最近一次续期的时间
+    LastSeenAt uint64
+    // SessKey 会话对外的标识，吊销访问令牌时使用
+    SessKey string
+    // ViaToken 由访问令牌认证，此时 SessId 为空，ExpiresAt 为令牌的过期时间
+    ViaToken bool
 }
 
 type EmChatMsgType int32
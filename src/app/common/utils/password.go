@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// 密码哈希格式：
+// 当前为 argon2id，按 PHC 字符串保存：$argon2id$v=19$m=19456,t=2,p=1$<盐>$<哈希>，盐和哈希为不带填充的 base64。
+// 前缀标明算法和版本，以后更换算法或调整参数时，旧哈希在用户下次登录时升级。
+// 旧格式为 CalPassHash 计算的不加盐 MD5，32 位十六进制，没有前缀
+
+const argon2idPrefix = "$argon2id$"
+
+// argon2id 参数，取 OWASP 推荐的最低配置，单次计算约占 19 MiB 内存
+const (
+	argon2Memory  uint32 = 19 * 1024 // KiB
+	argon2Time    uint32 = 2
+	argon2Threads uint8  = 1
+	argon2SaltLen        = 16
+	argon2KeyLen  uint32 = 32
+)
+
+var legacyPassHashRe = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+var ErrUnknownPassHash = errors.New("unknown password hash format")
+
+// HashPassword 用随机盐计算当前格式的密码哈希
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", fmt.Errorf("rand.Read: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		argon2Memory, argon2Time, argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// VerifyPassword 验证密码是否与哈希匹配。
+// needRehash 为 true 时哈希是旧格式或参数已过时，应在验证通过后用 HashPassword 重新计算
+func VerifyPassword(password string, passHash string) (match bool, needRehash bool, err error) {
+	if strings.HasPrefix(passHash, argon2idPrefix) {
+		return verifyArgon2id(password, passHash)
+	}
+	if legacyPassHashRe.MatchString(passHash) {
+		match = subtle.ConstantTimeCompare([]byte(CalPassHash(password)), []byte(passHash)) == 1
+		return match, match, nil
+	}
+	return false, false, ErrUnknownPassHash
+}
+
+func verifyArgon2id(password string, passHash string) (match bool, needRehash bool, err error) {
+	// "", "argon2id", "v=19", "m=19456,t=2,p=1", 盐, 哈希
+	parts := strings.Split(passHash, "$")
+	if len(parts) != 6 {
+		return false, false, fmt.Errorf("invalid argon2id hash")
+	}
+	var version int
+	_, err = fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return false, false, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return false, false, fmt.Errorf("unsupported argon2id version: %d", version)
+	}
+	var memory, time uint32
+	var threads uint8
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads)
+	if err != nil {
+		return false, false, fmt.Errorf("invalid argon2id params: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, fmt.Errorf("invalid argon2id hash: %w", err)
+	}
+
+	other := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false, nil
+	}
+	needRehash = memory != argon2Memory || time != argon2Time || threads != argon2Threads ||
+		len(salt) != argon2SaltLen || uint32(len(key)) != argon2KeyLen
+	return true, needRehash, nil
+}
+
+var (
+	dummyPassHash     string
+	dummyPassHashOnce sync.Once
+)
+
+// VerifyDummyPassword 用户不存在时也计算一次哈希，让响应时间与密码错误时相同，避免借此探测用户名
+func VerifyDummyPassword(password string) {
+	dummyPassHashOnce.Do(func() {
+		dummyPassHash, _ = HashPassword("")
+	})
+	_, _, _ = VerifyPassword(password, dummyPassHash)
+}
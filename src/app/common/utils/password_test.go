@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"testing"
+)
+
+// argon2idHash 用指定参数计算 PHC 格式的哈希
+func argon2idHash(password string, memory uint32, time uint32, threads uint8) string {
+	salt := []byte("0123456789abcdef")
+	key := argon2.IDKey([]byte(password), salt, time, memory, threads, argon2KeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, memory, time, threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func TestVerifyPassword(t *testing.T) {
+	current, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		passHash   string
+		match      bool
+		needRehash bool
+		wantErr    bool
+	}{
+		{"argon2id", "secret", current, true, false, false},
+		{"argon2id wrong password", "Secret", current, false, false, false},
+		{"legacy md5", "secret", CalPassHash("secret"), true, true, false},
+		{"legacy md5 wrong password", "Secret", CalPassHash("secret"), false, false, false},
+		{"outdated params", "secret", argon2idHash("secret", 8*1024, 1, 1), true, true, false},
+		{"outdated params wrong password", "Secret", argon2idHash("secret", 8*1024, 1, 1), false, false, false},
+		{"unknown format", "secret", "secret", false, false, true},
+		{"missing fields", "secret", "$argon2id$v=19$m=19456,t=2,p=1$c2FsdA", false, false, true},
+		{"bad version", "secret", "$argon2id$v=x$m=19456,t=2,p=1$c2FsdA$aGFzaA", false, false, true},
+		{"unsupported version", "secret", "$argon2id$v=16$m=19456,t=2,p=1$c2FsdA$aGFzaA", false, false, true},
+		{"bad params", "secret", "$argon2id$v=19$m=19456$c2FsdA$aGFzaA", false, false, true},
+		{"bad salt", "secret", "$argon2id$v=19$m=19456,t=2,p=1$!!$aGFzaA", false, false, true},
+		{"bad hash", "secret", "$argon2id$v=19$m=19456,t=2,p=1$c2FsdA$!!", false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, needRehash, err := VerifyPassword(tt.password, tt.passHash)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyPassword error = %v, want error %v", err, tt.wantErr)
+			}
+			if match != tt.match || needRehash != tt.needRehash {
+				t.Fatalf("VerifyPassword = match %v, needRehash %v, want %v, %v", match, needRehash, tt.match, tt.needRehash)
+			}
+		})
+	}
+
+	_, _, err = VerifyPassword("secret", "plain")
+	if !errors.Is(err, ErrUnknownPassHash) {
+		t.Fatalf("VerifyPassword unknown format: %v, want ErrUnknownPassHash", err)
+	}
+}
+
+func TestHashPasswordSalted(t *testing.T) {
+	a, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	b, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	if a == b {
+		t.Fatalf("two hashes of the same password are equal: %s", a)
+	}
+}
@@ -5,6 +5,7 @@ import (
     "encoding/hex"
 )
 
+// CalPassHash 旧格式的密码哈希，只用于验证尚未升级的密码，新密码使用 HashPassword
 func CalPassHash(password string) string {
     combined := "ss" + password
     hash := md5.New()
@@ -0,0 +1,286 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	. "social_server/src/utils/log"
+	"time"
+)
+
+// 收件箱保留策略：
+// 超出保留期限或条数上限的消息由后台任务移到 tb_user_inbox_archive，
+// 拉取更新不再返回归档消息，历史消息接口在收件箱读完后接着读归档。
+// 归档先插入再删除，插入时忽略已存在的行，多个节点同时归档也不会重复或丢失
+
+// InboxRetention 收件箱保留策略，各项为 0 时不限制
+type InboxRetention struct {
+	MaxAge         time.Duration
+	MaxMsgsPerUser int
+	MaxMsgsPerConv int
+}
+
+func (p InboxRetention) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxMsgsPerUser > 0 || p.MaxMsgsPerConv > 0
+}
+
+func inboxRetentionFromEnv() InboxRetention {
+	return InboxRetention{
+		MaxAge:         time.Duration(envUint("INBOX_RETENTION_DAYS", 0)) * 24 * time.Hour,
+		MaxMsgsPerUser: int(envUint("INBOX_MAX_MSGS_PER_USER", 0)),
+		MaxMsgsPerConv: int(envUint("INBOX_MAX_MSGS_PER_CONV", 0)),
+	}
+}
+
+// startInboxArchiver 配置了保留策略时启动后台归档任务
+func startInboxArchiver(storage InboxStorage) {
+	retention := inboxRetentionFromEnv()
+	if !retention.Enabled() {
+		return
+	}
+	interval := time.Duration(envUint("INBOX_ARCHIVE_INTERVAL_S", 3600)) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	go runInboxArchiver(storage, retention, interval)
+}
+
+func runInboxArchiver(storage InboxStorage, retention InboxRetention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		archived, err := storage.ArchiveInbox(context.Background(), retention)
+		if err != nil {
+			Log.Error("ArchiveInbox: %v", err)
+		}
+		if archived > 0 {
+			Log.Info("Archived %d inbox messages", archived)
+		}
+	}
+}
+
+func (p *DB) ArchiveInbox(ctx context.Context, retention InboxRetention) (archived int64, err error) {
+	var cutoff string
+	if retention.MaxAge > 0 {
+		cutoff = time.Now().UTC().Add(-retention.MaxAge).Format("2006-01-02 15:04:05")
+	}
+
+	// 各分片分别归档各自的收件箱
+	for i, s := range p.shards {
+		n, err := s.archiveShardInbox(ctx, retention, cutoff)
+		archived += n
+		if err != nil {
+			return archived, fmt.Errorf("shard %d archiveShardInbox: %w", i, err)
+		}
+	}
+	return archived, nil
+}
+
+func (p *DB) archiveShardInbox(ctx context.Context, retention InboxRetention, cutoff string) (archived int64, err error) {
+	var lastUid uint64
+	for {
+		uids, err := p.inboxUsersAfter(ctx, lastUid)
+		if err != nil {
+			return archived, fmt.Errorf("inboxUsersAfter: %w", err)
+		}
+		for _, uid := range uids {
+			n, err := p.archiveUserInbox(ctx, uid, retention, cutoff)
+			archived += n
+			if err != nil {
+				return archived, fmt.Errorf("archiveUserInbox: %w", err)
+			}
+		}
+		if len(uids) < sqlBatchSize {
+			return archived, nil
+		}
+		lastUid = uids[len(uids)-1]
+	}
+}
+
+// inboxUsersAfter 按 user_id 顺序返回一批收件箱非空的用户
+func (p *DB) inboxUsersAfter(ctx context.Context, lastUid uint64) (uids []uint64, err error) {
+	rows, err := p.queryRows(ctx, "SELECT DISTINCT user_id FROM tb_user_inbox WHERE user_id > ? ORDER BY user_id LIMIT ?",
+		lastUid, sqlBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("queryRows: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var uid uint64
+		err = rows.Scan(&uid)
+		if err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		uids = append(uids, uid)
+	}
+	return uids, nil
+}
+
+// inboxConv 收件箱中的一个会话。群聊 groupId 非 0，单聊为对方 uid
+type inboxConv struct {
+	groupId uint64
+	peerUid uint64
+}
+
+// cond 会话在 uid 收件箱中的查询条件。收件箱中的单聊消息都以 uid 为一方
+func (p inboxConv) cond(uid uint64) (string, []interface{}) {
+	if p.groupId != 0 {
+		return "group_id = ?", []interface{}{p.groupId}
+	}
+	return "group_id IS NULL AND sender_id IN (?, ?) AND receiver_id IN (?, ?)",
+		[]interface{}{uid, p.peerUid, uid, p.peerUid}
+}
+
+func (p *DB) archiveUserInbox(ctx context.Context, uid uint64, retention InboxRetention, cutoff string) (archived int64, err error) {
+	if cutoff != "" {
+		n, err := p.moveToArchive(ctx, uid, "sent_at < ?", cutoff)
+		archived += n
+		if err != nil {
+			return archived, fmt.Errorf("age moveToArchive: %w", err)
+		}
+	}
+
+	if retention.MaxMsgsPerConv > 0 {
+		convs, err := p.oversizedConvs(ctx, uid, retention.MaxMsgsPerConv)
+		if err != nil {
+			return archived, fmt.Errorf("oversizedConvs: %w", err)
+		}
+		for _, conv := range convs {
+			cond, args := conv.cond(uid)
+			n, err := p.archiveBeyond(ctx, uid, retention.MaxMsgsPerConv, cond, args...)
+			archived += n
+			if err != nil {
+				return archived, fmt.Errorf("conv archiveBeyond: %w", err)
+			}
+		}
+	}
+
+	if retention.MaxMsgsPerUser > 0 {
+		n, err := p.archiveBeyond(ctx, uid, retention.MaxMsgsPerUser, "1 = 1")
+		archived += n
+		if err != nil {
+			return archived, fmt.Errorf("user archiveBeyond: %w", err)
+		}
+	}
+	return archived, nil
+}
+
+// oversizedConvs 返回消息数超过 maxMsgs 的会话
+func (p *DB) oversizedConvs(ctx context.Context, uid uint64, maxMsgs int) (convs []inboxConv, err error) {
+	rows, err := p.queryRows(ctx, `
+		SELECT COALESCE(group_id, 0) AS conv_group_id,
+			CASE WHEN group_id IS NULL THEN (CASE WHEN sender_id = user_id THEN COALESCE(receiver_id, 0) ELSE sender_id END) ELSE 0 END AS conv_peer_id
+		FROM tb_user_inbox WHERE user_id = ?
+		GROUP BY conv_group_id, conv_peer_id HAVING COUNT(*) > ?`, uid, maxMsgs)
+	if err != nil {
+		return nil, fmt.Errorf("queryRows: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var conv inboxConv
+		err = rows.Scan(&conv.groupId, &conv.peerUid)
+		if err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		convs = append(convs, conv)
+	}
+	return convs, nil
+}
+
+// archiveBeyond 满足 cond 的消息只保留 seqId 最大的 keep 条，其余归档
+func (p *DB) archiveBeyond(ctx context.Context, uid uint64, keep int, cond string, args ...interface{}) (archived int64, err error) {
+	queryArgs := append(append([]interface{}{uid}, args...), keep-1)
+	row, err := p.queryRow(ctx, "SELECT seq_id FROM tb_user_inbox WHERE user_id = ? AND "+cond+" ORDER BY seq_id DESC LIMIT 1 OFFSET ?",
+		queryArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("queryRow: %w", err)
+	}
+	var threshold uint64
+	err = row.Scan(&threshold)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("Scan: %w", err)
+	}
+	return p.moveToArchive(ctx, uid, cond+" AND seq_id < ?", append(args, threshold)...)
+}
+
+// moveToArchive 把 uid 收件箱中满足 cond 的消息移到归档表。
+// 按 seqId 顺序每批移动 sqlBatchSize 条，每批一个事务，避免长事务和大量行锁
+func (p *DB) moveToArchive(ctx context.Context, uid uint64, cond string, args ...interface{}) (archived int64, err error) {
+	defer func() {
+		if archived > 0 {
+			p.markWritten(userKey(uid))
+			p.clearInboxTail(uid)
+		}
+	}()
+	for {
+		n, more, err := p.moveBatchToArchive(ctx, uid, cond, args...)
+		archived += n
+		if err != nil {
+			return archived, fmt.Errorf("moveBatchToArchive: %w", err)
+		}
+		if !more {
+			return archived, nil
+		}
+	}
+}
+
+// moveBatchToArchive 移动满足 cond 的 seqId 最小的一批消息，more 为 true 时可能还有剩余
+func (p *DB) moveBatchToArchive(ctx context.Context, uid uint64, cond string, args ...interface{}) (archived int64, more bool, err error) {
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("beginTx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	queryArgs := append(append([]interface{}{uid}, args...), sqlBatchSize)
+	rows, err := p.sqlTxQueryRows(ctx, tx, "SELECT seq_id FROM tb_user_inbox WHERE user_id = ? AND "+cond+" ORDER BY seq_id LIMIT ?",
+		queryArgs...)
+	if err != nil {
+		return 0, false, fmt.Errorf("sqlTxQueryRows: %w", err)
+	}
+	batchArgs := []interface{}{uid}
+	for rows.Next() {
+		var seqId uint64
+		err = rows.Scan(&seqId)
+		if err != nil {
+			rows.Close()
+			return 0, false, fmt.Errorf("Scan: %w", err)
+		}
+		batchArgs = append(batchArgs, seqId)
+	}
+	rows.Close()
+	batchSize := len(batchArgs) - 1
+	if batchSize == 0 {
+		tx.Rollback()
+		return 0, false, nil
+	}
+
+	batchCond := fmt.Sprintf("user_id = ? AND seq_id IN (%s)", sqlPlaceholders(batchSize))
+	_, err = p.sqlTxExec(ctx, tx, p.insertIgnoreSql()+" INTO tb_user_inbox_archive ("+inboxMsgColumns+") SELECT "+inboxMsgColumns+
+		" FROM tb_user_inbox WHERE "+batchCond, batchArgs...)
+	if err != nil {
+		return 0, false, fmt.Errorf("insert sqlTxExec: %w", err)
+	}
+	ret, err := p.sqlTxExec(ctx, tx, "DELETE FROM tb_user_inbox WHERE "+batchCond, batchArgs...)
+	if err != nil {
+		return 0, false, fmt.Errorf("delete sqlTxExec: %w", err)
+	}
+	archived, err = ret.RowsAffected()
+	if err != nil {
+		return 0, false, fmt.Errorf("RowsAffected: %w", err)
+	}
+
+	err = p.sqlTxCommit(tx)
+	if err != nil {
+		return 0, false, fmt.Errorf("sqlTxCommit: %w", err)
+	}
+	return archived, batchSize == sqlBatchSize, nil
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestArchiveInboxBatches 超过一批的消息分多个事务归档，保留 seqId 最大的消息
+func TestArchiveInboxBatches(t *testing.T) {
+	p := newTestSqliteDB(t, filepath.Join(t.TempDir(), "archive.db"), "1")
+	ctx := context.Background()
+
+	const uid, total, keep = 1, 2*sqlBatchSize + 100, 100
+	for start := 1; start <= total; start += sqlBatchSize {
+		var args []interface{}
+		for seqId := start; seqId < start+sqlBatchSize && seqId <= total; seqId++ {
+			args = append(args, uid, seqId, 2, seqId, 0, "m")
+		}
+		values := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?), ", len(args)/6), ", ")
+		_, err := p.sqlExec(ctx, "INSERT INTO tb_user_inbox (user_id, seq_id, sender_id, conv_msg_id, message_type, content) VALUES "+values, args...)
+		if err != nil {
+			t.Fatalf("insert sqlExec: %v", err)
+		}
+	}
+
+	archived, err := p.ArchiveInbox(ctx, InboxRetention{MaxMsgsPerUser: keep})
+	if err != nil {
+		t.Fatalf("ArchiveInbox: %v", err)
+	}
+	if archived != total-keep {
+		t.Fatalf("archived %d messages, want %d", archived, total-keep)
+	}
+
+	countRange := func(table string) (n int, minSeqId int, maxSeqId int) {
+		t.Helper()
+		row, err := p.queryRow(ctx, "SELECT COUNT(*), COALESCE(MIN(seq_id), 0), COALESCE(MAX(seq_id), 0) FROM "+table+" WHERE user_id = ?", uid)
+		if err != nil {
+			t.Fatalf("queryRow: %v", err)
+		}
+		err = row.Scan(&n, &minSeqId, &maxSeqId)
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		return n, minSeqId, maxSeqId
+	}
+	if n, minSeqId, maxSeqId := countRange("tb_user_inbox"); n != keep || minSeqId != total-keep+1 || maxSeqId != total {
+		t.Fatalf("inbox has %d messages in [%d, %d], want %d in [%d, %d]", n, minSeqId, maxSeqId, keep, total-keep+1, total)
+	}
+	if n, minSeqId, maxSeqId := countRange("tb_user_inbox_archive"); n != total-keep || minSeqId != 1 || maxSeqId != total-keep {
+		t.Fatalf("archive has %d messages in [%d, %d], want %d in [1, %d]", n, minSeqId, maxSeqId, total-keep, total-keep)
+	}
+}
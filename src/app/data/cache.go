@@ -11,6 +11,7 @@ import (
     "github.com/go-redis/redis/v8"
     "log"
     "os"
+    "social_server/src/app/common/proj_err"
     "social_server/src/app/common/types"
     . "social_server/src/utils/log"
     "strconv"
@@ -35,6 +36,8 @@ func (e *CacheNotFoundError) Error() string {
 
 type Cache struct {
     client *redis.Client
+    // inboxTailSize 每个用户缓存的最近收件箱消息条数，为 0 时不缓存
+    inboxTailSize int64
 }
 
 func NewCache() *Cache {
@@ -70,7 +73,10 @@ func NewCache() *Cache {
         Log.Info("Connected to Redis successfully!")
     }
 
-    return &Cache{client: redisClient}
+    return &Cache{
+        client:        redisClient,
+        inboxTailSize: int64(envUint("INBOX_TAIL_CACHE_SIZE", 100)),
+    }
 }
 
 func GenerateSessionID(uid uint64) (string, error) {
@@ -97,9 +103,7 @@ func GenerateSessionID(uid uint64) (string, error) {
     return sessionID, nil
 }
 
-func (p *Cache) CreateSess(username string, uid uint64, expireAfterSecs uint64) (sessId types.SessId, err error) {
-    ctx := context.Background()
-
+func (p *Cache) CreateSess(ctx context.Context, username string, uid uint64, device types.SessDevice, expireAfterSecs uint64) (sessId types.SessId, err error) {
     var sessIdStr string
     sessIdStr, err = GenerateSessionID(uid)
     if err != nil {
@@ -122,11 +126,13 @@ func (p *Cache) CreateSess(username string, uid uint64, expireAfterSecs uint64)
 
     // 会话上下文
     sessCtx := types.SessCtx{
-        SessId:    sessId,
-        Username:  username,
-        Uid:       uid,
-        CreatedAt: createdAt,
-        ExpiresAt: expiresAt,
+        SessId:     sessId,
+        Username:   username,
+        Uid:        uid,
+        SessDevice: device,
+        CreatedAt:  createdAt,
+        ExpiresAt:  expiresAt,
+        LastSeenAt: createdAt,
     }
 
     userSessionsKey := fmt.Sprintf("user:%v:sessions", uid)
@@ -134,10 +140,14 @@ func (p *Cache) CreateSess(username string, uid uint64, expireAfterSecs uint64)
     // 使用事务创建会话并关联到用户
     _, err = p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
         pipe.HSet(ctx, sessionKey, map[string]interface{}{
-            "Uid":       sessCtx.Uid,
-            "Username":  sessCtx.Username,
-            "CreatedAt": sessCtx.CreatedAt,
-            "ExpiresAt": sessCtx.ExpiresAt,
+            "Uid":        sessCtx.Uid,
+            "Username":   sessCtx.Username,
+            "DeviceName": sessCtx.DeviceName,
+            "Platform":   sessCtx.Platform,
+            "Ip":         sessCtx.Ip,
+            "CreatedAt":  sessCtx.CreatedAt,
+            "ExpiresAt":  sessCtx.ExpiresAt,
+            "LastSeenAt": sessCtx.LastSeenAt,
         })
         pipe.SAdd(ctx, userSessionsKey, sessIdStr)
         pipe.Expire(ctx, sessionKey, time.Duration(expireAfterSecs)*time.Second)      // 设置会话过期时间
@@ -152,38 +162,49 @@ func (p *Cache) CreateSess(username string, uid uint64, expireAfterSecs uint64)
     return sessId, nil
 }
 
-func (p *Cache) GetSessCtx(sessId types.SessId) (sessCtx *types.SessCtx, err error) {
+func (p *Cache) GetSessCtx(ctx context.Context, sessId types.SessId) (sessCtx *types.SessCtx, err error) {
     // 查询会话
     sessionKey := fmt.Sprintf("session:%s", sessId)
-    sessionData, err := p.client.HGetAll(context.Background(), sessionKey).Result()
+    sessionData, err := p.client.HGetAll(ctx, sessionKey).Result()
     if err != nil {
         return nil, err
     }
     if len(sessionData) == 0 {
-        return nil, fmt.Errorf("session not found")
+        return nil, fmt.Errorf("%w", proj_err.ErrSessNotExisted)
     }
+    return parseSessCtx(sessId, sessionData), nil
+}
 
+func parseSessCtx(sessId types.SessId, sessionData map[string]string) *types.SessCtx {
     uid, _ := strconv.ParseUint(sessionData["Uid"], 10, 64)
     createdAt, _ := strconv.ParseUint(sessionData["CreatedAt"], 10, 64)
     expiresAt, _ := strconv.ParseUint(sessionData["ExpiresAt"], 10, 64)
-    username := sessionData["Username"]
+    lastSeenAt, _ := strconv.ParseUint(sessionData["LastSeenAt"], 10, 64)
+    if lastSeenAt == 0 {
+        // 旧版本创建的会话没有此字段
+        lastSeenAt = createdAt
+    }
 
     return &types.SessCtx{
-        SessId:    sessId,
-        Username:  username,
-        Uid:       uid,
-        CreatedAt: createdAt,
-        ExpiresAt: expiresAt,
-    }, nil
+        SessId:   sessId,
+        Username: sessionData["Username"],
+        Uid:      uid,
+        SessDevice: types.SessDevice{
+            DeviceName: sessionData["DeviceName"],
+            Platform:   sessionData["Platform"],
+            Ip:         sessionData["Ip"],
+        },
+        CreatedAt:  createdAt,
+        ExpiresAt:  expiresAt,
+        LastSeenAt: lastSeenAt,
+    }
 }
 
-func (p *Cache) RenewSessCtx(sessCtx *types.SessCtx, expireAfterSecs uint64) (err error) {
+func (p *Cache) RenewSessCtx(ctx context.Context, sessCtx *types.SessCtx, expireAfterSecs uint64) (err error) {
     if expireAfterSecs == 0 {
         return fmt.Errorf("expireAfterSecs must be greater than zero")
     }
 
-    ctx := context.Background()
-
     // 计算新的过期时间
     currentTime := uint64(time.Now().Unix())
     newExpiresAt := currentTime + expireAfterSecs
@@ -194,8 +215,8 @@ func (p *Cache) RenewSessCtx(sessCtx *types.SessCtx, expireAfterSecs uint64) (er
 
     // 使用事务来更新会话信息和过期时间
     _, err = p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-        // 更新会话上下文中的过期时间
-        if err := pipe.HSet(ctx, sessionKey, "ExpiresAt", newExpiresAt).Err(); err != nil {
+        // 更新会话上下文中的过期时间和最近活动时间
+        if err := pipe.HSet(ctx, sessionKey, "ExpiresAt", newExpiresAt, "LastSeenAt", currentTime).Err(); err != nil {
             return fmt.Errorf("failed to set new expiration in session context: %w", err)
         }
         // 续期会话键
@@ -216,37 +237,37 @@ func (p *Cache) RenewSessCtx(sessCtx *types.SessCtx, expireAfterSecs uint64) (er
     return nil
 }
 
-func (p *Cache) RenewSessCtxBySessId(sessId types.SessId, expireAfterSecs uint64) error {
+func (p *Cache) RenewSessCtxBySessId(ctx context.Context, sessId types.SessId, expireAfterSecs uint64) error {
     // 获取会话上下文
-    sessCtx, err := p.GetSessCtx(sessId)
+    sessCtx, err := p.GetSessCtx(ctx, sessId)
     if err != nil {
-        return fmt.Errorf("GetSessCtx: %v", err)
+        return fmt.Errorf("GetSessCtx: %w", err)
     }
     if sessCtx == nil {
-        return fmt.Errorf("session not found")
+        return fmt.Errorf("%w", proj_err.ErrSessNotExisted)
     }
-    return p.RenewSessCtx(sessCtx, expireAfterSecs)
+    return p.RenewSessCtx(ctx, sessCtx, expireAfterSecs)
 }
 
-func (p *Cache) GetSessCtxByUid(uid uint64) (sessCtx *types.SessCtx, err error) {
+func (p *Cache) GetSessCtxByUid(ctx context.Context, uid uint64) (sessCtx *types.SessCtx, err error) {
     // 查找用户的会话集合 key
     userSessionsKey := fmt.Sprintf("user:%v:sessions", uid)
 
     // 获取所有会话 ID
-    sessIds, err := p.client.SMembers(context.Background(), userSessionsKey).Result()
+    sessIds, err := p.client.SMembers(ctx, userSessionsKey).Result()
     if err != nil {
         return nil, err
     }
     if len(sessIds) == 0 {
-        return nil, fmt.Errorf("no sessions found for user: %s", uid)
+        return nil, fmt.Errorf("no sessions found for user: %d", uid)
     }
 
     for _, sessId := range sessIds {
-        sessCtx, err := p.GetSessCtx(types.SessId(sessId))
+        sessCtx, err := p.GetSessCtx(ctx, types.SessId(sessId))
         if err != nil {
             // 如果会话不存在，继续处理下一个会话 ID
-            if err.Error() == "session not found" {
-                p.client.SRem(context.Background(), userSessionsKey, sessId)
+            if errors.Is(err, proj_err.ErrSessNotExisted) {
+                p.client.SRem(ctx, userSessionsKey, sessId)
                 continue
             }
             return nil, err
@@ -255,20 +276,61 @@ func (p *Cache) GetSessCtxByUid(uid uint64) (sessCtx *types.SessCtx, err error)
         }
     }
 
-    return nil, fmt.Errorf("no active sessions found for user: %s", uid)
+    return nil, fmt.Errorf("no active sessions found for user: %d", uid)
+}
+
+func (p *Cache) GetUserSessList(ctx context.Context, uid uint64) (sessList []*types.SessCtx, err error) {
+    userSessionsKey := fmt.Sprintf("user:%v:sessions", uid)
+
+    sessIds, err := p.client.SMembers(ctx, userSessionsKey).Result()
+    if err != nil {
+        return nil, fmt.Errorf("SMembers: %w", err)
+    }
+    if len(sessIds) == 0 {
+        return nil, nil
+    }
+
+    cmds := make([]*redis.StringStringMapCmd, len(sessIds))
+    _, err = p.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+        for i, sessId := range sessIds {
+            cmds[i] = pipe.HGetAll(ctx, fmt.Sprintf("session:%s", sessId))
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, fmt.Errorf("Pipelined: %w", err)
+    }
+
+    var expired []interface{}
+    for i, cmd := range cmds {
+        sessionData := cmd.Val()
+        if len(sessionData) == 0 {
+            // 会话已过期，从用户会话集合中移除
+            expired = append(expired, sessIds[i])
+            continue
+        }
+        sessList = append(sessList, parseSessCtx(types.SessId(sessIds[i]), sessionData))
+    }
+    if len(expired) > 0 {
+        err = p.client.SRem(ctx, userSessionsKey, expired...).Err()
+        if err != nil {
+            Log.Warn("SRem: %v", err)
+        }
+    }
+    return sessList, nil
 }
 
-func (p *Cache) DeleteSess(sessId types.SessId) (err error) {
+func (p *Cache) DeleteSess(ctx context.Context, sessId types.SessId) (err error) {
     // 查找会话 key
     sessionKey := fmt.Sprintf("session:%s", sessId)
 
     // 获取会话数据
-    sessionData, err := p.client.HGetAll(context.Background(), sessionKey).Result()
+    sessionData, err := p.client.HGetAll(ctx, sessionKey).Result()
     if err != nil {
         return fmt.Errorf("HGetAll: %w", err)
     }
     if len(sessionData) == 0 {
-        return fmt.Errorf("session not found")
+        return fmt.Errorf("%w", proj_err.ErrSessNotExisted)
     }
 
     // 获取uid
@@ -276,9 +338,9 @@ func (p *Cache) DeleteSess(sessId types.SessId) (err error) {
     userSessionsKey := fmt.Sprintf("user:%v:sessions", uid)
 
     // 删除会话和用户会话集合中的会话 ID
-    _, err = p.client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
-        pipe.Del(context.Background(), sessionKey)
-        pipe.SRem(context.Background(), userSessionsKey, string(sessId))
+    _, err = p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+        pipe.Del(ctx, sessionKey)
+        pipe.SRem(ctx, userSessionsKey, string(sessId))
         return nil
     })
 
@@ -289,8 +351,7 @@ func (p *Cache) DeleteSess(sessId types.SessId) (err error) {
     return nil
 }
 
-func (p *Cache) DeleteUserSess(uid uint64) error {
-    ctx := context.Background()
+func (p *Cache) DeleteUserSess(ctx context.Context, uid uint64) error {
     userSessionsKey := fmt.Sprintf("user:%v:sessions", uid)
 
     // 获取用户的所有会话ID
@@ -320,118 +381,180 @@ func (p *Cache) DeleteUserSess(uid uint64) error {
     return nil
 }
 
-// Chat
-func (p *Cache) GetChatMsgList(uid uint64, seqId uint64) (msgs []types.ChatMsgOfConv, err error) {
-    ctx := context.Background()
-    key := fmt.Sprintf("chat:msglist:%d", uid)
-    result, err := p.client.Get(ctx, key).Result()
-    if err == redis.Nil {
-        return nil, &CacheNotFoundError{Key: key}
-    } else if err != nil {
-        return nil, err
+const tokenRevokedKey = "token:revoked"
+
+func (p *Cache) RevokeTokenSess(ctx context.Context, sessKeys []string, until uint64) (err error) {
+    if len(sessKeys) == 0 {
+        return nil
+    }
+    members := make([]*redis.Z, len(sessKeys))
+    for i, sessKey := range sessKeys {
+        members[i] = &redis.Z{Score: float64(until), Member: sessKey}
+    }
+    err = p.client.ZAdd(ctx, tokenRevokedKey, members...).Err()
+    if err != nil {
+        return fmt.Errorf("ZAdd: %w", err)
+    }
+    return nil
+}
+
+func (p *Cache) GetRevokedTokenSess(ctx context.Context) (revoked map[string]uint64, err error) {
+    // 先删除到期的记录
+    now := strconv.FormatInt(time.Now().Unix(), 10)
+    err = p.client.ZRemRangeByScore(ctx, tokenRevokedKey, "-inf", now).Err()
+    if err != nil {
+        return nil, fmt.Errorf("ZRemRangeByScore: %w", err)
+    }
+    members, err := p.client.ZRangeWithScores(ctx, tokenRevokedKey, 0, -1).Result()
+    if err != nil {
+        return nil, fmt.Errorf("ZRangeWithScores: %w", err)
+    }
+    revoked = make(map[string]uint64, len(members))
+    for _, m := range members {
+        sessKey, _ := m.Member.(string)
+        revoked[sessKey] = uint64(m.Score)
     }
+    return revoked, nil
+}
 
-    // todo seqId
+func loginFailKey(key string) string {
+    return "login:fail:" + key
+}
 
-    // 反序列化
-    err = json.Unmarshal([]byte(result), &msgs)
+func loginLockKey(key string) string {
+    return "login:lock:" + key
+}
+
+func (p *Cache) AddLoginFailure(ctx context.Context, key string, windowS uint64) (count uint64, err error) {
+    now := time.Now()
+    failKey := loginFailKey(key)
+    // 成员带随机后缀，同一时刻的多次失败分别计数
+    suffix := make([]byte, 4)
+    _, err = rand.Read(suffix)
     if err != nil {
-        return nil, err
+        return 0, fmt.Errorf("rand.Read: %w", err)
     }
+    member := strconv.FormatInt(now.UnixMilli(), 10) + ":" + hex.EncodeToString(suffix)
+    windowStart := strconv.FormatInt(now.UnixMilli()-int64(windowS)*1000, 10)
 
-    return msgs, nil
+    var zCard *redis.IntCmd
+    _, err = p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+        pipe.ZRemRangeByScore(ctx, failKey, "-inf", "("+windowStart)
+        pipe.ZAdd(ctx, failKey, &redis.Z{Score: float64(now.UnixMilli()), Member: member})
+        zCard = pipe.ZCard(ctx, failKey)
+        pipe.Expire(ctx, failKey, time.Duration(windowS)*time.Second)
+        return nil
+    })
+    if err != nil {
+        return 0, fmt.Errorf("TxPipelined: %w", err)
+    }
+    return uint64(zCard.Val()), nil
 }
 
-func (p *Cache) CacheChatMsgList(uid uint64, seqId uint64, msgs []types.ChatMsgOfConv) (err error) {
-    ctx := context.Background()
-    key := fmt.Sprintf("chat:msglist:%d", uid)
-    data, err := json.Marshal(msgs)
+func (p *Cache) GetLoginFailureCount(ctx context.Context, key string, windowS uint64) (count uint64, err error) {
+    windowStart := strconv.FormatInt(time.Now().UnixMilli()-int64(windowS)*1000, 10)
+    n, err := p.client.ZCount(ctx, loginFailKey(key), windowStart, "+inf").Result()
     if err != nil {
-        return err
+        return 0, fmt.Errorf("ZCount: %w", err)
     }
-    return p.client.Set(ctx, key, data, 24*time.Hour).Err()
+    return uint64(n), nil
 }
 
-func (p *Cache) ClearCacheChatMsgList(uid uint64, seqId uint64) (err error) {
-    ctx := context.Background()
-    key := fmt.Sprintf("chat:msglist:%d", uid)
-    return p.client.Del(ctx, key).Err()
+func (p *Cache) LockLogin(ctx context.Context, key string, lockS uint64) (err error) {
+    err = p.client.Set(ctx, loginLockKey(key), "1", time.Duration(lockS)*time.Second).Err()
+    if err != nil {
+        return fmt.Errorf("Set: %w", err)
+    }
+    return nil
+}
+
+func (p *Cache) GetLoginLockTtl(ctx context.Context, key string) (ttlS uint64, err error) {
+    ttl, err := p.client.PTTL(ctx, loginLockKey(key)).Result()
+    if err != nil {
+        return 0, fmt.Errorf("PTTL: %w", err)
+    }
+    // 键不存在或没有过期时间时 ttl 为负数
+    if ttl <= 0 {
+        return 0, nil
+    }
+    // 不足一秒按一秒算
+    return uint64((ttl + time.Second - 1) / time.Second), nil
 }
 
-func (p *Cache) SendMsg(peerId types.PeerId, msg types.ChatMsg) (err error) {
+func (p *Cache) ClearLoginFailures(ctx context.Context, key string) (wasLocked bool, err error) {
+    var delLock *redis.IntCmd
+    _, err = p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+        pipe.Del(ctx, loginFailKey(key))
+        delLock = pipe.Del(ctx, loginLockKey(key))
+        return nil
+    })
+    if err != nil {
+        return false, fmt.Errorf("TxPipelined: %w", err)
+    }
+    return delLock.Val() > 0, nil
+}
+
+func (p *Cache) SendMsg(ctx context.Context, peerId types.PeerId, msg types.ChatMsg) (err error) {
     // 具体实现根据业务逻辑
     return errors.New("method not implemented")
 }
 
-func (p *Cache) CreateGroupConv(uid uint64) (ConvId uint64, err error) {
+func (p *Cache) CreateGroupConv(ctx context.Context, uid uint64) (ConvId uint64, err error) {
     // 具体实现根据业务逻辑
     return 0, errors.New("method not implemented")
 }
 
-func (p *Cache) GroupGetList(uid uint64) (ConvIdList []uint64, err error) {
-    ctx := context.Background()
+func (p *Cache) GroupGetList(ctx context.Context, uid uint64) (ConvIdList []uint64, fillVersion uint64, err error) {
     key := fmt.Sprintf("group:convlist:%d", uid)
-    result, err := p.client.Get(ctx, key).Result()
-    if err == redis.Nil {
-        return nil, &CacheNotFoundError{Key: key}
-    } else if err != nil {
-        return nil, err
+    result, fillVersion, err := p.getRelationCache(ctx, key)
+    if err != nil {
+        return nil, fillVersion, err
     }
 
     // 反序列化
     err = json.Unmarshal([]byte(result), &ConvIdList)
     if err != nil {
-        return nil, err
+        return nil, 0, err
     }
 
-    return ConvIdList, nil
+    return ConvIdList, 0, nil
 }
 
-func (p *Cache) CacheGroupConvList(uid uint64, ConvIdList []uint64) (err error) {
-    ctx := context.Background()
+func (p *Cache) CacheGroupConvList(ctx context.Context, uid uint64, fillVersion uint64, ConvIdList []uint64) (err error) {
     key := fmt.Sprintf("group:convlist:%d", uid)
     data, err := json.Marshal(ConvIdList)
     if err != nil {
         return err
     }
-    return p.client.Set(ctx, key, data, 24*time.Hour).Err()
+    return p.fillRelationCache(ctx, key, fillVersion, string(data))
 }
 
-func (p *Cache) ClearCacheGroupConvList(uid uint64) (err error) {
-    ctx := context.Background()
-    key := fmt.Sprintf("group:convlist:%d", uid)
-    return p.client.Del(ctx, key).Err()
+func (p *Cache) ClearCacheGroupConvList(ctx context.Context, uid uint64) (err error) {
+    return p.clearRelationCache(ctx, fmt.Sprintf("group:convlist:%d", uid))
 }
 
-func (p *Cache) IsUserInConv(convId uint64, uid uint64) (inConv bool, err error) {
-    ctx := context.Background()
+func (p *Cache) IsUserInConv(ctx context.Context, convId uint64, uid uint64) (inConv bool, fillVersion uint64, err error) {
     key := fmt.Sprintf("conv:isuserin:%d:%d", convId, uid)
-    result, err := p.client.Get(ctx, key).Result()
-    if err == redis.Nil {
-        return false, &CacheNotFoundError{Key: key}
-    } else if err != nil {
-        return false, err
+    result, fillVersion, err := p.getRelationCache(ctx, key)
+    if err != nil {
+        return false, fillVersion, err
     }
 
     inConv = result == "true"
-    return inConv, nil
+    return inConv, 0, nil
 }
 
-func (p *Cache) CacheIsUserInConv(convId uint64, uid uint64, inConv bool) (err error) {
-    ctx := context.Background()
+func (p *Cache) CacheIsUserInConv(ctx context.Context, convId uint64, uid uint64, fillVersion uint64, inConv bool) (err error) {
     key := fmt.Sprintf("conv:isuserin:%d:%d", convId, uid)
-    return p.client.Set(ctx, key, inConv, 24*time.Hour).Err()
+    return p.fillRelationCache(ctx, key, fillVersion, strconv.FormatBool(inConv))
 }
 
-func (p *Cache) ClearCacheIsUserInConv(convId uint64, uid uint64) (err error) {
-    ctx := context.Background()
-    key := fmt.Sprintf("conv:isuserin:%d:%d", convId, uid)
-    return p.client.Del(ctx, key).Err()
+func (p *Cache) ClearCacheIsUserInConv(ctx context.Context, convId uint64, uid uint64) (err error) {
+    return p.clearRelationCache(ctx, fmt.Sprintf("conv:isuserin:%d:%d", convId, uid))
 }
 
 // UserMgmt
-func (p *Cache) IsUsernameExisted(userName string) (isExisted bool, err error) {
-    ctx := context.Background()
+func (p *Cache) IsUsernameExisted(ctx context.Context, userName string) (isExisted bool, err error) {
     key := fmt.Sprintf("user:username:%s", userName)
     result, err := p.client.Get(ctx, key).Result()
     if err == redis.Nil {
@@ -444,46 +567,38 @@ func (p *Cache) IsUsernameExisted(userName string) (isExisted bool, err error) {
     return isExisted, nil
 }
 
-func (p *Cache) CacheIsUsernameExisted(userName string, isExisted bool) (err error) {
-    ctx := context.Background()
+func (p *Cache) CacheIsUsernameExisted(ctx context.Context, userName string, isExisted bool) (err error) {
     key := fmt.Sprintf("user:username:%s", userName)
     return p.client.Set(ctx, key, isExisted, 24*time.Hour).Err()
 }
 
-func (p *Cache) ClearCacheIsUsernameExisted(userName string) (err error) {
-    ctx := context.Background()
+func (p *Cache) ClearCacheIsUsernameExisted(ctx context.Context, userName string) (err error) {
     key := fmt.Sprintf("user:username:%s", userName)
     return p.client.Del(ctx, key).Err()
 }
 
-func (p *Cache) UserAuthenticate(param *types.UmUserAuthenticateParam) (pass bool, err error) {
-    ctx := context.Background()
-    // 查询用户信息
-    key := fmt.Sprintf("user:userinfo:%s", param.Username)
+func (p *Cache) GetUserAuth(ctx context.Context, username string) (user *types.UmUserInfo, err error) {
+    key := fmt.Sprintf("user:userinfo:%s", username)
     result, err := p.client.Get(ctx, key).Result()
     if err == redis.Nil {
-        return false, &CacheNotFoundError{Key: key}
+        return nil, &CacheNotFoundError{Key: key}
     } else if err != nil {
-        return false, err
-    } else {
-        // 反序列化
-        var user types.UmUserInfo
-        err = json.Unmarshal([]byte(result), &user)
-        if err != nil {
-            return false, err
-        }
-
-        // 验证密码
-        if user.Password == param.Passphase {
-            return true, nil
-        } else {
-            return false, nil
-        }
+        return nil, err
     }
+    // 反序列化
+    user = &types.UmUserInfo{}
+    err = json.Unmarshal([]byte(result), user)
+    if err != nil {
+        return nil, err
+    }
+    if user.Uid == 0 {
+        // 旧版本缓存没有 uid，按未命中处理
+        return nil, &CacheNotFoundError{Key: key}
+    }
+    return user, nil
 }
 
-func (p *Cache) CacheUserAuthenticate(user *types.UmUserInfo) (err error) {
-    ctx := context.Background()
+func (p *Cache) CacheUserAuthenticate(ctx context.Context, user *types.UmUserInfo) (err error) {
     key := fmt.Sprintf("user:userinfo:%s", user.Username)
     data, err := json.Marshal(user)
     if err != nil {
@@ -492,63 +607,179 @@ func (p *Cache) CacheUserAuthenticate(user *types.UmUserInfo) (err error) {
     return p.client.Set(ctx, key, data, 24*time.Hour).Err()
 }
 
-func (p *Cache) ClearCacheUserAuthenticate(username string) (err error) {
-    ctx := context.Background()
+func (p *Cache) ClearCacheUserAuthenticate(ctx context.Context, username string) (err error) {
     key := fmt.Sprintf("user:userinfo:%s", username)
     return p.client.Del(ctx, key).Err()
 }
 
-func (p *Cache) Register(param *types.UmRegisterParam) (err error) {
+func (p *Cache) Register(ctx context.Context, param *types.UmRegisterParam) (err error) {
     // 具体实现根据业务逻辑
     return errors.New("method not implemented")
 }
 
-func (p *Cache) Unregister(param *types.UmUnregisterParam) (err error) {
+func (p *Cache) Unregister(ctx context.Context, param *types.UmUnregisterParam) (err error) {
     // 具体实现根据业务逻辑
     return errors.New("method not implemented")
 }
 
-func (p *Cache) AddContacts(param *types.UmContactAddRequestParam) (err error) {
+func (p *Cache) AddContacts(ctx context.Context, param *types.UmContactAddRequestParam) (err error) {
     // 具体实现根据业务逻辑
     return errors.New("method not implemented")
 }
 
-func (p *Cache) DelContacts(param *types.UmDelContactsParam) (err error) {
+func (p *Cache) DelContacts(ctx context.Context, param *types.UmDelContactsParam) (err error) {
     // 具体实现根据业务逻辑
     return errors.New("method not implemented")
 }
 
-func (p *Cache) GetContactList(uid uint64) (contactsUid []uint64, err error) {
-    ctx := context.Background()
+func (p *Cache) GetContactList(ctx context.Context, uid uint64) (contactsUid []uint64, fillVersion uint64, err error) {
     key := fmt.Sprintf("contact:list:%d", uid)
-    result, err := p.client.Get(ctx, key).Result()
-    if err == redis.Nil {
-        return nil, &CacheNotFoundError{Key: key}
-    } else if err != nil {
-        return nil, err
+    result, fillVersion, err := p.getRelationCache(ctx, key)
+    if err != nil {
+        return nil, fillVersion, err
     }
 
     // 反序列化
     err = json.Unmarshal([]byte(result), &contactsUid)
     if err != nil {
-        return nil, err
+        return nil, 0, err
     }
 
-    return contactsUid, nil
+    return contactsUid, 0, nil
 }
 
-func (p *Cache) CacheContactList(uid uint64, contactsUid []uint64) (err error) {
-    ctx := context.Background()
+func (p *Cache) CacheContactList(ctx context.Context, uid uint64, fillVersion uint64, contactsUid []uint64) (err error) {
     key := fmt.Sprintf("contact:list:%d", uid)
     data, err := json.Marshal(contactsUid)
     if err != nil {
         return err
     }
-    return p.client.Set(ctx, key, data, 24*time.Hour).Err()
+    return p.fillRelationCache(ctx, key, fillVersion, string(data))
 }
 
-func (p *Cache) ClearCacheContactList(uid uint64) (err error) {
-    ctx := context.Background()
-    key := fmt.Sprintf("contact:list:%d", uid)
-    return p.client.Del(ctx, key).Err()
-}
\ No newline at end of file
+func (p *Cache) ClearCacheContactList(ctx context.Context, uid uint64) (err error) {
+    return p.clearRelationCache(ctx, fmt.Sprintf("contact:list:%d", uid))
+}
+
+type contactRelation struct {
+    IsMutualContact bool   `json:"isMutualContact"`
+    RemarkName      string `json:"remarkName"`
+}
+
+func (p *Cache) GetContactRelation(ctx context.Context, uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, fillVersion uint64, err error) {
+    key := fmt.Sprintf("contact:relation:%d:%d", uid, contactUid)
+    result, fillVersion, err := p.getRelationCache(ctx, key)
+    if err != nil {
+        return false, "", fillVersion, err
+    }
+
+    var relation contactRelation
+    err = json.Unmarshal([]byte(result), &relation)
+    if err != nil {
+        return false, "", 0, err
+    }
+    return relation.IsMutualContact, relation.RemarkName, 0, nil
+}
+
+func (p *Cache) CacheContactRelation(ctx context.Context, uid uint64, contactUid uint64, fillVersion uint64, isMutualContact bool, remarkName string) (err error) {
+    key := fmt.Sprintf("contact:relation:%d:%d", uid, contactUid)
+    data, err := json.Marshal(contactRelation{IsMutualContact: isMutualContact, RemarkName: remarkName})
+    if err != nil {
+        return err
+    }
+    return p.fillRelationCache(ctx, key, fillVersion, string(data))
+}
+
+func (p *Cache) ClearCacheContactRelation(ctx context.Context, uid uint64, contactUid uint64) (err error) {
+    return p.clearRelationCache(ctx, fmt.Sprintf("contact:relation:%d:%d", uid, contactUid))
+}
+
+// ClearCacheContacts 清除两个用户之间双向的联系人列表和关系缓存
+func (p *Cache) ClearCacheContacts(ctx context.Context, uid uint64, contactUid uint64) (err error) {
+    return p.clearRelationCache(ctx,
+        fmt.Sprintf("contact:list:%d", uid),
+        fmt.Sprintf("contact:list:%d", contactUid),
+        fmt.Sprintf("contact:relation:%d:%d", uid, contactUid),
+        fmt.Sprintf("contact:relation:%d:%d", contactUid, uid),
+    )
+}
+
+// ClearCacheGroupMembers 清除一批用户的群列表缓存和在群 groupId 中的成员关系缓存
+func (p *Cache) ClearCacheGroupMembers(ctx context.Context, groupId uint64, uids []uint64) (err error) {
+    for start := 0; start < len(uids); start += 500 {
+        end := start + 500
+        if end > len(uids) {
+            end = len(uids)
+        }
+        keys := make([]string, 0, 2*(end-start))
+        for _, uid := range uids[start:end] {
+            keys = append(keys, fmt.Sprintf("group:convlist:%d", uid), fmt.Sprintf("conv:isuserin:%d:%d", groupId, uid))
+        }
+        err = p.clearRelationCache(ctx, keys...)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// 联系人和群组关系缓存。每个 key 带一个版本号 <key>:ver，清除缓存时加一；
+// 未命中时返回当前版本加一作为 fillVersion，写回时版本已变说明期间数据被修改，放弃写回，
+// 避免回源读到的旧数据在清除之后写入缓存
+const relationCacheTTL = 24 * time.Hour
+
+func relationCacheVerKey(key string) string {
+    return key + ":ver"
+}
+
+// 返回 {1, value} 命中；{0, version + 1} 未命中
+var relationCacheReadScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if value then
+    return {1, value}
+end
+return {0, tonumber(redis.call('GET', KEYS[2]) or '0') + 1}
+`)
+
+// ARGV[1] 为 fillVersion，ARGV[2] 为值，ARGV[3] 为过期秒数
+var relationCacheFillScript = redis.NewScript(`
+if tonumber(redis.call('GET', KEYS[2]) or '0') + 1 ~= tonumber(ARGV[1]) then
+    return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'EX', ARGV[3])
+return 1
+`)
+
+func (p *Cache) getRelationCache(ctx context.Context, key string) (value string, fillVersion uint64, err error) {
+    res, err := relationCacheReadScript.Run(ctx, p.client, []string{key, relationCacheVerKey(key)}).Slice()
+    if err != nil {
+        return "", 0, err
+    }
+    if hit, _ := res[0].(int64); hit == 0 {
+        version, _ := res[1].(int64)
+        return "", uint64(version), &CacheNotFoundError{Key: key}
+    }
+    value, _ = res[1].(string)
+    return value, 0, nil
+}
+
+func (p *Cache) fillRelationCache(ctx context.Context, key string, fillVersion uint64, value string) error {
+    if fillVersion == 0 {
+        return nil
+    }
+    return relationCacheFillScript.Run(ctx, p.client, []string{key, relationCacheVerKey(key)},
+        fillVersion, value, int64(relationCacheTTL/time.Second)).Err()
+}
+
+// clearRelationCache 删除缓存并增加版本号，作废进行中的回源。版本号保留到缓存过期之后
+func (p *Cache) clearRelationCache(ctx context.Context, keys ...string) error {
+    _, err := p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+        pipe.Del(ctx, keys...)
+        for _, key := range keys {
+            pipe.Incr(ctx, relationCacheVerKey(key))
+            pipe.Expire(ctx, relationCacheVerKey(key), relationCacheTTL)
+        }
+        return nil
+    })
+    return err
+}
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"social_server/src/app/common/types"
+	. "social_server/src/utils/log"
+	"sync"
+	"testing"
+)
+
+var setupLoggerOnce sync.Once
+
+func newTestCache(t *testing.T) *Cache {
+	t.Helper()
+	setupLoggerOnce.Do(SetupLogger)
+	mr := miniredis.RunT(t)
+	t.Setenv("REDIS_HOST", mr.Host())
+	t.Setenv("REDIS_PORT", mr.Port())
+	t.Setenv("REDIS_PASSWORD", "")
+	t.Setenv("REDIS_DB", "")
+	return NewCache()
+}
+
+func isCacheMiss(err error) bool {
+	var notFound *CacheNotFoundError
+	return errors.As(err, &notFound)
+}
+
+// TestRelationCacheFillAfterClear 回源期间缓存被清除时，回源读到的旧数据不能写入缓存
+func TestRelationCacheFillAfterClear(t *testing.T) {
+	cache := newTestCache(t)
+	ctx := context.Background()
+
+	// 未命中后回源，期间成员被移出群并清除缓存
+	_, fillVersion, err := cache.IsUserInConv(ctx, 1, 2)
+	if !isCacheMiss(err) || fillVersion == 0 {
+		t.Fatalf("IsUserInConv: fillVersion %d, err %v, want miss", fillVersion, err)
+	}
+	if err := cache.ClearCacheGroupMembers(ctx, 1, []uint64{2}); err != nil {
+		t.Fatalf("ClearCacheGroupMembers: %v", err)
+	}
+	if err := cache.CacheIsUserInConv(ctx, 1, 2, fillVersion, true); err != nil {
+		t.Fatalf("CacheIsUserInConv: %v", err)
+	}
+	_, fillVersion, err = cache.IsUserInConv(ctx, 1, 2)
+	if !isCacheMiss(err) {
+		t.Fatalf("IsUserInConv after stale fill: %v, want miss", err)
+	}
+
+	// 期间没有修改时正常写回
+	if err := cache.CacheIsUserInConv(ctx, 1, 2, fillVersion, false); err != nil {
+		t.Fatalf("CacheIsUserInConv: %v", err)
+	}
+	inConv, _, err := cache.IsUserInConv(ctx, 1, 2)
+	if err != nil || inConv {
+		t.Fatalf("IsUserInConv: %v %v, want cached false", inConv, err)
+	}
+	if err := cache.ClearCacheGroupMembers(ctx, 1, []uint64{2}); err != nil {
+		t.Fatalf("ClearCacheGroupMembers: %v", err)
+	}
+	_, fillVersion, _ = cache.IsUserInConv(ctx, 1, 2)
+	cache.CacheIsUserInConv(ctx, 1, 2, fillVersion, true)
+	inConv, _, err = cache.IsUserInConv(ctx, 1, 2)
+	if err != nil || !inConv {
+		t.Fatalf("IsUserInConv: %v %v, want cached true", inConv, err)
+	}
+
+	// 联系人缓存同样按版本写回
+	_, _, fillVersion, err = cache.GetContactRelation(ctx, 3, 4)
+	if !isCacheMiss(err) {
+		t.Fatalf("GetContactRelation: %v, want miss", err)
+	}
+	if err := cache.ClearCacheContacts(ctx, 4, 3); err != nil {
+		t.Fatalf("ClearCacheContacts: %v", err)
+	}
+	cache.CacheContactRelation(ctx, 3, 4, fillVersion, true, "old")
+	if _, _, _, err := cache.GetContactRelation(ctx, 3, 4); !isCacheMiss(err) {
+		t.Fatalf("GetContactRelation after stale fill: %v, want miss", err)
+	}
+
+	_, fillVersion, err = cache.GroupGetList(ctx, 5)
+	if !isCacheMiss(err) {
+		t.Fatalf("GroupGetList: %v, want miss", err)
+	}
+	cache.CacheGroupConvList(ctx, 5, fillVersion, []uint64{7, 8})
+	convIds, _, err := cache.GroupGetList(ctx, 5)
+	if err != nil || len(convIds) != 2 {
+		t.Fatalf("GroupGetList: %v %v, want [7 8]", convIds, err)
+	}
+}
+
+// TestInboxTailAppendOverflow 一次追加超出条数上限时只保留最新的消息，floor 随之抬高
+func TestInboxTailAppendOverflow(t *testing.T) {
+	t.Setenv("INBOX_TAIL_CACHE_SIZE", "2")
+	cache := newTestCache(t)
+	ctx := context.Background()
+
+	const uid = 1
+	_, fillVersion, _ := cache.GetChatMsgList(ctx, uid, 0, 10)
+	err := cache.CacheChatMsgList(ctx, uid, 0, fillVersion, nil)
+	if err != nil {
+		t.Fatalf("CacheChatMsgList: %v", err)
+	}
+
+	err = cache.BeginAppendChatMsg(ctx, []uint64{uid})
+	if err != nil {
+		t.Fatalf("BeginAppendChatMsg: %v", err)
+	}
+	var msgs []types.ChatMsgOfConv
+	for seqId := uint64(1); seqId <= 3; seqId++ {
+		msgs = append(msgs, types.ChatMsgOfConv{SeqId: seqId, ConvMsgId: seqId})
+	}
+	err = cache.EndAppendChatMsg(ctx, []uint64{uid}, map[uint64][]types.ChatMsgOfConv{uid: msgs})
+	if err != nil {
+		t.Fatalf("EndAppendChatMsg: %v", err)
+	}
+
+	if _, _, err := cache.GetChatMsgList(ctx, uid, 0, 10); !isCacheMiss(err) {
+		t.Fatalf("GetChatMsgList below floor: %v, want miss", err)
+	}
+	cached, _, err := cache.GetChatMsgList(ctx, uid, 1, 10)
+	if err != nil {
+		t.Fatalf("GetChatMsgList: %v", err)
+	}
+	if len(cached) != 2 || cached[0].SeqId != 2 || cached[1].SeqId != 3 {
+		t.Fatalf("cached %+v, want seq ids 2 and 3", cached)
+	}
+}
@@ -1,14 +1,19 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
+	"log"
+	_ "modernc.org/sqlite"
+	"net"
 	"os"
 	"social_server/src/app/common/types"
 	"social_server/src/gen/grpc"
 	. "social_server/src/utils/log"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -87,8 +92,23 @@ func tryPush(ch chan struct{}) bool {
 	}
 }
 
+const (
+	driverMysql  = "mysql"
+	driverSqlite = "sqlite"
+)
+
+// 多行语句每批的行数，避免超出占位符数量限制
+const sqlBatchSize = 500
+
+// sqlPlaceholders 生成 n 个以逗号分隔的占位符
+func sqlPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
 type DB struct {
+	driver               string
 	dsn                  string
+	autoMigrate          bool
 	maxReconnectInterval time.Duration
 	connectTimeout       time.Duration
 	maxPendingRequests   int
@@ -100,6 +120,52 @@ type DB struct {
 	pendingRequestsCount int
 
 	retrying 	     	  bool
+
+	userSeqAlloc  *SeqAllocator[uint64]
+	chatSeqAlloc  *SeqAllocator[[2]uint64]
+	groupSeqAlloc *SeqAllocator[uint64]
+
+	// 成员数超过此值的群改为读扩散，为 0 时不启用
+	readDiffusionThreshold uint64
+
+	// 只读副本，为 nil 时所有读取走主库
+	replicas *replicaSet
+
+	// 收件箱等按 key 路由的分片，未配置分片时只有自身
+	shards []*DB
+
+	// 收件箱尾部缓存，为 nil 时不缓存
+	inboxTail InboxTailCache
+}
+
+func newDB(driver string, dsn string, autoMigrate bool) *DB {
+	// 号段大小，默认不预分配
+	segmentSize := uint64(1)
+	if s := os.Getenv("SEQ_SEGMENT_SIZE"); s != "" {
+		var err error
+		segmentSize, err = strconv.ParseUint(s, 10, 64)
+		if err != nil || segmentSize == 0 {
+			log.Fatalf("Invalid SEQ_SEGMENT_SIZE value: %s", s)
+		}
+	}
+
+	p := &DB{
+		driver:               driver,
+		dsn:                  dsn,
+		autoMigrate:          autoMigrate,
+		maxReconnectInterval: 10 * time.Second,
+		connectTimeout:       30 * time.Second,
+		maxPendingRequests:   10000,
+		reconnectChan:        make(chan struct{}, 1),
+		retrying:			  false,
+		readDiffusionThreshold: groupReadDiffusionThreshold(),
+	}
+	// 收件箱 seqId 不使用号段，见 SeqAllocator
+	p.userSeqAlloc = NewSeqAllocator(1, p.leaseUserSeqIds)
+	p.chatSeqAlloc = NewSeqAllocator(segmentSize, p.leaseChatSeqIds)
+	p.groupSeqAlloc = NewSeqAllocator(segmentSize, p.leaseGroupSeqIds)
+	p.shards = []*DB{p}
+	return p
 }
 
 func NewStorage() *DB {
@@ -113,30 +179,106 @@ func NewStorage() *DB {
 		dbPort = "3306"
 	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=true&loc=UTC", dbUser, dbPassword, dbHost, dbPort, dbName)
+	dsnOf := func(addr string) string {
+		return fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=true&loc=UTC", dbUser, dbPassword, addr, dbName)
+	}
 
-	return &DB{
-		dsn:                  dsn,
-		maxReconnectInterval: 10 * time.Second,
-		connectTimeout:       30 * time.Second,
-		maxPendingRequests:   10000,
-		reconnectChan:        make(chan struct{}, 1),
-		retrying:			  false,
+	p := newDB(driverMysql, dsnOf(net.JoinHostPort(dbHost, dbPort)), os.Getenv("DB_AUTO_MIGRATE") == "true")
+
+	// 只读副本和分片，逗号分隔的 host 或 host:port，账号和库名与主库相同
+	replicaAddrs := parseHostList(os.Getenv("DB_REPLICA_HOSTS"), "3306")
+	maxLag := time.Duration(envUint("DB_REPLICA_MAX_LAG_S", 1)) * time.Second
+	var err error
+	p.replicas, err = newReplicaSet(driverMysql, replicaAddrs, dsnOf, maxLag)
+	if err != nil {
+		log.Fatalf("newReplicaSet: %v", err)
 	}
+	if shards := newShards(dsnOf); len(shards) > 0 {
+		p.shards = shards
+	}
+	return p
 }
 
-func (p *DB) connectDb() error {
+// NewSqliteStorage 使用 SQLite 文件作为存储，适合单机小规模部署
+func NewSqliteStorage() *DB {
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		path = "social_server.db"
+	}
+
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path)
+
+	// 单机部署默认自动迁移
+	return newDB(driverSqlite, dsn, os.Getenv("DB_AUTO_MIGRATE") != "false")
+}
+
+// Open 直接建立连接，不检查表结构版本。用于 migrate 等命令
+func (p *DB) Open() error {
 	var err error
-	p.db, err = sql.Open("mysql", p.dsn)
+	p.db, err = sql.Open(p.driver, p.dsn)
 	if err != nil {
 		return err
 	}
-	p.db.SetConnMaxLifetime(p.connectTimeout)
-	p.db.SetMaxOpenConns(10)
-	p.db.SetMaxIdleConns(10)
+	if p.driver == driverSqlite {
+		// SQLite 同一时间只允许一个写者，用单连接串行化所有操作
+		p.db.SetMaxOpenConns(1)
+	} else {
+		p.db.SetConnMaxLifetime(p.connectTimeout)
+		p.db.SetMaxOpenConns(10)
+		p.db.SetMaxIdleConns(10)
+	}
 	return p.db.Ping()
 }
 
+func (p *DB) Close() error {
+	p.replicas.close()
+	for _, s := range p.ShardDBs() {
+		s.Close()
+	}
+	if p.db == nil {
+		return nil
+	}
+	return p.db.Close()
+}
+
+func (p *DB) connectDb() error {
+	err := p.Open()
+	if err != nil {
+		return err
+	}
+	err = p.checkSchema()
+	if err != nil {
+		p.db.Close()
+		p.db = nil
+		return err
+	}
+	return nil
+}
+
+// upsertContactSql 插入联系人，已存在时更新为双向联系人
+func (p *DB) upsertContactSql() string {
+	if p.driver == driverSqlite {
+		return `
+		INSERT INTO tb_user_contacts (user_id, contact_id, is_mutual_contact)
+		VALUES (?, ?, true)
+		ON CONFLICT (user_id, contact_id) DO UPDATE SET is_mutual_contact = excluded.is_mutual_contact
+	`
+	}
+	return `
+		INSERT INTO tb_user_contacts (user_id, contact_id, is_mutual_contact)
+		VALUES (?, ?, true)
+		ON DUPLICATE KEY UPDATE is_mutual_contact = VALUES(is_mutual_contact)
+	`
+}
+
+// insertIgnoreSql 插入时忽略主键冲突
+func (p *DB) insertIgnoreSql() string {
+	if p.driver == driverSqlite {
+		return "INSERT OR IGNORE"
+	}
+	return "INSERT IGNORE"
+}
+
 func (p *DB) reconnectTask() {
 	var err error
 	for {
@@ -177,6 +319,11 @@ func (p *DB) Init() {
 	p.connectOnce.Do(func() {
 		go p.reconnectTask()
 		tryPush(p.reconnectChan)
+		p.replicas.start()
+		for _, s := range p.ShardDBs() {
+			s.Init()
+		}
+		p.startFanOutRedriver()
 	})
 }
 
@@ -187,11 +334,11 @@ func (p *DB) withReconnectHandling(action func() (interface{}, error)) (interfac
 	if p.retrying {
 		return nil, errors.New("db connection issue")
 	}
+	p.dbMutex.Lock()
 	if p.pendingRequestsCount > p.maxPendingRequests {
+		p.dbMutex.Unlock()
 		return nil, errors.New("too many pending db requests")
 	}
-
-	p.dbMutex.Lock()
 	p.pendingRequestsCount++
 	p.dbMutex.Unlock()
 	defer func() {
@@ -202,16 +349,19 @@ func (p *DB) withReconnectHandling(action func() (interface{}, error)) (interfac
 
 	ret, err := action()
 	if err != nil {
-		p.triggerReconnect()
+		// 请求取消或超时不是连接问题
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+			p.triggerReconnect()
+		}
 		return nil, fmt.Errorf("action: %w", err)
 	}
 
 	return ret, nil
 }
 
-func (p *DB) sqlExec(sqlStr string, args ...interface{}) (sql.Result, error) {
+func (p *DB) sqlExec(ctx context.Context, sqlStr string, args ...interface{}) (sql.Result, error) {
 	ret, err := p.withReconnectHandling(func() (interface{}, error) {
-		return p.db.Exec(sqlStr, args...)
+		return p.db.ExecContext(ctx, sqlStr, args...)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("withReconnectHandling: %w", err)
@@ -219,9 +369,19 @@ func (p *DB) sqlExec(sqlStr string, args ...interface{}) (sql.Result, error) {
 	return ret.(sql.Result), nil
 }
 
-func (p *DB) sqlTxExec(tx *sql.Tx, sqlStr string, args ...interface{}) (sql.Result, error) {
+func (p *DB) beginTx(ctx context.Context) (*sql.Tx, error) {
+	ret, err := p.withReconnectHandling(func() (interface{}, error) {
+		return p.db.BeginTx(ctx, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("withReconnectHandling: %w", err)
+	}
+	return ret.(*sql.Tx), nil
+}
+
+func (p *DB) sqlTxExec(ctx context.Context, tx *sql.Tx, sqlStr string, args ...interface{}) (sql.Result, error) {
 	ret, err := p.withReconnectHandling(func() (interface{}, error) {
-		return tx.Exec(sqlStr, args...)
+		return tx.ExecContext(ctx, sqlStr, args...)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("withReconnectHandling: %w", err)
@@ -239,9 +399,9 @@ func (p *DB) sqlTxCommit(tx *sql.Tx) error {
 	return nil
 }
 
-func (p *DB) queryRow(query string, args ...interface{}) (*sql.Row, error) {
+func (p *DB) sqlTxQueryRow(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*sql.Row, error) {
 	ret, err := p.withReconnectHandling(func() (interface{}, error) {
-		return p.db.QueryRow(query, args...), nil
+		return tx.QueryRowContext(ctx, query, args...), nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("withReconnectHandling: %w", err)
@@ -249,9 +409,29 @@ func (p *DB) queryRow(query string, args ...interface{}) (*sql.Row, error) {
 	return ret.(*sql.Row), nil
 }
 
-func (p *DB) queryRows(query string, args ...interface{}) (*sql.Rows, error) {
+func (p *DB) sqlTxQueryRows(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*sql.Rows, error) {
 	ret, err := p.withReconnectHandling(func() (interface{}, error) {
-		return p.db.Query(query, args...)
+		return tx.QueryContext(ctx, query, args...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("withReconnectHandling: %w", err)
+	}
+	return ret.(*sql.Rows), nil
+}
+
+func (p *DB) queryRow(ctx context.Context, query string, args ...interface{}) (*sql.Row, error) {
+	ret, err := p.withReconnectHandling(func() (interface{}, error) {
+		return p.db.QueryRowContext(ctx, query, args...), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("withReconnectHandling: %w", err)
+	}
+	return ret.(*sql.Row), nil
+}
+
+func (p *DB) queryRows(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ret, err := p.withReconnectHandling(func() (interface{}, error) {
+		return p.db.QueryContext(ctx, query, args...)
 	})
 	if err != nil {
 		return nil, err
@@ -294,109 +474,36 @@ func convertDbMsgToChatMsgOfConv(rowMsg InboxMsg) (msg types.ChatMsgOfConv, err
 }
 
 
-func (p *DB) AllocateSeqId(uid uint64) (seqId uint64, err error) {
-	row, err := p.queryRow("SELECT seq_id FROM tb_seq_id_user WHERE user_id = ?", uid)
+func (p *DB) AllocateSeqId(ctx context.Context, uid uint64) (seqId uint64, err error) {
+	seqId, err = p.userSeqAlloc.Alloc(ctx, uid, 1)
 	if err != nil {
-		return 0, fmt.Errorf("queryRow: %w", err)
+		return 0, fmt.Errorf("Alloc: %w", err)
 	}
-
-	err = row.Scan(&seqId)
-	if err != nil {
-		if err != sql.ErrNoRows {
-			return 0, fmt.Errorf("Scan: %w", err)
-		}
-
-		// 还没有分配过 seqId，需要初始化
-		_, err = p.sqlExec( "INSERT INTO tb_seq_id_user (user_id, seq_id) VALUES (?, ?)", uid, 2)
-		if err != nil {
-			return 0, fmt.Errorf("sqlExec: %w", err)
-		}
-		return 1, nil
-	}
-
-	// 更新 seqId
-	_, err = p.sqlExec("UPDATE tb_seq_id_user SET seq_id = ? WHERE user_id = ?", seqId + 1, uid)
-	if err != nil {
-		return 0, fmt.Errorf("sqlExec: %w", err)
-	}
-
 	return seqId, nil
 }
 
-func (p *DB) AllocateChatSeqId(uid1 uint64, uid2 uint64) (seqId uint64, err error) {
+func (p *DB) AllocateChatSeqId(ctx context.Context, uid1 uint64, uid2 uint64) (seqId uint64, err error) {
 	if uid1 > uid2 {
 		uid1, uid2 = uid2, uid1
 	}
-	row, err := p.queryRow("SELECT seq_id FROM tb_seq_id_chat WHERE user1_id = ? AND user2_id = ?", uid1, uid2)
+	seqId, err = p.chatSeqAlloc.Alloc(ctx, [2]uint64{uid1, uid2}, 1)
 	if err != nil {
-		return 0, fmt.Errorf("queryRow: %w", err)
+		return 0, fmt.Errorf("Alloc: %w", err)
 	}
-
-	err = row.Scan(&seqId)
-	if err != nil {
-		if err != sql.ErrNoRows {
-			return 0, fmt.Errorf("Scan: %w", err)
-		}
-
-		// 还没有分配过 seqId，需要初始化
-		_, err = p.sqlExec( "INSERT INTO tb_seq_id_chat (user1_id, user2_id, seq_id) VALUES (?, ?, 2)", uid1, uid2)
-		if err != nil {
-			return 0, fmt.Errorf("sqlExec: %w", err)
-		}
-		return 1, nil
-	}
-
-	// 更新 seqId
-	_, err = p.sqlExec("UPDATE tb_seq_id_chat SET seq_id = ? WHERE user1_id = ? AND user2_id = ?", seqId + 1, uid1, uid2)
-	if err != nil {
-		return 0, fmt.Errorf("sqlExec: %w", err)
-	}
-
 	return seqId, nil
 }
 
-func (p *DB) AllocateGroupSeqId(groupId uint64) (seqId uint64, err error) {
-	row, err := p.queryRow("SELECT seq_id FROM tb_seq_id_group WHERE group_id = ?", groupId)
-	if err != nil {
-		return 0, fmt.Errorf("queryRow: %w", err)
-	}
-
-	err = row.Scan(&seqId)
+func (p *DB) AllocateGroupSeqId(ctx context.Context, groupId uint64) (seqId uint64, err error) {
+	seqId, err = p.groupSeqAlloc.Alloc(ctx, groupId, 1)
 	if err != nil {
-		if err != sql.ErrNoRows {
-			return 0, fmt.Errorf("Scan: %w", err)
-		}
-
-		// 还没有分配过 seqId，需要初始化
-		_, err = p.sqlExec( "INSERT INTO tb_seq_id_group (group_id, seq_id) VALUES (?, ?)", groupId, 2)
-		if err != nil {
-			return 0, fmt.Errorf("sqlExec: %w", err)
-		}
-		return 1, nil
+		return 0, fmt.Errorf("Alloc: %w", err)
 	}
-
-	// 更新 seqId
-	_, err = p.sqlExec("UPDATE tb_seq_id_group SET seq_id = ? WHERE group_id = ?", seqId + 1, groupId)
-	if err != nil {
-		return 0, fmt.Errorf("sqlExec: %w", err)
-	}
-
 	return seqId, nil
 }
 
 // UserMgmt
-func (p *DB) UserIsUsernameExisted(username string) (bool, error) {
-	row, err := p.queryRow("SELECT COUNT(*) FROM tb_users WHERE LOWER(username) = LOWER(?)", username)
-	var count int
-	err = row.Scan(&count)
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
-
-func (p *DB) UserAuthenticate(param *types.UmUserAuthenticateParam) (bool, error) {
-	row, err := p.queryRow("SELECT COUNT(*) FROM tb_users WHERE username = ? AND password = ?", param.Username, param.Passphase)
+func (p *DB) UserIsUsernameExisted(ctx context.Context, username string) (bool, error) {
+	row, err := p.queryRow(ctx, "SELECT COUNT(*) FROM tb_users WHERE LOWER(username) = LOWER(?)", username)
 	var count int
 	err = row.Scan(&count)
 	if err != nil {
@@ -405,8 +512,8 @@ func (p *DB) UserAuthenticate(param *types.UmUserAuthenticateParam) (bool, error
 	return count > 0, nil
 }
 
-func (p *DB) UserRegister(param *types.UmRegisterParam) (uint64, error) {
-	res, err := p.sqlExec("INSERT INTO tb_users (password, username, nickname, email, avatar) VALUES (?, ?, ?, ?, ?)",
+func (p *DB) UserRegister(ctx context.Context, param *types.UmRegisterParam) (uint64, error) {
+	res, err := p.sqlExec(ctx, "INSERT INTO tb_users (password, username, nickname, email, avatar) VALUES (?, ?, ?, ?, ?)",
 		param.Passwd, param.Username, param.Nickname, param.Email, param.Avatar)
 	if err != nil {
 		return 0, fmt.Errorf("sqlExec: %w", err)
@@ -419,13 +526,13 @@ func (p *DB) UserRegister(param *types.UmRegisterParam) (uint64, error) {
 	return uint64(id), nil
 }
 
-func (p *DB) UserUnregister(param *types.UmUnregisterParam) (error) {
-	_, err := p.sqlExec("DELETE FROM tb_users WHERE user_id = ?", param.Uid)
+func (p *DB) UserUnregister(ctx context.Context, param *types.UmUnregisterParam) (error) {
+	_, err := p.sqlExec(ctx, "DELETE FROM tb_users WHERE user_id = ?", param.Uid)
 	return err
 }
 
-func (p *DB) UserGetInfo(uid uint64) (user *types.UmUserInfo, err error) {
-	row, err := p.queryRow("SELECT user_id, password, username, nickname, email, avatar FROM tb_users WHERE user_id = ?", uid)
+func (p *DB) UserGetInfo(ctx context.Context, uid uint64) (user *types.UmUserInfo, err error) {
+	row, err := p.readQueryRow(ctx, userKey(uid), "SELECT user_id, password, username, nickname, email, avatar FROM tb_users WHERE user_id = ?", uid)
 	if err != nil {
 		return nil, err
 	}
@@ -444,8 +551,8 @@ func (p *DB) UserGetInfo(uid uint64) (user *types.UmUserInfo, err error) {
 	}, nil
 }
 
-func (p *DB) UserGetInfoByUsername(username string) (user *types.UmUserInfo, err error) {
-	row, err := p.queryRow("SELECT user_id, password, username, nickname, email, avatar FROM tb_users WHERE LOWER(username) = LOWER(?)", username)
+func (p *DB) UserGetInfoByUsername(ctx context.Context, username string) (user *types.UmUserInfo, err error) {
+	row, err := p.queryRow(ctx, "SELECT user_id, password, username, nickname, email, avatar FROM tb_users WHERE LOWER(username) = LOWER(?)", username)
 	if err != nil {
 		return nil, err
 	}
@@ -464,7 +571,7 @@ func (p *DB) UserGetInfoByUsername(username string) (user *types.UmUserInfo, err
 	}, nil
 }
 
-func (p *DB) UserUpdateInfo(uid uint64, nickname string, email string, avatar string, password string) (err error) {
+func (p *DB) UserUpdateInfo(ctx context.Context, uid uint64, nickname string, email string, avatar string, password string) (err error) {
 	var fields []string
 	var args []interface{}
 
@@ -498,16 +605,29 @@ func (p *DB) UserUpdateInfo(uid uint64, nickname string, email string, avatar st
 	args = append(args, uid)
 
 	// 执行 SQL 语句
-	_, err = p.sqlExec(query, args...)
+	_, err = p.sqlExec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
+	p.markWritten(userKey(uid))
 
 	return nil
 }
 
-func (p *DB) ContactGetList(uid uint64) (contactUidList []uint64, err error) {
-	rows, err := p.queryRows("SELECT contact_id FROM tb_user_contacts WHERE user_id = ?", uid)
+func (p *DB) UserUpdatePassHash(ctx context.Context, uid uint64, oldPassHash string, newPassHash string) (updated bool, err error) {
+	res, err := p.sqlExec(ctx, "UPDATE tb_users SET password = ? WHERE user_id = ? AND password = ?", newPassHash, uid, oldPassHash)
+	if err != nil {
+		return false, fmt.Errorf("sqlExec: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("RowsAffected: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (p *DB) ContactGetList(ctx context.Context, uid uint64) (contactUidList []uint64, err error) {
+	rows, err := p.readQueryRows(ctx, userKey(uid), "SELECT contact_id FROM tb_user_contacts WHERE user_id = ?", uid)
 	if err != nil {
 		return nil, err
 	}
@@ -525,8 +645,8 @@ func (p *DB) ContactGetList(uid uint64) (contactUidList []uint64, err error) {
 	return contactUidList, nil
 }
 
-func (p *DB) ContactGetRelation(uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, err error) {
-	row, err := p.queryRow("SELECT is_mutual_contact, remark_name FROM tb_user_contacts WHERE user_id = ? AND contact_id = ?", uid, contactUid)
+func (p *DB) ContactGetRelation(ctx context.Context, uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, err error) {
+	row, err := p.queryRow(ctx, "SELECT is_mutual_contact, remark_name FROM tb_user_contacts WHERE user_id = ? AND contact_id = ?", uid, contactUid)
 	if err != nil {
 		return false, "", fmt.Errorf("queryRow: %w", err)
 	}
@@ -541,10 +661,10 @@ func (p *DB) ContactGetRelation(uid uint64, contactUid uint64) (isMutualContact
 	return isMutualContact, remarkName, nil
 }
 
-func (p *DB) ContactAdd(uid uint64, contactUid uint64) error {
-	tx, err := p.db.Begin() // 开启事务
+func (p *DB) ContactAdd(ctx context.Context, uid uint64, contactUid uint64) error {
+	tx, err := p.beginTx(ctx) // 开启事务
 	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+		return fmt.Errorf("beginTx: %w", err)
 	}
 
 	defer func() {
@@ -557,21 +677,13 @@ func (p *DB) ContactAdd(uid uint64, contactUid uint64) error {
 	}()
 
 	// 插入或更新记录
-	_, err = p.sqlTxExec(tx, `
-		INSERT INTO tb_user_contacts (user_id, contact_id, is_mutual_contact)
-		VALUES (?, ?, true)
-		ON DUPLICATE KEY UPDATE is_mutual_contact = VALUES(is_mutual_contact)
-	`, uid, contactUid)
+	_, err = p.sqlTxExec(ctx, tx, p.upsertContactSql(), uid, contactUid)
 	if err != nil {
 		return fmt.Errorf("sqlTxExec: %w", err)
 	}
 
 	// 插入或更新反向关系
-	_, err = p.sqlTxExec(tx, `
-		INSERT INTO tb_user_contacts (user_id, contact_id, is_mutual_contact)
-		VALUES (?, ?, true)
-		ON DUPLICATE KEY UPDATE is_mutual_contact = VALUES(is_mutual_contact)
-	`, contactUid, uid)
+	_, err = p.sqlTxExec(ctx, tx, p.upsertContactSql(), contactUid, uid)
 	if err != nil {
 		return fmt.Errorf("sqlTxExec: %w", err)
 	}
@@ -581,57 +693,67 @@ func (p *DB) ContactAdd(uid uint64, contactUid uint64) error {
 	if err != nil {
 		return fmt.Errorf("sqlTxCommit: %w", err)
 	}
+	p.markWritten(userKey(uid), userKey(contactUid))
 
 	return nil
 }
 
-func (p *DB) ContactAccept(uid uint64, contactUid uint64) (error) {
-	err := p.ContactAdd(uid, contactUid)
+func (p *DB) ContactAccept(ctx context.Context, uid uint64, contactUid uint64) (error) {
+	err := p.ContactAdd(ctx, uid, contactUid)
 	if err != nil {
 		return fmt.Errorf("ContactAdd: %w", err)
 	}
 	// 在 tb_user_inbox 表中更新好友请求消息状态
-	_, err = p.sqlExec("UPDATE tb_user_inbox SET status = 1 WHERE user_id = ? AND sender_id = ? AND receiver_id = ? AND status = 0",
+	_, err = p.userShard(uid).sqlExec(ctx, "UPDATE tb_user_inbox SET status = 1 WHERE user_id = ? AND sender_id = ? AND receiver_id = ? AND status = 0",
 		uid, contactUid, uid)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
+	p.clearInboxTail(uid)
 	return nil
 }
 
-func (p *DB) ContactDel(uid uint64, contactUid uint64) (error) {
-	_, err := p.sqlExec("DELETE FROM tb_user_contacts WHERE user_id = ? AND contact_id = ?", uid, contactUid)
+func (p *DB) ContactDel(ctx context.Context, uid uint64, contactUid uint64) (error) {
+	defer p.markWritten(userKey(uid), userKey(contactUid))
+	_, err := p.sqlExec(ctx, "DELETE FROM tb_user_contacts WHERE user_id = ? AND contact_id = ?", uid, contactUid)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
-	_, err = p.sqlExec("DELETE FROM tb_user_contacts WHERE user_id = ? AND contact_id = ?", contactUid, uid)
-	if err != nil {
-		return fmt.Errorf("sqlExec: %w", err)
-	}
-	_, err = p.sqlExec("DELETE FROM tb_user_inbox WHERE user_id = ? AND sender_id = ? AND receiver_id = ?",
-		uid, contactUid, uid)
+	_, err = p.sqlExec(ctx, "DELETE FROM tb_user_contacts WHERE user_id = ? AND contact_id = ?", contactUid, uid)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
-	_, err = p.sqlExec("DELETE FROM tb_user_inbox WHERE user_id = ? AND sender_id = ? AND receiver_id = ?",
-		uid, uid, contactUid)
-	if err != nil {
-		return fmt.Errorf("sqlExec: %w", err)
+	// 收件箱和归档中的会话消息都删除
+	shard := p.userShard(uid)
+	for _, table := range inboxTables {
+		_, err = shard.sqlExec(ctx, "DELETE FROM "+table+" WHERE user_id = ? AND sender_id = ? AND receiver_id = ?",
+			uid, contactUid, uid)
+		if err != nil {
+			return fmt.Errorf("sqlExec: %w", err)
+		}
+		_, err = shard.sqlExec(ctx, "DELETE FROM "+table+" WHERE user_id = ? AND sender_id = ? AND receiver_id = ?",
+			uid, uid, contactUid)
+		if err != nil {
+			return fmt.Errorf("sqlExec: %w", err)
+		}
 	}
+	p.clearInboxTail(uid)
 	return nil
 }
 
-func (p *DB) ContactReject(uid uint64, contactUid uint64) (error) {
-	_, err := p.sqlExec("UPDATE tb_user_inbox SET status = 2 WHERE user_id = ? AND sender_id = ?  AND receiver_id = ? AND status = 0",
+func (p *DB) ContactReject(ctx context.Context, uid uint64, contactUid uint64) (error) {
+	_, err := p.userShard(uid).sqlExec(ctx, "UPDATE tb_user_inbox SET status = 2 WHERE user_id = ? AND sender_id = ?  AND receiver_id = ? AND status = 0",
 		uid, contactUid, uid)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
+	p.markWritten(userKey(uid))
+	p.clearInboxTail(uid)
 	return nil
 }
 
-func (p *DB) GroupGetList(uid uint64) (ConvIdList []uint64, err error) {
-	rows, err := p.queryRows("SELECT group_id FROM tb_group_members WHERE user_id = ?", uid)
+func (p *DB) GroupGetList(ctx context.Context, uid uint64) (ConvIdList []uint64, err error) {
+	rows, err := p.readQueryRows(ctx, userKey(uid), "SELECT group_id FROM tb_group_members WHERE user_id = ?", uid)
 	if err != nil {
 		return nil, fmt.Errorf("queryRows: %w", err)
 	}
@@ -649,8 +771,8 @@ func (p *DB) GroupGetList(uid uint64) (ConvIdList []uint64, err error) {
 	return ConvIdList, nil
 }
 
-func (p *DB) GroupGetInfo(groupId uint64) (groupInfo *types.UmGroupInfo, err error) {
-	row, err := p.queryRow("SELECT group_id, group_name, owner_id, avatar, mem_count, created_at FROM tb_groups WHERE group_id = ?", groupId)
+func (p *DB) GroupGetInfo(ctx context.Context, groupId uint64) (groupInfo *types.UmGroupInfo, err error) {
+	row, err := p.readQueryRow(ctx, groupKey(groupId), "SELECT group_id, group_name, owner_id, avatar, mem_count, created_at FROM tb_groups WHERE group_id = ?", groupId)
 	if err != nil {
 		return nil, fmt.Errorf("queryRow: %w", err)
 	}
@@ -669,7 +791,7 @@ func (p *DB) GroupGetInfo(groupId uint64) (groupInfo *types.UmGroupInfo, err err
 	}, nil
 }
 
-func (p *DB) GroupUpdateInfo(groupId uint64, groupName string, avatar string) (err error) {
+func (p *DB) GroupUpdateInfo(ctx context.Context, groupId uint64, groupName string, avatar string) (err error) {
 	// 动态构建 SQL 语句和参数列表
 	var fields []string
 	var args []interface{}
@@ -694,17 +816,18 @@ func (p *DB) GroupUpdateInfo(groupId uint64, groupName string, avatar string) (e
 	args = append(args, groupId)
 
 	// 执行 SQL 语句
-	_, err = p.sqlExec(query, args...)
+	_, err = p.sqlExec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
+	p.markWritten(groupKey(groupId))
 
 	return nil
 }
 
 
-func (p *DB) GroupCreate(uid uint64, groupName string) (ConvId uint64, err error) {
-	res, err := p.sqlExec("INSERT INTO tb_groups (group_name, owner_id, mem_count) VALUES (?, ?, 1)", groupName, uid)
+func (p *DB) GroupCreate(ctx context.Context, uid uint64, groupName string) (ConvId uint64, err error) {
+	res, err := p.sqlExec(ctx, "INSERT INTO tb_groups (group_name, owner_id, mem_count) VALUES (?, ?, 1)", groupName, uid)
 	if err != nil {
 		return 0, err
 	}
@@ -714,51 +837,77 @@ func (p *DB) GroupCreate(uid uint64, groupName string) (ConvId uint64, err error
 		return 0, err
 	}
 
-	_, err = p.sqlExec("INSERT INTO tb_group_members (group_id, user_id, role) VALUES (?, ?, 1)", groupID, uid)
+	_, err = p.sqlExec(ctx, "INSERT INTO tb_group_members (group_id, user_id, role) VALUES (?, ?, 1)", groupID, uid)
+	p.markWritten(userKey(uid), groupKey(uint64(groupID)))
 	return uint64(groupID), err
 }
 
-func (p *DB) GroupDelete(uid uint64, groupId uint64) (err error) {
-	isOwner, err := p.GroupIsOwner(groupId, uid)
+func (p *DB) GroupDelete(ctx context.Context, uid uint64, groupId uint64) (err error) {
+	isOwner, err := p.GroupIsOwner(ctx, groupId, uid)
 	if err != nil {
 		return fmt.Errorf("GroupIsOwner: %w", err)
 	}
 	if !isOwner {
 		return fmt.Errorf("uid is not the owner of the group")
 	}
+	defer p.markWritten(groupKey(groupId))
 	// 清空群成员
-	_, err = p.sqlExec("DELETE FROM tb_group_members WHERE group_id = ?", groupId)
+	_, err = p.sqlExec(ctx, "DELETE FROM tb_group_members WHERE group_id = ?", groupId)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
+	// 删除时间线，成员游标分布在各分片
+	for _, table := range []string{"tb_group_timeline", "tb_seq_id_group_timeline"} {
+		_, err = p.groupShard(groupId).sqlExec(ctx, "DELETE FROM "+table+" WHERE group_id = ?", groupId)
+		if err != nil {
+			return fmt.Errorf("sqlExec: %w", err)
+		}
+	}
+	err = p.execAllShards(ctx, "DELETE FROM tb_group_cursor WHERE group_id = ?", groupId)
+	if err != nil {
+		return fmt.Errorf("execAllShards: %w", err)
+	}
 	// 删除群聊
-	_, err = p.sqlExec("DELETE FROM tb_groups WHERE group_id = ?", groupId)
+	_, err = p.sqlExec(ctx, "DELETE FROM tb_groups WHERE group_id = ?", groupId)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
 	return nil
 }
 
-func (p *DB) GroupGetMemList(groupId uint64) (memUidList []uint64, err error) {
-	rows, err := p.queryRows("SELECT user_id FROM tb_group_members WHERE group_id = ?", groupId)
+func (p *DB) GroupGetMemList(ctx context.Context, groupId uint64) (memUidList []uint64, err error) {
+	rows, err := p.readQueryRows(ctx, groupKey(groupId), "SELECT user_id FROM tb_group_members WHERE group_id = ?", groupId)
 	if err != nil {
 		return nil, fmt.Errorf("queryRows: %w", err)
 	}
 	defer rows.Close()
+	return scanUids(rows)
+}
 
+// groupMemListFromPrimary 从主库读取群成员，扇出时用，刚入群的成员不会漏收消息
+func (p *DB) groupMemListFromPrimary(ctx context.Context, groupId uint64) (memUidList []uint64, err error) {
+	rows, err := p.queryRows(ctx, "SELECT user_id FROM tb_group_members WHERE group_id = ?", groupId)
+	if err != nil {
+		return nil, fmt.Errorf("queryRows: %w", err)
+	}
+	defer rows.Close()
+	return scanUids(rows)
+}
+
+func scanUids(rows *sql.Rows) (uids []uint64, err error) {
 	for rows.Next() {
 		var uid uint64
 		err := rows.Scan(&uid)
 		if err != nil {
 			return nil, err
 		}
-		memUidList = append(memUidList, uid)
+		uids = append(uids, uid)
 	}
-	return memUidList, nil
+	return uids, rows.Err()
 }
 
-func (p *DB) GroupGetAdminList(groupId uint64) (adminUid []uint64, err error) {
-	rows, err := p.queryRows("SELECT user_id FROM tb_group_members WHERE group_id = ? AND (role = 1 OR role = 2)", groupId)
+func (p *DB) GroupGetAdminList(ctx context.Context, groupId uint64) (adminUid []uint64, err error) {
+	rows, err := p.queryRows(ctx, "SELECT user_id FROM tb_group_members WHERE group_id = ? AND (role = 1 OR role = 2)", groupId)
 	if err != nil {
 		return nil, fmt.Errorf("queryRows: %w", err)
 	}
@@ -776,8 +925,8 @@ func (p *DB) GroupGetAdminList(groupId uint64) (adminUid []uint64, err error) {
 	return adminUid, nil
 }
 
-func (p *DB) GroupIsOwner(groupId uint64, uid uint64) (isOwner bool, err error) {
-	row, err := p.queryRow("SELECT COUNT(*) FROM tb_group_members WHERE group_id = ? AND user_id = ? AND role = 1", groupId, uid)
+func (p *DB) GroupIsOwner(ctx context.Context, groupId uint64, uid uint64) (isOwner bool, err error) {
+	row, err := p.queryRow(ctx, "SELECT COUNT(*) FROM tb_group_members WHERE group_id = ? AND user_id = ? AND role = 1", groupId, uid)
 	var count int
 	err = row.Scan(&count)
 	if err != nil {
@@ -786,8 +935,8 @@ func (p *DB) GroupIsOwner(groupId uint64, uid uint64) (isOwner bool, err error)
 	return count > 0,nil
 }
 
-func (p *DB) GroupIsAdmin(groupId uint64, uid uint64) (isAdmin bool, err error) {
-	row, err := p.queryRow("SELECT COUNT(*) FROM tb_group_members WHERE group_id = ? AND user_id = ? AND (role = 1 OR role = 2)", groupId, uid)
+func (p *DB) GroupIsAdmin(ctx context.Context, groupId uint64, uid uint64) (isAdmin bool, err error) {
+	row, err := p.queryRow(ctx, "SELECT COUNT(*) FROM tb_group_members WHERE group_id = ? AND user_id = ? AND (role = 1 OR role = 2)", groupId, uid)
 	var count int
 	err = row.Scan(&count)
 	if err != nil {
@@ -796,8 +945,8 @@ func (p *DB) GroupIsAdmin(groupId uint64, uid uint64) (isAdmin bool, err error)
 	return count > 0, nil
 }
 
-func (p *DB) GroupIsMem(groupId uint64, uid uint64) (inGroup bool, err error) {
-	row, err := p.queryRow("SELECT COUNT(*) FROM tb_group_members WHERE group_id = ? AND user_id = ?", groupId, uid)
+func (p *DB) GroupIsMem(ctx context.Context, groupId uint64, uid uint64) (inGroup bool, err error) {
+	row, err := p.queryRow(ctx, "SELECT COUNT(*) FROM tb_group_members WHERE group_id = ? AND user_id = ?", groupId, uid)
 	var count int
 	err = row.Scan(&count)
 	if err != nil {
@@ -806,253 +955,290 @@ func (p *DB) GroupIsMem(groupId uint64, uid uint64) (inGroup bool, err error) {
 	return count > 0, nil
 }
 
-func (p *DB) GroupClearMsg(groupId uint64, uid uint64) (err error) {
-	// 删除群聊消息
-	_, err = p.sqlExec("DELETE FROM tb_user_inbox WHERE user_id = ? AND group_id = ?", uid, groupId)
+func (p *DB) GroupClearMsg(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	defer p.markWritten(userKey(uid))
+	// 删除群聊消息，包括归档
+	for _, table := range inboxTables {
+		_, err = p.userShard(uid).sqlExec(ctx, "DELETE FROM "+table+" WHERE user_id = ? AND group_id = ?", uid, groupId)
+		if err != nil {
+			return fmt.Errorf("sqlExec: %w", err)
+		}
+	}
+	p.clearInboxTail(uid)
+	// 尚未同步的时间线消息也不再同步
+	err = p.resetGroupCursor(ctx, groupId, uid)
 	if err != nil {
-		return fmt.Errorf("sqlExec: %w", err)
+		return fmt.Errorf("resetGroupCursor: %w", err)
 	}
 	return nil
 }
 
-func (p *DB) GroupLeave(groupId uint64, uid uint64) (err error) {
+func (p *DB) GroupLeave(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	defer p.markWritten(userKey(uid), groupKey(groupId))
 	// 删除群成员
-	_, err = p.sqlExec("DELETE FROM tb_group_members WHERE group_id = ? AND user_id = ?", groupId, uid)
+	_, err = p.sqlExec(ctx, "DELETE FROM tb_group_members WHERE group_id = ? AND user_id = ?", groupId, uid)
 	if err != nil {
 		return fmt.Errorf("Member sqlExec: %w", err)
 	}
 	// 更新群成员数量
-	_, err = p.sqlExec("UPDATE tb_groups SET mem_count = mem_count - 1 WHERE group_id = ?", groupId)
+	_, err = p.sqlExec(ctx, "UPDATE tb_groups SET mem_count = mem_count - 1 WHERE group_id = ?", groupId)
 	if err != nil {
 		return fmt.Errorf("Count sqlExec: %w", err)
 	}
-	// 为此用户删除群聊消息
-	_, err = p.sqlExec("DELETE FROM tb_user_inbox WHERE user_id = ? AND group_id = ?", uid, groupId)
+	// 为此用户删除群聊消息，包括归档
+	for _, table := range inboxTables {
+		_, err = p.userShard(uid).sqlExec(ctx, "DELETE FROM "+table+" WHERE user_id = ? AND group_id = ?", uid, groupId)
+		if err != nil {
+			return fmt.Errorf("Inbox sqlExec: %w", err)
+		}
+	}
+	p.clearInboxTail(uid)
+	err = p.deleteGroupCursor(ctx, groupId, uid)
 	if err != nil {
-		return fmt.Errorf("Inbox sqlExec: %w", err)
+		return fmt.Errorf("deleteGroupCursor: %w", err)
 	}
 	return nil
 }
 
-func (p *DB) GroupAddMem(groupId uint64, uid uint64, role uint) (err error) {
+func (p *DB) GroupAddMem(ctx context.Context, groupId uint64, uid uint64, role uint) (err error) {
+	defer p.markWritten(userKey(uid), groupKey(groupId))
 	// 添加群成员
-	_, err = p.sqlExec("INSERT INTO tb_group_members (group_id, user_id, role) VALUES (?, ?, ?)", groupId, uid, role)
+	_, err = p.sqlExec(ctx, "INSERT INTO tb_group_members (group_id, user_id, role) VALUES (?, ?, ?)", groupId, uid, role)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
 	// 更新群成员数量
-	_, err = p.sqlExec("UPDATE tb_groups SET mem_count = mem_count + 1 WHERE group_id = ?", groupId)
+	_, err = p.sqlExec(ctx, "UPDATE tb_groups SET mem_count = mem_count + 1 WHERE group_id = ?", groupId)
 	if err != nil {
 		return fmt.Errorf("Count sqlExec: %w", err)
 	}
+	// 新成员不同步入群前的时间线消息
+	err = p.resetGroupCursor(ctx, groupId, uid)
+	if err != nil {
+		return fmt.Errorf("resetGroupCursor: %w", err)
+	}
 	return nil
 }
 
-func (p *DB) GroupDelMem(groupId uint64, uid uint64) (err error) {
+func (p *DB) GroupDelMem(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	defer p.markWritten(userKey(uid), groupKey(groupId))
 	// 删除群成员
-	_, err = p.sqlExec("DELETE FROM tb_group_members WHERE group_id = ? AND user_id = ?", groupId, uid)
+	_, err = p.sqlExec(ctx, "DELETE FROM tb_group_members WHERE group_id = ? AND user_id = ?", groupId, uid)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
-	if _, err = p.sqlExec("UPDATE tb_groups SET mem_count = mem_count - 1 WHERE group_id = ?", groupId); err != nil {
+	if _, err = p.sqlExec(ctx, "UPDATE tb_groups SET mem_count = mem_count - 1 WHERE group_id = ?", groupId); err != nil {
 		return fmt.Errorf("Count sqlExec: %w", err)
 	}
+	err = p.deleteGroupCursor(ctx, groupId, uid)
+	if err != nil {
+		return fmt.Errorf("deleteGroupCursor: %w", err)
+	}
 	return nil
 }
 
-func (p *DB) GroupAccept(groupId uint64, uid uint64) (err error) {
-	err = p.GroupAddMem(groupId, uid, 0)
+func (p *DB) GroupAccept(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	err = p.GroupAddMem(ctx, groupId, uid, 0)
 	if err != nil {
 		return fmt.Errorf("GroupAddMem: %w", err)
 	}
-	// 更新入群请求状态
-	_, err = p.sqlExec("UPDATE tb_user_inbox SET status = 1 WHERE group_id = ? AND sender_id = ?", groupId, uid)
+	// 更新入群请求状态，请求在各管理员的收件箱中
+	err = p.execAllShards(ctx, "UPDATE tb_user_inbox SET status = 1 WHERE group_id = ? AND sender_id = ?", groupId, uid)
 	if err != nil {
-		return fmt.Errorf("sqlExec: %w", err)
+		return fmt.Errorf("execAllShards: %w", err)
 	}
+	p.clearGroupRequestTail(ctx, groupId)
 	return nil
 }
 
-func (p *DB) GroupReject(groupId uint64, uid uint64) (err error) {
+func (p *DB) GroupReject(ctx context.Context, groupId uint64, uid uint64) (err error) {
 	// 在 tb_user_inbox 表中更新入群请求消息状态
-	_, err = p.sqlExec("UPDATE tb_user_inbox SET status = 2 WHERE group_id = ? AND sender_id = ?", groupId, uid)
+	err = p.execAllShards(ctx, "UPDATE tb_user_inbox SET status = 2 WHERE group_id = ? AND sender_id = ?", groupId, uid)
 	if err != nil {
-		return fmt.Errorf("sqlExec: %w", err)
+		return fmt.Errorf("execAllShards: %w", err)
 	}
+	p.clearGroupRequestTail(ctx, groupId)
 	return nil
 }
 
-func (p *DB) GroupIgnore(groupId uint64, uid uint64) (err error) {
+func (p *DB) GroupIgnore(ctx context.Context, groupId uint64, uid uint64) (err error) {
 	// 更新入群请求状态
-	_, err = p.sqlExec("UPDATE tb_user_inbox SET status = 3 WHERE group_id = ? AND sender_id = ?", groupId, uid)
+	err = p.execAllShards(ctx, "UPDATE tb_user_inbox SET status = 3 WHERE group_id = ? AND sender_id = ?", groupId, uid)
 	if err != nil {
-		return fmt.Errorf("sqlExec: %w", err)
+		return fmt.Errorf("execAllShards: %w", err)
 	}
+	p.clearGroupRequestTail(ctx, groupId)
 	return nil
 }
 
-func (p *DB) GroupUpdateMem(groupId uint64, uid uint64, role uint) (err error) {
+func (p *DB) GroupUpdateMem(ctx context.Context, groupId uint64, uid uint64, role uint) (err error) {
 	// 更新群成员
-	_, err = p.sqlExec("UPDATE tb_group_members SET role = ? WHERE group_id = ? AND user_id = ?", role, groupId, uid)
+	_, err = p.sqlExec(ctx, "UPDATE tb_group_members SET role = ? WHERE group_id = ? AND user_id = ?", role, groupId, uid)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
 	return nil
 }
 
-func (p *DB) ChatSendMsgToUser(uid uint64, convMsg types.ChatMsgOfConv) (err error) {
-	var receiverId interface{}
-	var groupId interface{}
-	if convMsg.ReceiverId.PeerIdType == types.EmPeerIdType_Uid {
-		receiverId = convMsg.ReceiverId.Uid
-		groupId = nil
-		if convMsg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
-			// 删除 sender 发向 receiver 的已读消息
-			_, err = p.sqlExec("DELETE FROM tb_user_inbox WHERE user_id = ? AND sender_id = ?  AND receiver_id = ? AND message_type = ? AND read_msg_id <= ?",
-				uid, convMsg.Msg.SenderUid, receiverId, gen_grpc.ChatMsgType_emChatMsgType_MarkRead, convMsg.Msg.ReadMsgId)
-			if err != nil {
-				Log.Warn("sqlExec: %s", err)
-			}
+// fanOutMsg 把消息写入多个用户的收件箱。
+// 接收者在同一分片时在一个事务中写入，任一步失败整体回滚，不会出现部分用户收到消息的情况；
+// 跨分片时经 outbox 写入，见 fanOutCrossShard
+func (p *DB) fanOutMsg(ctx context.Context, uids []uint64, convMsg types.ChatMsgOfConv) (err error) {
+	// 去重，同一个用户只写一份
+	var targets []uint64
+	seen := make(map[uint64]bool, len(uids))
+	for _, uid := range uids {
+		if !seen[uid] {
+			seen[uid] = true
+			targets = append(targets, uid)
 		}
-	} else {
-		groupId = convMsg.ReceiverId.GroupId
-		receiverId = nil
-		if convMsg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
-			// 删除 sender 发向 group 的已读消息
-			_, err = p.sqlExec("DELETE FROM tb_user_inbox WHERE user_id = ? AND sender_id = ?  AND group_id = ? AND message_type = ? AND read_msg_id <= ?",
-				uid, convMsg.Msg.SenderUid, groupId, gen_grpc.ChatMsgType_emChatMsgType_MarkRead, convMsg.Msg.ReadMsgId)
-			if err != nil {
-				Log.Warn("sqlExec: %s", err)
-			}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	groups := p.groupByShard(targets)
+	if len(groups) > 1 {
+		err = p.fanOutCrossShard(ctx, groups, convMsg)
+		if err != nil {
+			return fmt.Errorf("fanOutCrossShard: %w", err)
 		}
+		return nil
 	}
 
-	// 分配 seqId
-	var seqId uint64
-	seqId, err = p.AllocateSeqId(uid)
+	err = p.fanOutToShard(ctx, groups[0].shard, targets, convMsg, 0)
 	if err != nil {
-		return fmt.Errorf("AllocateSeqId: %w", err)
+		return fmt.Errorf("fanOutToShard: %w", err)
 	}
 
-	// 添加消息
-	_, err = p.sqlExec("INSERT INTO tb_user_inbox (user_id, seq_id, conv_msg_id, rand_msg_id, sender_id, receiver_id, group_id, content, message_type, read_msg_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-		uid, seqId, convMsg.ConvMsgId, convMsg.RandMsgId, convMsg.Msg.SenderUid, receiverId, groupId, convMsg.Msg.MsgContent, convMsg.Msg.MsgType, convMsg.Msg.ReadMsgId)
-	if err != nil {
-		return fmt.Errorf("user sqlExec: %w", err)
+	keys := make([]readKey, len(targets))
+	for i, uid := range targets {
+		keys[i] = userKey(uid)
 	}
+	p.markWritten(keys...)
+	return nil
+}
 
-	return err
+func (p *DB) ChatSendMsgToUser(ctx context.Context, uid uint64, convMsg types.ChatMsgOfConv) (err error) {
+	err = p.fanOutMsg(ctx, []uint64{uid}, convMsg)
+	if err != nil {
+		return fmt.Errorf("fanOutMsg: %w", err)
+	}
+	return nil
 }
 
-func (p *DB) ChatSendMsg(convMsg types.ChatMsgOfConv) (err error) {
+func (p *DB) ChatSendMsg(ctx context.Context, convMsg types.ChatMsgOfConv) (err error) {
+	var uids []uint64
 	if convMsg.ReceiverId.PeerIdType == types.EmPeerIdType_Uid {
 		// 分配 msgId
-		convMsg.ConvMsgId, err = p.AllocateChatSeqId(convMsg.Msg.SenderUid, convMsg.ReceiverId.Uid)
+		convMsg.ConvMsgId, err = p.AllocateChatSeqId(ctx, convMsg.Msg.SenderUid, convMsg.ReceiverId.Uid)
 		if err != nil {
 			return fmt.Errorf("AllocateChatSeqId: %w", err)
 		}
-
-		// 添加消息
-		err = p.ChatSendMsgToUser(convMsg.ReceiverId.Uid, convMsg)
-		if err != nil {
-			return fmt.Errorf("Receiver ChatSendMsgTo: %w", err)
-		}
-
-		// 也为发送者添加消息
-		err = p.ChatSendMsgToUser(convMsg.Msg.SenderUid, convMsg)
-		if err != nil {
-			return fmt.Errorf("Sender ChatSendMsgTo: %w", err)
-		}
-
+		// 接收者和发送者各一份
+		uids = []uint64{convMsg.ReceiverId.Uid, convMsg.Msg.SenderUid}
 	} else {
-		// 获取群员群员列表
-		memberList, err := p.GroupGetMemList(convMsg.ReceiverId.GroupId)
+		// 大群只写时间线
+		var readDiffusion bool
+		readDiffusion, err = p.isReadDiffusionGroup(ctx, convMsg.ReceiverId.GroupId)
 		if err != nil {
-			return fmt.Errorf("GroupGetMemList: %w", err)
+			return fmt.Errorf("isReadDiffusionGroup: %w", err)
+		}
+		if !readDiffusion {
+			// 获取群员列表
+			uids, err = p.groupMemListFromPrimary(ctx, convMsg.ReceiverId.GroupId)
+			if err != nil {
+				return fmt.Errorf("groupMemListFromPrimary: %w", err)
+			}
 		}
 
 		// 分配 msgId
-		convMsg.ConvMsgId, err = p.AllocateGroupSeqId(convMsg.ReceiverId.GroupId)
+		convMsg.ConvMsgId, err = p.AllocateGroupSeqId(ctx, convMsg.ReceiverId.GroupId)
 		if err != nil {
 			return fmt.Errorf("AllocateGroupSeqId: %w", err)
 		}
 
-		for _, memberUid := range memberList {
-			// 添加消息
-			err = p.ChatSendMsgToUser(memberUid, convMsg)
+		if readDiffusion {
+			err = p.appendGroupTimeline(ctx, convMsg)
 			if err != nil {
-				return fmt.Errorf("Group ChatSendMsgTo: %w", err)
+				return fmt.Errorf("appendGroupTimeline: %w", err)
 			}
+			return nil
 		}
 	}
 
-	return err
+	err = p.fanOutMsg(ctx, uids, convMsg)
+	if err != nil {
+		return fmt.Errorf("fanOutMsg: %w", err)
+	}
+	return nil
 }
 
-func (p *DB) ChatSendMsgToAdmins(convMsg types.ChatMsgOfConv) (err error) {
+func (p *DB) ChatSendMsgToAdmins(ctx context.Context, convMsg types.ChatMsgOfConv) (err error) {
 
 	if convMsg.ReceiverId.PeerIdType != types.EmPeerIdType_GroupId {
 		return fmt.Errorf("peer id type must be group id")
 	}
 
 	// 获取管理员列表
-	adminUidList, err := p.GroupGetAdminList(convMsg.ReceiverId.GroupId)
+	adminUidList, err := p.GroupGetAdminList(ctx, convMsg.ReceiverId.GroupId)
 	if err != nil {
 		return fmt.Errorf("GroupGetAdminList: %w", err)
 	}
 
 	// 分配 msgId
-	convMsg.ConvMsgId, err = p.AllocateGroupSeqId(convMsg.ReceiverId.GroupId)
+	convMsg.ConvMsgId, err = p.AllocateGroupSeqId(ctx, convMsg.ReceiverId.GroupId)
 	if err != nil {
 		return fmt.Errorf("AllocateGroupSeqId: %w", err)
 	}
 
-	for _, adminUid := range adminUidList {
-		// 分配 seqId
-		seqId, err := p.AllocateSeqId(adminUid)
-		if err != nil {
-			return fmt.Errorf("AllocateSeqId: %w", err)
-		}
-
-		// 添加消息
-		_, err = p.sqlExec( "INSERT INTO tb_user_inbox (user_id, seq_id, conv_msg_id, rand_msg_id, sender_id, receiver_id, group_id, content, message_type, read_msg_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-			adminUid, seqId, convMsg.ConvMsgId, convMsg.RandMsgId, convMsg.Msg.SenderUid, nil, convMsg.ReceiverId.GroupId, convMsg.Msg.MsgContent, convMsg.Msg.MsgType, convMsg.Msg.ReadMsgId)
-		if err != nil {
-			return fmt.Errorf("sqlExec: %w", err)
-		}
+	err = p.fanOutMsg(ctx, adminUidList, convMsg)
+	if err != nil {
+		return fmt.Errorf("fanOutMsg: %w", err)
 	}
-
 	return nil
 }
 
-func (p *DB) ChatMarkRead(uid uint64, contactId uint64, readMsgId uint64) (err error) {
-	_, err = p.sqlExec("UPDATE tb_user_inbox SET is_read = 1 WHERE user_id = ? AND (sender_id = ? AND receiver_id = ?) AND conv_msg_id <= ? AND is_read = 0",
+func (p *DB) ChatMarkRead(ctx context.Context, uid uint64, contactId uint64, readMsgId uint64) (err error) {
+	defer p.markWritten(userKey(uid), userKey(contactId))
+	_, err = p.userShard(uid).sqlExec(ctx, "UPDATE tb_user_inbox SET is_read = 1 WHERE user_id = ? AND (sender_id = ? AND receiver_id = ?) AND conv_msg_id <= ? AND is_read = 0",
 		uid, contactId, uid, readMsgId)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
-	_, err = p.sqlExec("UPDATE tb_user_inbox SET is_read = 1 WHERE user_id = ? AND (sender_id = ? AND receiver_id = ?) AND conv_msg_id <= ? AND is_read = 0",
+	_, err = p.userShard(contactId).sqlExec(ctx, "UPDATE tb_user_inbox SET is_read = 1 WHERE user_id = ? AND (sender_id = ? AND receiver_id = ?) AND conv_msg_id <= ? AND is_read = 0",
 		contactId, contactId, uid, readMsgId)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
+	p.clearInboxTail(uid, contactId)
 	return nil
 }
 
-func (p *DB) ChatReadGroupMsg(uid uint64, groupId uint64, readMsgId uint64) (err error) {
-	_, err = p.sqlExec("UPDATE tb_user_inbox SET is_read = 1 WHERE user_id = ? AND group_id = ? AND conv_msg_id <= ? AND is_read = 0", uid, groupId, readMsgId)
+func (p *DB) ChatReadGroupMsg(ctx context.Context, uid uint64, groupId uint64, readMsgId uint64) (err error) {
+	_, err = p.userShard(uid).sqlExec(ctx, "UPDATE tb_user_inbox SET is_read = 1 WHERE user_id = ? AND group_id = ? AND conv_msg_id <= ? AND is_read = 0", uid, groupId, readMsgId)
 	if err != nil {
 		return fmt.Errorf("sqlExec: %w", err)
 	}
+	p.markWritten(userKey(uid))
+	p.clearInboxTail(uid)
 	return nil
 }
 
-func (p *DB) ChatGetMsgList(uid uint64, seqId uint64) (msgs []types.ChatMsgOfConv, err error) {
+func (p *DB) ChatSyncInbox(ctx context.Context, uid uint64) (err error) {
+	err = p.syncGroupTimelines(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("syncGroupTimelines: %w", err)
+	}
+	return nil
+}
+
+func (p *DB) ChatGetMsgList(ctx context.Context, uid uint64, seqId uint64, limit int) (msgs []types.ChatMsgOfConv, err error) {
 	// 查询。按 seqId 升序排列
-	rows, err := p.queryRows(`
-		SELECT user_id, seq_id, conv_msg_id, rand_msg_id, sender_id, receiver_id, group_id, content, message_type, read_msg_id, sent_at, is_read, status
-		FROM tb_user_inbox WHERE user_id = ? AND seq_id > ? ORDER BY seq_id ASC`,
-		uid, seqId,
+	rows, err := p.userShard(uid).readQueryRows(ctx, userKey(uid), `
+		SELECT `+inboxMsgColumns+`
+		FROM tb_user_inbox WHERE user_id = ? AND seq_id > ? ORDER BY seq_id ASC LIMIT ?`,
+		uid, seqId, limit,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -1063,8 +1249,17 @@ func (p *DB) ChatGetMsgList(uid uint64, seqId uint64) (msgs []types.ChatMsgOfCon
 	}
 	defer rows.Close()
 
+	return scanInboxMsgs(rows)
+}
+
+// 收件箱表和归档表，删除会话消息时两者都要处理
+var inboxTables = []string{"tb_user_inbox", "tb_user_inbox_archive"}
+
+// inboxMsgColumns 与 scanInboxMsgs 的扫描顺序一致
+const inboxMsgColumns = "user_id, seq_id, conv_msg_id, rand_msg_id, sender_id, receiver_id, group_id, content, message_type, read_msg_id, sent_at, is_read, status"
+
+func scanInboxMsgs(rows *sql.Rows) (msgs []types.ChatMsgOfConv, err error) {
 	for rows.Next() {
-		var msg types.ChatMsgOfConv
 		var rowMsg InboxMsg
 		err = rows.Scan(
 			&rowMsg.UserID,
@@ -1081,13 +1276,87 @@ func (p *DB) ChatGetMsgList(uid uint64, seqId uint64) (msgs []types.ChatMsgOfCon
 			&rowMsg.IsRead,
 			&rowMsg.Status,
 		)
-		msg, err = convertDbMsgToChatMsgOfConv(rowMsg)
-
+		if err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		msg, err := convertDbMsgToChatMsgOfConv(rowMsg)
 		if err != nil {
 			return nil, err
 		}
 		msgs = append(msgs, msg)
 	}
+	return msgs, rows.Err()
+}
+
+// queryHistory 从收件箱或归档表中按 seqId 倒序读取会话消息，返回升序结果
+func (p *DB) queryHistory(ctx context.Context, table string, uid uint64, peerId types.PeerId, beforeSeqId uint64, limit int) (msgs []types.ChatMsgOfConv, err error) {
+	// 已读消息不属于会话内容
+	cond := "message_type <> ?"
+	condArgs := []interface{}{gen_grpc.ChatMsgType_emChatMsgType_MarkRead}
+	if beforeSeqId != 0 {
+		cond += " AND seq_id < ?"
+		condArgs = append(condArgs, beforeSeqId)
+	}
+	order := " ORDER BY seq_id DESC LIMIT ?"
+
+	var query string
+	var args []interface{}
+	if peerId.PeerIdType == types.EmPeerIdType_GroupId {
+		query = "SELECT " + inboxMsgColumns + " FROM " + table + " WHERE user_id = ? AND group_id = ? AND " + cond + order
+		args = append(append([]interface{}{uid, peerId.GroupId}, condArgs...), limit)
+	} else {
+		// 双方发出的消息各走一次 (user_id, sender_id, receiver_id, seq_id) 索引，再合并
+		branch := "SELECT " + inboxMsgColumns + " FROM " + table + " WHERE user_id = ? AND sender_id = ? AND receiver_id = ? AND " + cond + order
+		query = branch
+		args = append(append([]interface{}{uid, uid, peerId.Uid}, condArgs...), limit)
+		if peerId.Uid != uid {
+			query = "SELECT * FROM (" + branch + ") AS sent UNION ALL SELECT * FROM (" + branch + ") AS received" + order
+			args = append(args, uid, peerId.Uid, uid)
+			args = append(append(args, condArgs...), limit, limit)
+		}
+	}
+
+	rows, err := p.userShard(uid).readQueryRows(ctx, userKey(uid), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("readQueryRows: %w", err)
+	}
+	defer rows.Close()
 
+	msgs, err = scanInboxMsgs(rows)
+	if err != nil {
+		return nil, err
+	}
+	// 倒序查询，返回时改为升序
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
 	return msgs, nil
 }
+
+func (p *DB) ChatGetHistory(ctx context.Context, uid uint64, peerId types.PeerId, beforeSeqId uint64, limit int) (msgs []types.ChatMsgOfConv, err error) {
+	if peerId.PeerIdType == types.EmPeerIdType_GroupId {
+		// 先同步读扩散群的新消息
+		err = p.syncGroupTimelines(ctx, uid)
+		if err != nil {
+			return nil, fmt.Errorf("syncGroupTimelines: %w", err)
+		}
+	}
+
+	msgs, err = p.queryHistory(ctx, "tb_user_inbox", uid, peerId, beforeSeqId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("queryHistory: %w", err)
+	}
+	if len(msgs) >= limit {
+		return msgs, nil
+	}
+
+	// 收件箱中不够时接着读归档。归档按 seqId 从小到大移出，归档中的 seqId 都小于收件箱中的
+	if len(msgs) > 0 {
+		beforeSeqId = msgs[0].SeqId
+	}
+	archived, err := p.queryHistory(ctx, "tb_user_inbox_archive", uid, peerId, beforeSeqId, limit-len(msgs))
+	if err != nil {
+		return nil, fmt.Errorf("archive queryHistory: %w", err)
+	}
+	return append(archived, msgs...), nil
+}
@@ -0,0 +1,138 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"social_server/src/app/common/types"
+	"social_server/src/gen/grpc"
+	"testing"
+)
+
+// TestChatGetHistory 按 seqId 向前翻页，convMsgId 与写入顺序不一致时也不乱序、不遗漏，并从收件箱接着读归档
+func TestChatGetHistory(t *testing.T) {
+	storages := []struct {
+		name       string
+		newStorage func(t *testing.T) Storage
+	}{
+		{"memory", func(t *testing.T) Storage {
+			return NewMemStorage()
+		}},
+		{"sqlite", func(t *testing.T) Storage {
+			return newTestSqliteDB(t, filepath.Join(t.TempDir(), "history.db"), "1")
+		}},
+	}
+	for _, tc := range storages {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := tc.newStorage(t)
+			ctx := context.Background()
+
+			var uids []uint64
+			for _, username := range []string{"alice", "bob", "carol"} {
+				uid, err := storage.UserRegister(ctx, &types.UmRegisterParam{Username: username, Passwd: "x", Email: username + "@example.com"})
+				if err != nil {
+					t.Fatalf("UserRegister: %v", err)
+				}
+				uids = append(uids, uid)
+			}
+			alice, bob, carol := uids[0], uids[1], uids[2]
+			groupId, err := storage.GroupCreate(ctx, alice, "g")
+			if err != nil {
+				t.Fatalf("GroupCreate: %v", err)
+			}
+			err = storage.GroupAddMem(ctx, groupId, bob, 0)
+			if err != nil {
+				t.Fatalf("GroupAddMem: %v", err)
+			}
+
+			toUser := func(sender uint64, receiver uint64, convMsgId uint64, content string) {
+				t.Helper()
+				err := storage.ChatSendMsgToUser(ctx, alice, types.ChatMsgOfConv{
+					ReceiverId: types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: receiver},
+					ConvMsgId:  convMsgId,
+					Msg:        types.ChatMsg{SenderUid: sender, MsgType: gen_grpc.ChatMsgType_emChatMsgType_Text, MsgContent: content},
+				})
+				if err != nil {
+					t.Fatalf("ChatSendMsgToUser: %v", err)
+				}
+			}
+			toGroup := func(content string) {
+				t.Helper()
+				err := storage.ChatSendMsg(ctx, types.ChatMsgOfConv{
+					ReceiverId: types.PeerId{PeerIdType: types.EmPeerIdType_GroupId, GroupId: groupId},
+					Msg:        types.ChatMsg{SenderUid: bob, MsgType: gen_grpc.ChatMsgType_emChatMsgType_Text, MsgContent: content},
+				})
+				if err != nil {
+					t.Fatalf("ChatSendMsg: %v", err)
+				}
+			}
+			// 不同节点租用的号段交错，convMsgId 与写入 alice 收件箱的顺序不一致
+			toUser(alice, bob, 2, "d1")
+			toUser(bob, alice, 1, "d2")
+			toGroup("g1")
+			toUser(alice, bob, 4, "d3")
+			toUser(carol, alice, 1, "c1")
+			toUser(bob, alice, 3, "d4")
+			toGroup("g2")
+			toUser(alice, bob, 6, "d5")
+			toUser(bob, alice, 5, "d6")
+			toGroup("g3")
+
+			// 只保留最新的 5 条，d1 到 d3 及 g1 移到归档
+			_, err = storage.ArchiveInbox(ctx, InboxRetention{MaxMsgsPerUser: 5})
+			if err != nil {
+				t.Fatalf("ArchiveInbox: %v", err)
+			}
+
+			readAll := func(peerId types.PeerId, limit int) (contents []string) {
+				t.Helper()
+				var beforeSeqId uint64
+				for page := 0; ; page++ {
+					msgs, err := storage.ChatGetHistory(ctx, alice, peerId, beforeSeqId, limit)
+					if err != nil {
+						t.Fatalf("ChatGetHistory: %v", err)
+					}
+					if len(msgs) > limit {
+						t.Fatalf("got %d messages, want at most %d", len(msgs), limit)
+					}
+					if len(msgs) == 0 {
+						return contents
+					}
+					var pageContents []string
+					for i, msg := range msgs {
+						if beforeSeqId != 0 && msg.SeqId >= beforeSeqId {
+							t.Fatalf("page %d: seqId %d not before %d", page, msg.SeqId, beforeSeqId)
+						}
+						if i > 0 && msg.SeqId <= msgs[i-1].SeqId {
+							t.Fatalf("page %d: seqIds %d, %d not ascending", page, msgs[i-1].SeqId, msg.SeqId)
+						}
+						pageContents = append(pageContents, msg.Msg.MsgContent)
+					}
+					contents = append(pageContents, contents...)
+					beforeSeqId = msgs[0].SeqId
+				}
+			}
+
+			direct := types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: bob}
+			group := types.PeerId{PeerIdType: types.EmPeerIdType_GroupId, GroupId: groupId}
+			tests := []struct {
+				peerId types.PeerId
+				limit  int
+				want   []string
+			}{
+				{direct, 2, []string{"d1", "d2", "d3", "d4", "d5", "d6"}},
+				{direct, 4, []string{"d1", "d2", "d3", "d4", "d5", "d6"}},
+				{direct, 10, []string{"d1", "d2", "d3", "d4", "d5", "d6"}},
+				{types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: carol}, 2, []string{"c1"}},
+				{group, 1, []string{"g1", "g2", "g3"}},
+				{group, 2, []string{"g1", "g2", "g3"}},
+			}
+			for _, tt := range tests {
+				got := readAll(tt.peerId, tt.limit)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("history of %+v with limit %d: %v, want %v", tt.peerId, tt.limit, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"social_server/src/app/common/types"
+	. "social_server/src/utils/log"
+	"strings"
+	"time"
+)
+
+// 收件箱尾部缓存：
+// 每个用户在 Redis 中有一个有序集合，按 seqId 保存最近的若干条收件箱消息，
+// 另有 floor 表示集合包含了 seqId 大于 floor 的全部消息。拉取 floor 之后的消息直接读缓存，更早的范围回源数据库。
+// 扇出事务提交后把新消息追加到集合；事务进行期间标记写入中，缓存不命中，避免并发扇出乱序追加时读到缺消息的缓存。
+// 收件箱消息被修改或删除时清除缓存。回源结果读到末尾时写回缓存，写回前缓存被清除或有消息未能追加则放弃写回
+
+const (
+	inboxTailTTL = time.Hour
+	// inboxTailPendingTTL 写入中标记的有效期，扇出中途进程退出时最多这么久不走缓存
+	inboxTailPendingTTL = time.Minute
+	// inboxTailVersionTTL 没有缓存时版本号只需比进行中的回源活得久
+	inboxTailVersionTTL = time.Minute
+)
+
+// inboxTailKeys 有序集合、元数据（floor 和版本号）、写入中计数。同一用户的 key 在同一个哈希槽
+func inboxTailKeys(uid uint64) []string {
+	return []string{
+		fmt.Sprintf("inbox:tail:{%d}", uid),
+		fmt.Sprintf("inbox:tail:meta:{%d}", uid),
+		fmt.Sprintf("inbox:tail:pending:{%d}", uid),
+	}
+}
+
+// 超出条数上限时删除最早的消息并抬高 floor，然后刷新过期时间。ARGV[3] 为条数上限，ARGV[4] 为过期秒数
+const inboxTailTrimLua = `
+local excess = redis.call('ZCARD', KEYS[1]) - tonumber(ARGV[3])
+if excess > 0 then
+	local top = redis.call('ZRANGE', KEYS[1], excess - 1, excess - 1, 'WITHSCORES')
+	redis.call('ZREMRANGEBYRANK', KEYS[1], 0, excess - 1)
+	if tonumber(top[2]) > floor then
+		floor = tonumber(top[2])
+	end
+end
+redis.call('HSET', KEYS[2], 'floor', string.format('%d', floor))
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+redis.call('EXPIRE', KEYS[2], ARGV[4])
+return 1
+`
+
+// 读取 ARGV[1] 之后的最多 ARGV[2] 条消息。
+// 返回 {1, 0, msgs} 命中；{0, version} 未命中，version 非 0 时可以写回
+var inboxTailReadScript = redis.NewScript(`
+local version = tonumber(redis.call('HGET', KEYS[2], 'version') or '0')
+local floor = redis.call('HGET', KEYS[2], 'floor')
+if not floor then
+	return {0, version + 1}
+end
+if tonumber(redis.call('GET', KEYS[3]) or '0') > 0 or tonumber(ARGV[1]) < tonumber(floor) then
+	return {0, 0}
+end
+return {1, 0, redis.call('ZRANGEBYSCORE', KEYS[1], '(' .. ARGV[1], '+inf', 'LIMIT', 0, ARGV[2])}
+`)
+
+// 写回回源结果。ARGV[1] 为读取时的版本，ARGV[2] 为 floor，之后为 seqId 和消息
+var inboxTailFillScript = redis.NewScript(`
+if tonumber(redis.call('HGET', KEYS[2], 'version') or '0') + 1 ~= tonumber(ARGV[1]) then
+	return 0
+end
+for i = 5, #ARGV, 2 do
+	if redis.call('ZCOUNT', KEYS[1], ARGV[i], ARGV[i]) == 0 then
+		redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i + 1])
+	end
+end
+local floor = tonumber(ARGV[2])
+local old = redis.call('HGET', KEYS[2], 'floor')
+if old and tonumber(old) < floor then
+	floor = tonumber(old)
+end
+` + inboxTailTrimLua)
+
+// 结束写入中标记并追加消息。ARGV[1] 为消息条数，为 0 时只结束标记；
+// ARGV[2] 为超出条数上限没有发送的消息中最大的 seqId，没有时为 0；ARGV[5] 为没有缓存时版本号的过期秒数，之后为 seqId 和消息
+var inboxTailAppendScript = redis.NewScript(`
+if redis.call('DECR', KEYS[3]) <= 0 then
+	redis.call('DEL', KEYS[3])
+end
+if tonumber(ARGV[1]) == 0 then
+	return 0
+end
+local floor = redis.call('HGET', KEYS[2], 'floor')
+if not floor then
+	-- 还没有缓存，作废进行中的回源，回源可能没读到这条消息
+	redis.call('HINCRBY', KEYS[2], 'version', 1)
+	redis.call('EXPIRE', KEYS[2], ARGV[5])
+	return 0
+end
+floor = tonumber(floor)
+local skipped = tonumber(ARGV[2])
+if skipped > floor then
+	redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', skipped)
+	floor = skipped
+end
+for i = 6, #ARGV, 2 do
+	redis.call('ZREMRANGEBYSCORE', KEYS[1], ARGV[i], ARGV[i])
+	redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+` + inboxTailTrimLua)
+
+func (p *Cache) GetChatMsgList(ctx context.Context, uid uint64, seqId uint64, limit int) (msgs []types.ChatMsgOfConv, fillVersion uint64, err error) {
+	keys := inboxTailKeys(uid)
+	if p.inboxTailSize == 0 {
+		return nil, 0, &CacheNotFoundError{Key: keys[0]}
+	}
+	res, err := inboxTailReadScript.Run(ctx, p.client, keys, seqId, limit).Slice()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(res) < 2 {
+		return nil, 0, fmt.Errorf("unexpected script result: %v", res)
+	}
+	hit, _ := res[0].(int64)
+	if hit == 0 {
+		fillVersion, _ := res[1].(int64)
+		return nil, uint64(fillVersion), &CacheNotFoundError{Key: keys[0]}
+	}
+
+	members, _ := res[2].([]interface{})
+	for _, member := range members {
+		var msg types.ChatMsgOfConv
+		err = json.Unmarshal([]byte(member.(string)), &msg)
+		if err != nil {
+			return nil, 0, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, 0, nil
+}
+
+// CacheChatMsgList 写回 seqId 之后的全部消息，fillVersion 为未命中时返回的版本
+func (p *Cache) CacheChatMsgList(ctx context.Context, uid uint64, seqId uint64, fillVersion uint64, msgs []types.ChatMsgOfConv) (err error) {
+	if p.inboxTailSize == 0 || fillVersion == 0 {
+		return nil
+	}
+	floor := seqId
+	if int64(len(msgs)) > p.inboxTailSize {
+		floor = msgs[int64(len(msgs))-p.inboxTailSize-1].SeqId
+		msgs = msgs[int64(len(msgs))-p.inboxTailSize:]
+	}
+
+	args := make([]interface{}, 0, 4+2*len(msgs))
+	args = append(args, fillVersion, floor, p.inboxTailSize, int64(inboxTailTTL/time.Second))
+	for _, msg := range msgs {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		args = append(args, msg.SeqId, data)
+	}
+	return inboxTailFillScript.Run(ctx, p.client, inboxTailKeys(uid), args...).Err()
+}
+
+// BeginAppendChatMsg 标记 uids 的收件箱正在写入，EndAppendChatMsg 之前缓存不命中
+func (p *Cache) BeginAppendChatMsg(ctx context.Context, uids []uint64) (err error) {
+	if p.inboxTailSize == 0 {
+		return nil
+	}
+	_, err = p.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, uid := range uids {
+			pendingKey := inboxTailKeys(uid)[2]
+			pipe.Incr(ctx, pendingKey)
+			pipe.Expire(ctx, pendingKey, inboxTailPendingTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// EndAppendChatMsg 结束写入中标记，并把各用户新写入的消息追加到已有的缓存。msgs 为 nil 时只结束标记。
+// 每个用户的消息按 seqId 升序，超出条数上限的部分不发送，直接抬高 floor
+func (p *Cache) EndAppendChatMsg(ctx context.Context, uids []uint64, msgs map[uint64][]types.ChatMsgOfConv) (err error) {
+	if p.inboxTailSize == 0 {
+		return nil
+	}
+	argsOf := make([][]interface{}, len(uids))
+	for i, uid := range uids {
+		userMsgs := msgs[uid]
+		var skipped uint64
+		if int64(len(userMsgs)) > p.inboxTailSize {
+			skipped = userMsgs[int64(len(userMsgs))-p.inboxTailSize-1].SeqId
+			userMsgs = userMsgs[int64(len(userMsgs))-p.inboxTailSize:]
+		}
+		args := make([]interface{}, 0, 5+2*len(userMsgs))
+		args = append(args, len(userMsgs), skipped, p.inboxTailSize, int64(inboxTailTTL/time.Second), int64(inboxTailVersionTTL/time.Second))
+		for _, msg := range userMsgs {
+			data, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			args = append(args, msg.SeqId, data)
+		}
+		argsOf[i] = args
+	}
+
+	run := func(eval func(pipe redis.Pipeliner, keys []string, args ...interface{})) error {
+		_, err := p.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, uid := range uids {
+				eval(pipe, inboxTailKeys(uid), argsOf[i]...)
+			}
+			return nil
+		})
+		return err
+	}
+	err = run(func(pipe redis.Pipeliner, keys []string, args ...interface{}) {
+		inboxTailAppendScript.EvalSha(ctx, pipe, keys, args...)
+	})
+	if err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT ") {
+		// 脚本未加载时整个管道都不会执行，改用 EVAL 重发
+		err = run(func(pipe redis.Pipeliner, keys []string, args ...interface{}) {
+			inboxTailAppendScript.Eval(ctx, pipe, keys, args...)
+		})
+	}
+	return err
+}
+
+// ClearCacheChatMsgList 清除 uids 的收件箱尾部缓存，并作废进行中的回源
+func (p *Cache) ClearCacheChatMsgList(ctx context.Context, uids []uint64) (err error) {
+	if p.inboxTailSize == 0 {
+		return nil
+	}
+	_, err = p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, uid := range uids {
+			keys := inboxTailKeys(uid)
+			pipe.Del(ctx, keys[0])
+			pipe.HDel(ctx, keys[1], "floor")
+			pipe.HIncrBy(ctx, keys[1], "version", 1)
+			pipe.Expire(ctx, keys[1], inboxTailVersionTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// SetInboxTailCache 设置收件箱尾部缓存，各分片共用
+func (p *DB) SetInboxTailCache(tail InboxTailCache) {
+	p.inboxTail = tail
+	for _, s := range p.shards {
+		s.inboxTail = tail
+	}
+}
+
+// inboxAppend 一次收件箱写入对尾部缓存的标记
+type inboxAppend struct {
+	tail  InboxTailCache
+	uids  []uint64
+	begun bool
+}
+
+// beginInboxAppend 在写入 uids 的收件箱之前调用，写入结束前缓存不命中
+func (p *DB) beginInboxAppend(ctx context.Context, uids []uint64) *inboxAppend {
+	a := &inboxAppend{tail: p.inboxTail, uids: uids}
+	if a.tail == nil {
+		return a
+	}
+	err := a.tail.BeginAppendChatMsg(ctx, uids)
+	if err != nil {
+		Log.Error("BeginAppendChatMsg: %v", err)
+		return a
+	}
+	a.begun = true
+	return a
+}
+
+// end 写入结束，把各用户新写入的消息追加到缓存。msgs 为 nil 表示没有写入
+func (a *inboxAppend) end(msgs map[uint64][]types.ChatMsgOfConv) {
+	if a.tail == nil {
+		return
+	}
+	// 写入已提交，不随请求取消
+	ctx := context.Background()
+	if !a.begun {
+		// 没能标记写入中，缓存可能已被并发回源写回，只能清除
+		if msgs != nil {
+			clearInboxTail(ctx, a.tail, a.uids)
+		}
+		return
+	}
+	err := a.tail.EndAppendChatMsg(ctx, a.uids, msgs)
+	if err != nil {
+		Log.Error("EndAppendChatMsg: %v", err)
+		clearInboxTail(ctx, a.tail, a.uids)
+	}
+}
+
+// endClear 写入结束，收件箱中有消息被删除，清除缓存
+func (a *inboxAppend) endClear() {
+	if a.tail == nil {
+		return
+	}
+	ctx := context.Background()
+	// 先清除再结束标记，中间不会读到旧缓存
+	clearInboxTail(ctx, a.tail, a.uids)
+	if a.begun {
+		err := a.tail.EndAppendChatMsg(ctx, a.uids, nil)
+		if err != nil {
+			Log.Error("EndAppendChatMsg: %v", err)
+		}
+	}
+}
+
+// clearInboxTail 收件箱消息被修改或删除后清除缓存。写入已提交，不随请求取消
+func (p *DB) clearInboxTail(uids ...uint64) {
+	if p.inboxTail == nil {
+		return
+	}
+	clearInboxTail(context.Background(), p.inboxTail, uids)
+}
+
+func clearInboxTail(ctx context.Context, tail InboxTailCache, uids []uint64) {
+	err := tail.ClearCacheChatMsgList(ctx, uids)
+	if err != nil {
+		Log.Error("ClearCacheChatMsgList: %v", err)
+	}
+}
+
+// clearGroupRequestTail 入群请求状态更新后清除管理员的缓存，请求在各管理员的收件箱中
+func (p *DB) clearGroupRequestTail(ctx context.Context, groupId uint64) {
+	if p.inboxTail == nil {
+		return
+	}
+	adminUids, err := p.GroupGetAdminList(ctx, groupId)
+	if err != nil {
+		Log.Error("GroupGetAdminList: %v", err)
+		return
+	}
+	p.clearInboxTail(adminUids...)
+}
+
+// inboxTailMsg 扇出写入 uid 收件箱的消息，与从数据库读出的一致
+func inboxTailMsg(convMsg types.ChatMsgOfConv, seqId uint64, sentAt time.Time) types.ChatMsgOfConv {
+	msg := convMsg
+	msg.SeqId = seqId
+	msg.Msg.SentTsMs = uint64(sentAt.UnixNano() / 1e6)
+	msg.IsRead = false
+	msg.Status = 0
+	return msg
+}
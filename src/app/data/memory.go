@@ -0,0 +1,1253 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"social_server/src/app/common/proj_err"
+	"social_server/src/app/common/types"
+	"social_server/src/gen/grpc"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memContact struct {
+	isMutualContact bool
+	remarkName      string
+	createdAt       time.Time
+}
+
+type memGroup struct {
+	group   ChatGroup
+	members map[uint64]*ChatGroupMember
+}
+
+// MemStorage 进程内存储，语义与 DB 保持一致。用于开发和测试，进程退出后数据丢失
+type MemStorage struct {
+	mu sync.Mutex
+
+	lastUid     uint64
+	lastGroupId uint64
+
+	users    map[uint64]*User
+	contacts map[uint64]map[uint64]*memContact
+	groups   map[uint64]*memGroup
+	inbox    map[uint64][]*InboxMsg
+	// 超出保留策略的收件箱消息
+	archive map[uint64][]*InboxMsg
+
+	// 读扩散群的时间线，SeqID 为时间线 seqId；游标 key 为 {groupId, uid}
+	readDiffusionThreshold uint64
+	timelines              map[uint64][]*InboxMsg
+	groupCursors           map[[2]uint64]uint64
+
+	// 下一个可分配的 seqId
+	userSeqIds  map[uint64]uint64
+	chatSeqIds  map[[2]uint64]uint64
+	groupSeqIds map[uint64]uint64
+	// 有此 key 的群即为读扩散模式
+	timelineSeqIds map[uint64]uint64
+}
+
+func NewMemStorage() *MemStorage {
+	return &MemStorage{
+		users:       make(map[uint64]*User),
+		contacts:    make(map[uint64]map[uint64]*memContact),
+		groups:      make(map[uint64]*memGroup),
+		inbox:       make(map[uint64][]*InboxMsg),
+		archive:     make(map[uint64][]*InboxMsg),
+		userSeqIds:  make(map[uint64]uint64),
+		chatSeqIds:  make(map[[2]uint64]uint64),
+		groupSeqIds: make(map[uint64]uint64),
+
+		readDiffusionThreshold: groupReadDiffusionThreshold(),
+		timelines:              make(map[uint64][]*InboxMsg),
+		groupCursors:           make(map[[2]uint64]uint64),
+		timelineSeqIds:         make(map[uint64]uint64),
+	}
+}
+
+func sortedKeys[V any](m map[uint64]V) []uint64 {
+	keys := make([]uint64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func nextSeqId[K comparable](m map[K]uint64, key K) uint64 {
+	seqId := m[key]
+	if seqId == 0 {
+		seqId = 1
+	}
+	m[key] = seqId + 1
+	return seqId
+}
+
+func (p *MemStorage) AllocateSeqId(ctx context.Context, uid uint64) (seqId uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return nextSeqId(p.userSeqIds, uid), nil
+}
+
+func (p *MemStorage) AllocateChatSeqId(ctx context.Context, uid1 uint64, uid2 uint64) (seqId uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.allocateChatSeqId(uid1, uid2), nil
+}
+
+func (p *MemStorage) allocateChatSeqId(uid1 uint64, uid2 uint64) uint64 {
+	if uid1 > uid2 {
+		uid1, uid2 = uid2, uid1
+	}
+	return nextSeqId(p.chatSeqIds, [2]uint64{uid1, uid2})
+}
+
+func (p *MemStorage) AllocateGroupSeqId(ctx context.Context, groupId uint64) (seqId uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return nextSeqId(p.groupSeqIds, groupId), nil
+}
+
+// UserMgmt
+func (p *MemStorage) findUserByUsername(username string) *User {
+	for _, user := range p.users {
+		// 与 utf8_general_ci 一致，用户名不区分大小写
+		if strings.EqualFold(user.Username, username) {
+			return user
+		}
+	}
+	return nil
+}
+
+func userToUserInfo(user *User) *types.UmUserInfo {
+	return &types.UmUserInfo{
+		Uid:      user.UserID,
+		Password: user.Password,
+		Username: user.Username,
+		Nickname: user.Nickname,
+		Email:    user.Email,
+		Avatar:   user.Avatar,
+	}
+}
+
+func (p *MemStorage) UserIsUsernameExisted(ctx context.Context, username string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.findUserByUsername(username) != nil, nil
+}
+
+func (p *MemStorage) UserRegister(ctx context.Context, param *types.UmRegisterParam) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 唯一约束
+	if p.findUserByUsername(param.Username) != nil {
+		return 0, fmt.Errorf("duplicate username: %s", param.Username)
+	}
+	for _, user := range p.users {
+		if user.Email == param.Email {
+			return 0, fmt.Errorf("duplicate email: %s", param.Email)
+		}
+	}
+
+	p.lastUid++
+	p.users[p.lastUid] = &User{
+		UserID:    p.lastUid,
+		Password:  param.Passwd,
+		Username:  param.Username,
+		Nickname:  param.Nickname,
+		Email:     param.Email,
+		Avatar:    param.Avatar,
+		CreatedAt: time.Now().UTC(),
+	}
+	return p.lastUid, nil
+}
+
+func (p *MemStorage) UserUnregister(ctx context.Context, param *types.UmUnregisterParam) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.users, param.Uid)
+	return nil
+}
+
+func (p *MemStorage) UserGetInfo(ctx context.Context, uid uint64) (user *types.UmUserInfo, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	userRow, ok := p.users[uid]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return userToUserInfo(userRow), nil
+}
+
+func (p *MemStorage) UserGetInfoByUsername(ctx context.Context, username string) (user *types.UmUserInfo, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	userRow := p.findUserByUsername(username)
+	if userRow == nil {
+		return nil, sql.ErrNoRows
+	}
+	return userToUserInfo(userRow), nil
+}
+
+func (p *MemStorage) UserUpdateInfo(ctx context.Context, uid uint64, nickname string, email string, avatar string, password string) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	user, ok := p.users[uid]
+	if !ok {
+		return nil
+	}
+	if nickname != "" {
+		user.Nickname = nickname
+	}
+	if email != "" {
+		user.Email = email
+	}
+	if avatar != "" {
+		user.Avatar = avatar
+	}
+	if password != "" {
+		user.Password = password
+	}
+	return nil
+}
+
+func (p *MemStorage) UserUpdatePassHash(ctx context.Context, uid uint64, oldPassHash string, newPassHash string) (updated bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	user, ok := p.users[uid]
+	if !ok || user.Password != oldPassHash {
+		return false, nil
+	}
+	user.Password = newPassHash
+	return true, nil
+}
+
+func (p *MemStorage) ContactGetList(ctx context.Context, uid uint64) (contactUidList []uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.contacts[uid]) == 0 {
+		return nil, nil
+	}
+	return sortedKeys(p.contacts[uid]), nil
+}
+
+func (p *MemStorage) ContactGetRelation(ctx context.Context, uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	contact, ok := p.contacts[uid][contactUid]
+	if !ok {
+		return false, "", nil
+	}
+	return contact.isMutualContact, contact.remarkName, nil
+}
+
+func (p *MemStorage) setContact(uid uint64, contactUid uint64) {
+	contacts, ok := p.contacts[uid]
+	if !ok {
+		contacts = make(map[uint64]*memContact)
+		p.contacts[uid] = contacts
+	}
+	contact, ok := contacts[contactUid]
+	if !ok {
+		contact = &memContact{createdAt: time.Now().UTC()}
+		contacts[contactUid] = contact
+	}
+	contact.isMutualContact = true
+}
+
+func (p *MemStorage) ContactAdd(ctx context.Context, uid uint64, contactUid uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.setContact(uid, contactUid)
+	p.setContact(contactUid, uid)
+	return nil
+}
+
+// updateInboxStatus 更新 uid 收件箱中满足条件的消息状态。uid 为 0 时遍历所有用户
+func (p *MemStorage) updateInboxStatus(uid uint64, match func(msg *InboxMsg) bool, status uint32) {
+	update := func(msgs []*InboxMsg) {
+		for _, msg := range msgs {
+			if match(msg) {
+				msg.Status = status
+			}
+		}
+	}
+	if uid != 0 {
+		update(p.inbox[uid])
+		return
+	}
+	for _, msgs := range p.inbox {
+		update(msgs)
+	}
+}
+
+// removeMsgs 移除满足条件的消息，返回剩余和移除的消息
+func removeMsgs(msgs []*InboxMsg, match func(msg *InboxMsg) bool) (kept []*InboxMsg, removed []*InboxMsg) {
+	kept = msgs[:0]
+	for _, msg := range msgs {
+		if match(msg) {
+			removed = append(removed, msg)
+		} else {
+			kept = append(kept, msg)
+		}
+	}
+	for i := len(kept); i < len(msgs); i++ {
+		msgs[i] = nil
+	}
+	return kept, removed
+}
+
+// deleteInboxMsgs 删除 uid 收件箱中满足条件的消息
+func (p *MemStorage) deleteInboxMsgs(uid uint64, match func(msg *InboxMsg) bool) {
+	p.inbox[uid], _ = removeMsgs(p.inbox[uid], match)
+}
+
+// deleteConvMsgs 删除 uid 收件箱和归档中满足条件的会话消息
+func (p *MemStorage) deleteConvMsgs(uid uint64, match func(msg *InboxMsg) bool) {
+	p.deleteInboxMsgs(uid, match)
+	p.archive[uid], _ = removeMsgs(p.archive[uid], match)
+}
+
+func isFromUserTo(senderUid uint64, receiverUid uint64) func(msg *InboxMsg) bool {
+	return func(msg *InboxMsg) bool {
+		return msg.SenderID == senderUid && msg.ReceiverID.Valid && uint64(msg.ReceiverID.Int64) == receiverUid
+	}
+}
+
+func isFromUserToGroup(senderUid uint64, groupId uint64) func(msg *InboxMsg) bool {
+	return func(msg *InboxMsg) bool {
+		return msg.SenderID == senderUid && msg.GroupID.Valid && uint64(msg.GroupID.Int64) == groupId
+	}
+}
+
+func isOfGroup(groupId uint64) func(msg *InboxMsg) bool {
+	return func(msg *InboxMsg) bool {
+		return msg.GroupID.Valid && uint64(msg.GroupID.Int64) == groupId
+	}
+}
+
+func (p *MemStorage) ContactAccept(ctx context.Context, uid uint64, contactUid uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.setContact(uid, contactUid)
+	p.setContact(contactUid, uid)
+	// 更新好友请求消息状态
+	isRequest := isFromUserTo(contactUid, uid)
+	p.updateInboxStatus(uid, func(msg *InboxMsg) bool {
+		return isRequest(msg) && msg.Status == 0
+	}, 1)
+	return nil
+}
+
+func (p *MemStorage) ContactDel(ctx context.Context, uid uint64, contactUid uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.contacts[uid], contactUid)
+	delete(p.contacts[contactUid], uid)
+	p.deleteConvMsgs(uid, isFromUserTo(contactUid, uid))
+	p.deleteConvMsgs(uid, isFromUserTo(uid, contactUid))
+	return nil
+}
+
+func (p *MemStorage) ContactReject(ctx context.Context, uid uint64, contactUid uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	isRequest := isFromUserTo(contactUid, uid)
+	p.updateInboxStatus(uid, func(msg *InboxMsg) bool {
+		return isRequest(msg) && msg.Status == 0
+	}, 2)
+	return nil
+}
+
+func (p *MemStorage) GroupGetList(ctx context.Context, uid uint64) (ConvIdList []uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, groupId := range sortedKeys(p.groups) {
+		if _, ok := p.groups[groupId].members[uid]; ok {
+			ConvIdList = append(ConvIdList, groupId)
+		}
+	}
+	return ConvIdList, nil
+}
+
+func (p *MemStorage) GroupGetInfo(ctx context.Context, groupId uint64) (groupInfo *types.UmGroupInfo, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.groups[groupId]
+	if !ok {
+		return nil, fmt.Errorf("Scan: %w", sql.ErrNoRows)
+	}
+	return &types.UmGroupInfo{
+		GroupId:    g.group.GroupID,
+		GroupName:  g.group.GroupName,
+		OwnerUid:   g.group.OwnerUid,
+		Avatar:     g.group.Avatar,
+		MemCount:   g.group.MemCount,
+		CreateTsMs: uint64(g.group.CreatedAt.UnixNano() / 1e6),
+	}, nil
+}
+
+func (p *MemStorage) GroupUpdateInfo(ctx context.Context, groupId uint64, groupName string, avatar string) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.groups[groupId]
+	if !ok {
+		return nil
+	}
+	if groupName != "" {
+		g.group.GroupName = groupName
+	}
+	if avatar != "" {
+		g.group.Avatar = avatar
+	}
+	return nil
+}
+
+func (p *MemStorage) GroupCreate(ctx context.Context, uid uint64, groupName string) (ConvId uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.users[uid]; !ok {
+		return 0, fmt.Errorf("owner %d not existed", uid)
+	}
+
+	now := time.Now().UTC()
+	p.lastGroupId++
+	p.groups[p.lastGroupId] = &memGroup{
+		group: ChatGroup{
+			GroupID:   p.lastGroupId,
+			GroupName: groupName,
+			OwnerUid:  uid,
+			MemCount:  1,
+			CreatedAt: now,
+		},
+		members: map[uint64]*ChatGroupMember{
+			uid: {GroupID: p.lastGroupId, UserID: uid, Role: 1, JoinedAt: now},
+		},
+	}
+	return p.lastGroupId, nil
+}
+
+func (p *MemStorage) GroupDelete(ctx context.Context, uid uint64, groupId uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.memberRole(groupId, uid) != 1 {
+		return fmt.Errorf("uid is not the owner of the group")
+	}
+	delete(p.groups, groupId)
+	// 删除时间线
+	delete(p.timelines, groupId)
+	delete(p.timelineSeqIds, groupId)
+	for key := range p.groupCursors {
+		if key[0] == groupId {
+			delete(p.groupCursors, key)
+		}
+	}
+	return nil
+}
+
+func (p *MemStorage) GroupGetMemList(ctx context.Context, groupId uint64) (memUidList []uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.groups[groupId]
+	if !ok {
+		return nil, nil
+	}
+	return sortedKeys(g.members), nil
+}
+
+func (p *MemStorage) groupAdminList(groupId uint64) (adminUid []uint64) {
+	g, ok := p.groups[groupId]
+	if !ok {
+		return nil
+	}
+	for _, uid := range sortedKeys(g.members) {
+		role := g.members[uid].Role
+		if role == 1 || role == 2 {
+			adminUid = append(adminUid, uid)
+		}
+	}
+	return adminUid
+}
+
+func (p *MemStorage) GroupGetAdminList(ctx context.Context, groupId uint64) (adminUid []uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.groupAdminList(groupId), nil
+}
+
+// memberRole 返回群成员角色，不是群成员时返回 -1
+func (p *MemStorage) memberRole(groupId uint64, uid uint64) int {
+	g, ok := p.groups[groupId]
+	if !ok {
+		return -1
+	}
+	member, ok := g.members[uid]
+	if !ok {
+		return -1
+	}
+	return int(member.Role)
+}
+
+func (p *MemStorage) GroupIsOwner(ctx context.Context, groupId uint64, uid uint64) (isOwner bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.memberRole(groupId, uid) == 1, nil
+}
+
+func (p *MemStorage) GroupIsAdmin(ctx context.Context, groupId uint64, uid uint64) (isAdmin bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	role := p.memberRole(groupId, uid)
+	return role == 1 || role == 2, nil
+}
+
+func (p *MemStorage) GroupIsMem(ctx context.Context, groupId uint64, uid uint64) (inGroup bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.memberRole(groupId, uid) >= 0, nil
+}
+
+func (p *MemStorage) GroupClearMsg(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deleteConvMsgs(uid, isOfGroup(groupId))
+	// 尚未同步的时间线消息也不再同步
+	p.resetGroupCursor(groupId, uid)
+	return nil
+}
+
+func (p *MemStorage) delMem(groupId uint64, uid uint64) {
+	g, ok := p.groups[groupId]
+	if !ok {
+		return
+	}
+	delete(g.members, uid)
+	if g.group.MemCount > 0 {
+		g.group.MemCount--
+	}
+	delete(p.groupCursors, [2]uint64{groupId, uid})
+}
+
+func (p *MemStorage) GroupLeave(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delMem(groupId, uid)
+	// 为此用户删除群聊消息
+	p.deleteConvMsgs(uid, isOfGroup(groupId))
+	return nil
+}
+
+func (p *MemStorage) addMem(groupId uint64, uid uint64, role uint) error {
+	g, ok := p.groups[groupId]
+	if !ok {
+		return fmt.Errorf("group %d not existed", groupId)
+	}
+	if _, ok = p.users[uid]; !ok {
+		return fmt.Errorf("user %d not existed", uid)
+	}
+	if _, ok = g.members[uid]; ok {
+		return fmt.Errorf("duplicate member %d of group %d", uid, groupId)
+	}
+	g.members[uid] = &ChatGroupMember{GroupID: groupId, UserID: uid, Role: role, JoinedAt: time.Now().UTC()}
+	g.group.MemCount++
+	// 新成员不同步入群前的时间线消息
+	p.resetGroupCursor(groupId, uid)
+	return nil
+}
+
+func (p *MemStorage) GroupAddMem(ctx context.Context, groupId uint64, uid uint64, role uint) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.addMem(groupId, uid, role)
+}
+
+func (p *MemStorage) GroupDelMem(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.delMem(groupId, uid)
+	return nil
+}
+
+func (p *MemStorage) GroupAccept(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err = p.addMem(groupId, uid, 0)
+	if err != nil {
+		return fmt.Errorf("GroupAddMem: %w", err)
+	}
+	// 更新入群请求状态
+	p.updateInboxStatus(0, isFromUserToGroup(uid, groupId), 1)
+	return nil
+}
+
+func (p *MemStorage) GroupReject(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updateInboxStatus(0, isFromUserToGroup(uid, groupId), 2)
+	return nil
+}
+
+func (p *MemStorage) GroupIgnore(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updateInboxStatus(0, isFromUserToGroup(uid, groupId), 3)
+	return nil
+}
+
+func (p *MemStorage) GroupUpdateMem(ctx context.Context, groupId uint64, uid uint64, role uint) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g, ok := p.groups[groupId]
+	if !ok {
+		return nil
+	}
+	if member, ok := g.members[uid]; ok {
+		member.Role = role
+	}
+	return nil
+}
+
+func (p *MemStorage) sendMsgToUser(uid uint64, convMsg types.ChatMsgOfConv) {
+	var receiverId, groupId sql.NullInt64
+	if convMsg.ReceiverId.PeerIdType == types.EmPeerIdType_Uid {
+		receiverId = sql.NullInt64{Int64: int64(convMsg.ReceiverId.Uid), Valid: true}
+		if convMsg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
+			// 删除 sender 发向 receiver 的已读消息
+			isMarkRead := isFromUserTo(convMsg.Msg.SenderUid, convMsg.ReceiverId.Uid)
+			p.deleteInboxMsgs(uid, func(msg *InboxMsg) bool {
+				return isMarkRead(msg) && msg.MessageType == int(gen_grpc.ChatMsgType_emChatMsgType_MarkRead) &&
+					msg.ReadMsgId <= convMsg.Msg.ReadMsgId
+			})
+		}
+	} else {
+		groupId = sql.NullInt64{Int64: int64(convMsg.ReceiverId.GroupId), Valid: true}
+		if convMsg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
+			// 删除 sender 发向 group 的已读消息
+			isMarkRead := isFromUserToGroup(convMsg.Msg.SenderUid, convMsg.ReceiverId.GroupId)
+			p.deleteInboxMsgs(uid, func(msg *InboxMsg) bool {
+				return isMarkRead(msg) && msg.MessageType == int(gen_grpc.ChatMsgType_emChatMsgType_MarkRead) &&
+					msg.ReadMsgId <= convMsg.Msg.ReadMsgId
+			})
+		}
+	}
+
+	// 分配 seqId 并添加消息
+	p.inbox[uid] = append(p.inbox[uid], &InboxMsg{
+		UserID:      uid,
+		SeqID:       nextSeqId(p.userSeqIds, uid),
+		ConvMsgId:   convMsg.ConvMsgId,
+		RandMsgId:   convMsg.RandMsgId,
+		SenderID:    convMsg.Msg.SenderUid,
+		ReceiverID:  receiverId,
+		GroupID:     groupId,
+		MessageType: int(convMsg.Msg.MsgType),
+		Content:     convMsg.Msg.MsgContent,
+		ReadMsgId:   convMsg.Msg.ReadMsgId,
+		SentAt:      time.Now().UTC(),
+	})
+}
+
+// isReadDiffusionGroup 群消息是否写入时间线。切换为读扩散后不再切回
+func (p *MemStorage) isReadDiffusionGroup(groupId uint64) bool {
+	if _, ok := p.timelineSeqIds[groupId]; ok {
+		return true
+	}
+	g, ok := p.groups[groupId]
+	return ok && p.readDiffusionThreshold > 0 && g.group.MemCount > p.readDiffusionThreshold
+}
+
+func (p *MemStorage) appendGroupTimeline(convMsg types.ChatMsgOfConv) {
+	groupId := convMsg.ReceiverId.GroupId
+	if convMsg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
+		// 删除 sender 发向 group 的旧已读消息
+		timeline := p.timelines[groupId][:0]
+		for _, msg := range p.timelines[groupId] {
+			if !(msg.SenderID == convMsg.Msg.SenderUid && msg.MessageType == int(gen_grpc.ChatMsgType_emChatMsgType_MarkRead) &&
+				msg.ReadMsgId <= convMsg.Msg.ReadMsgId) {
+				timeline = append(timeline, msg)
+			}
+		}
+		p.timelines[groupId] = timeline
+	}
+
+	p.timelines[groupId] = append(p.timelines[groupId], &InboxMsg{
+		SeqID:       nextSeqId(p.timelineSeqIds, groupId),
+		ConvMsgId:   convMsg.ConvMsgId,
+		RandMsgId:   convMsg.RandMsgId,
+		SenderID:    convMsg.Msg.SenderUid,
+		GroupID:     sql.NullInt64{Int64: int64(groupId), Valid: true},
+		MessageType: int(convMsg.Msg.MsgType),
+		Content:     convMsg.Msg.MsgContent,
+		ReadMsgId:   convMsg.Msg.ReadMsgId,
+		SentAt:      time.Now().UTC(),
+	})
+}
+
+// resetGroupCursor 把成员游标移到时间线末尾
+func (p *MemStorage) resetGroupCursor(groupId uint64, uid uint64) {
+	var cursor uint64
+	if next, ok := p.timelineSeqIds[groupId]; ok {
+		cursor = next - 1
+	}
+	p.groupCursors[[2]uint64{groupId, uid}] = cursor
+}
+
+// syncGroupTimelines 把用户所在读扩散群游标之后的时间线消息复制到收件箱
+func (p *MemStorage) syncGroupTimelines(uid uint64) {
+	for _, groupId := range sortedKeys(p.timelines) {
+		if p.memberRole(groupId, uid) < 0 {
+			continue
+		}
+		key := [2]uint64{groupId, uid}
+		cursor := p.groupCursors[key]
+		for _, msg := range p.timelines[groupId] {
+			if msg.SeqID <= cursor {
+				continue
+			}
+			if msg.MessageType == int(gen_grpc.ChatMsgType_emChatMsgType_MarkRead) {
+				// 删除 sender 发向 group 的旧已读消息
+				isMarkRead := isFromUserToGroup(msg.SenderID, groupId)
+				p.deleteInboxMsgs(uid, func(inboxMsg *InboxMsg) bool {
+					return isMarkRead(inboxMsg) && inboxMsg.MessageType == msg.MessageType && inboxMsg.ReadMsgId <= msg.ReadMsgId
+				})
+			}
+			inboxMsg := *msg
+			inboxMsg.UserID = uid
+			inboxMsg.SeqID = nextSeqId(p.userSeqIds, uid)
+			p.inbox[uid] = append(p.inbox[uid], &inboxMsg)
+			p.groupCursors[key] = msg.SeqID
+		}
+	}
+}
+
+func (p *MemStorage) ChatSendMsgToUser(ctx context.Context, uid uint64, convMsg types.ChatMsgOfConv) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sendMsgToUser(uid, convMsg)
+	return nil
+}
+
+func (p *MemStorage) ChatSendMsg(ctx context.Context, convMsg types.ChatMsgOfConv) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if convMsg.ReceiverId.PeerIdType == types.EmPeerIdType_Uid {
+		convMsg.ConvMsgId = p.allocateChatSeqId(convMsg.Msg.SenderUid, convMsg.ReceiverId.Uid)
+		p.sendMsgToUser(convMsg.ReceiverId.Uid, convMsg)
+		// 也为发送者添加消息
+		if convMsg.Msg.SenderUid != convMsg.ReceiverId.Uid {
+			p.sendMsgToUser(convMsg.Msg.SenderUid, convMsg)
+		}
+		return nil
+	}
+
+	var memberList []uint64
+	if g, ok := p.groups[convMsg.ReceiverId.GroupId]; ok {
+		memberList = sortedKeys(g.members)
+	}
+	convMsg.ConvMsgId = nextSeqId(p.groupSeqIds, convMsg.ReceiverId.GroupId)
+	// 大群只写时间线
+	if p.isReadDiffusionGroup(convMsg.ReceiverId.GroupId) {
+		p.appendGroupTimeline(convMsg)
+		return nil
+	}
+	for _, memberUid := range memberList {
+		p.sendMsgToUser(memberUid, convMsg)
+	}
+	return nil
+}
+
+func (p *MemStorage) ChatSendMsgToAdmins(ctx context.Context, convMsg types.ChatMsgOfConv) (err error) {
+	if convMsg.ReceiverId.PeerIdType != types.EmPeerIdType_GroupId {
+		return fmt.Errorf("peer id type must be group id")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	adminUidList := p.groupAdminList(convMsg.ReceiverId.GroupId)
+	convMsg.ConvMsgId = nextSeqId(p.groupSeqIds, convMsg.ReceiverId.GroupId)
+	for _, adminUid := range adminUidList {
+		p.sendMsgToUser(adminUid, convMsg)
+	}
+	return nil
+}
+
+func (p *MemStorage) ChatMarkRead(ctx context.Context, uid uint64, contactId uint64, readMsgId uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	isFromContact := isFromUserTo(contactId, uid)
+	for _, owner := range []uint64{uid, contactId} {
+		for _, msg := range p.inbox[owner] {
+			if isFromContact(msg) && msg.ConvMsgId <= readMsgId {
+				msg.IsRead = true
+			}
+		}
+	}
+	return nil
+}
+
+func (p *MemStorage) ChatReadGroupMsg(ctx context.Context, uid uint64, groupId uint64, readMsgId uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	inGroup := isOfGroup(groupId)
+	for _, msg := range p.inbox[uid] {
+		if inGroup(msg) && msg.ConvMsgId <= readMsgId {
+			msg.IsRead = true
+		}
+	}
+	return nil
+}
+
+func (p *MemStorage) ChatSyncInbox(ctx context.Context, uid uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.syncGroupTimelines(uid)
+	return nil
+}
+
+func (p *MemStorage) ChatGetMsgList(ctx context.Context, uid uint64, seqId uint64, limit int) (msgs []types.ChatMsgOfConv, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 收件箱按 seqId 升序保存
+	inbox := p.inbox[uid]
+	start := sort.Search(len(inbox), func(i int) bool { return inbox[i].SeqID > seqId })
+	end := len(inbox)
+	if end-start > limit {
+		end = start + limit
+	}
+	for _, rowMsg := range inbox[start:end] {
+		msg, err := convertDbMsgToChatMsgOfConv(*rowMsg)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// historyMsgs 与 DB 一致，按 seqId 倒序取会话中的 limit 条消息
+func historyMsgs(msgs []*InboxMsg, inConv func(msg *InboxMsg) bool, beforeSeqId uint64, limit int) []*InboxMsg {
+	var rowMsgs []*InboxMsg
+	for _, msg := range msgs {
+		// 已读消息不属于会话内容
+		if !inConv(msg) || msg.MessageType == int(gen_grpc.ChatMsgType_emChatMsgType_MarkRead) {
+			continue
+		}
+		if beforeSeqId != 0 && msg.SeqID >= beforeSeqId {
+			continue
+		}
+		rowMsgs = append(rowMsgs, msg)
+	}
+	sort.Slice(rowMsgs, func(i, j int) bool { return rowMsgs[i].SeqID > rowMsgs[j].SeqID })
+	if len(rowMsgs) > limit {
+		rowMsgs = rowMsgs[:limit]
+	}
+	return rowMsgs
+}
+
+func (p *MemStorage) ChatGetHistory(ctx context.Context, uid uint64, peerId types.PeerId, beforeSeqId uint64, limit int) (msgs []types.ChatMsgOfConv, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var inConv func(msg *InboxMsg) bool
+	if peerId.PeerIdType == types.EmPeerIdType_GroupId {
+		// 先同步读扩散群的新消息
+		p.syncGroupTimelines(uid)
+		inConv = isOfGroup(peerId.GroupId)
+	} else {
+		sent := isFromUserTo(uid, peerId.Uid)
+		received := isFromUserTo(peerId.Uid, uid)
+		inConv = func(msg *InboxMsg) bool { return sent(msg) || received(msg) }
+	}
+
+	rowMsgs := historyMsgs(p.inbox[uid], inConv, beforeSeqId, limit)
+	// 收件箱中不够时接着读归档
+	if len(rowMsgs) < limit {
+		if len(rowMsgs) > 0 {
+			beforeSeqId = rowMsgs[len(rowMsgs)-1].SeqID
+		}
+		rowMsgs = append(rowMsgs, historyMsgs(p.archive[uid], inConv, beforeSeqId, limit-len(rowMsgs))...)
+	}
+	for i := len(rowMsgs) - 1; i >= 0; i-- {
+		msg, err := convertDbMsgToChatMsgOfConv(*rowMsgs[i])
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (p *MemStorage) ArchiveInbox(ctx context.Context, retention InboxRetention) (archived int64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cutoff := time.Now().UTC().Add(-retention.MaxAge)
+	for _, uid := range sortedKeys(p.inbox) {
+		msgs := p.inbox[uid]
+		expired := make(map[*InboxMsg]bool)
+		if retention.MaxAge > 0 {
+			for _, msg := range msgs {
+				if msg.SentAt.Before(cutoff) {
+					expired[msg] = true
+				}
+			}
+		}
+		// 收件箱按 seqId 升序，从后往前数保留最新的消息
+		if retention.MaxMsgsPerConv > 0 {
+			convCounts := make(map[inboxConv]int)
+			for i := len(msgs) - 1; i >= 0; i-- {
+				conv := memInboxConv(uid, msgs[i])
+				convCounts[conv]++
+				if convCounts[conv] > retention.MaxMsgsPerConv {
+					expired[msgs[i]] = true
+				}
+			}
+		}
+		if retention.MaxMsgsPerUser > 0 {
+			for i := len(msgs) - 1 - retention.MaxMsgsPerUser; i >= 0; i-- {
+				expired[msgs[i]] = true
+			}
+		}
+		if len(expired) == 0 {
+			continue
+		}
+
+		kept, removed := removeMsgs(msgs, func(msg *InboxMsg) bool { return expired[msg] })
+		p.inbox[uid] = kept
+		p.archive[uid] = append(p.archive[uid], removed...)
+		archived += int64(len(removed))
+	}
+	return archived, nil
+}
+
+// memInboxConv 消息在 uid 收件箱中所属的会话
+func memInboxConv(uid uint64, msg *InboxMsg) inboxConv {
+	if msg.GroupID.Valid {
+		return inboxConv{groupId: uint64(msg.GroupID.Int64)}
+	}
+	if msg.SenderID == uid {
+		return inboxConv{peerUid: uint64(msg.ReceiverID.Int64)}
+	}
+	return inboxConv{peerUid: msg.SenderID}
+}
+
+// MarkUserWritten 进程内存储没有副本
+func (p *MemStorage) MarkUserWritten(uid uint64) {}
+
+// MemCache 进程内会话存储。用户认证、联系人和群组、收件箱缓存始终未命中，直接查询存储
+type MemCache struct {
+	mu           sync.Mutex
+	sessions     map[types.SessId]*types.SessCtx
+	userSessions map[uint64]map[types.SessId]struct{}
+	tokenRevoked map[string]uint64
+	// loginFails 每个 key 的登录失败时间，loginLocks 锁定到期时间
+	loginFails map[string][]time.Time
+	loginLocks map[string]time.Time
+}
+
+func NewMemCache() *MemCache {
+	return &MemCache{
+		sessions:     make(map[types.SessId]*types.SessCtx),
+		userSessions: make(map[uint64]map[types.SessId]struct{}),
+		tokenRevoked: make(map[string]uint64),
+		loginFails:   make(map[string][]time.Time),
+		loginLocks:   make(map[string]time.Time),
+	}
+}
+
+func (p *MemCache) CreateSess(ctx context.Context, username string, uid uint64, device types.SessDevice, expireAfterSecs uint64) (sessId types.SessId, err error) {
+	sessIdStr, err := GenerateSessionID(uid)
+	if err != nil {
+		return "", err
+	}
+	sessId = types.SessId(sessIdStr)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.sessions[sessId]; ok {
+		return "", fmt.Errorf("session ID already exists")
+	}
+
+	createdAt := uint64(time.Now().Unix())
+	p.sessions[sessId] = &types.SessCtx{
+		SessId:     sessId,
+		Username:   username,
+		Uid:        uid,
+		SessDevice: device,
+		CreatedAt:  createdAt,
+		ExpiresAt:  createdAt + expireAfterSecs,
+		LastSeenAt: createdAt,
+	}
+	userSessions, ok := p.userSessions[uid]
+	if !ok {
+		userSessions = make(map[types.SessId]struct{})
+		p.userSessions[uid] = userSessions
+	}
+	userSessions[sessId] = struct{}{}
+	return sessId, nil
+}
+
+// getSessCtx 获取未过期的会话，过期的会话顺便删除
+func (p *MemCache) getSessCtx(sessId types.SessId) *types.SessCtx {
+	sessCtx, ok := p.sessions[sessId]
+	if !ok {
+		return nil
+	}
+	if sessCtx.ExpiresAt <= uint64(time.Now().Unix()) {
+		p.deleteSess(sessCtx)
+		return nil
+	}
+	return sessCtx
+}
+
+func (p *MemCache) deleteSess(sessCtx *types.SessCtx) {
+	delete(p.sessions, sessCtx.SessId)
+	delete(p.userSessions[sessCtx.Uid], sessCtx.SessId)
+	if len(p.userSessions[sessCtx.Uid]) == 0 {
+		delete(p.userSessions, sessCtx.Uid)
+	}
+}
+
+func (p *MemCache) GetSessCtx(ctx context.Context, sessId types.SessId) (sessCtx *types.SessCtx, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sessCtx = p.getSessCtx(sessId)
+	if sessCtx == nil {
+		return nil, fmt.Errorf("%w", proj_err.ErrSessNotExisted)
+	}
+	ret := *sessCtx
+	return &ret, nil
+}
+
+func (p *MemCache) GetSessCtxByUid(ctx context.Context, uid uint64) (sessCtx *types.SessCtx, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for sessId := range p.userSessions[uid] {
+		sessCtx = p.getSessCtx(sessId)
+		if sessCtx != nil {
+			ret := *sessCtx
+			return &ret, nil
+		}
+	}
+	return nil, fmt.Errorf("no active sessions found for user: %d", uid)
+}
+
+func (p *MemCache) GetUserSessList(ctx context.Context, uid uint64) (sessList []*types.SessCtx, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for sessId := range p.userSessions[uid] {
+		sessCtx := p.getSessCtx(sessId)
+		if sessCtx != nil {
+			ret := *sessCtx
+			sessList = append(sessList, &ret)
+		}
+	}
+	return sessList, nil
+}
+
+func (p *MemCache) RenewSessCtx(ctx context.Context, sessCtx *types.SessCtx, expireAfterSecs uint64) (err error) {
+	if expireAfterSecs == 0 {
+		return fmt.Errorf("expireAfterSecs must be greater than zero")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stored := p.getSessCtx(sessCtx.SessId)
+	if stored == nil {
+		return fmt.Errorf("%w", proj_err.ErrSessNotExisted)
+	}
+	now := uint64(time.Now().Unix())
+	stored.ExpiresAt = now + expireAfterSecs
+	stored.LastSeenAt = now
+	return nil
+}
+
+func (p *MemCache) DeleteSess(ctx context.Context, sessId types.SessId) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sessCtx, ok := p.sessions[sessId]
+	if !ok {
+		return fmt.Errorf("%w", proj_err.ErrSessNotExisted)
+	}
+	p.deleteSess(sessCtx)
+	return nil
+}
+
+func (p *MemCache) DeleteUserSess(ctx context.Context, uid uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	userSessions := p.userSessions[uid]
+	if len(userSessions) == 0 {
+		return fmt.Errorf("no sessions found for user %d", uid)
+	}
+	for sessId := range userSessions {
+		delete(p.sessions, sessId)
+	}
+	delete(p.userSessions, uid)
+	return nil
+}
+
+func (p *MemCache) RevokeTokenSess(ctx context.Context, sessKeys []string, until uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, sessKey := range sessKeys {
+		p.tokenRevoked[sessKey] = until
+	}
+	return nil
+}
+
+func (p *MemCache) GetRevokedTokenSess(ctx context.Context) (revoked map[string]uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := uint64(time.Now().Unix())
+	revoked = make(map[string]uint64, len(p.tokenRevoked))
+	for sessKey, until := range p.tokenRevoked {
+		if until <= now {
+			delete(p.tokenRevoked, sessKey)
+			continue
+		}
+		revoked[sessKey] = until
+	}
+	return revoked, nil
+}
+
+// loginFailures 返回 windowS 秒内的失败记录，更早的记录顺便删除
+func (p *MemCache) loginFailures(key string, windowS uint64) []time.Time {
+	windowStart := time.Now().Add(-time.Duration(windowS) * time.Second)
+	fails := p.loginFails[key]
+	i := 0
+	for i < len(fails) && fails[i].Before(windowStart) {
+		i++
+	}
+	fails = fails[i:]
+	if len(fails) == 0 {
+		delete(p.loginFails, key)
+	} else {
+		p.loginFails[key] = fails
+	}
+	return fails
+}
+
+func (p *MemCache) AddLoginFailure(ctx context.Context, key string, windowS uint64) (count uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fails := append(p.loginFailures(key, windowS), time.Now())
+	p.loginFails[key] = fails
+	return uint64(len(fails)), nil
+}
+
+func (p *MemCache) GetLoginFailureCount(ctx context.Context, key string, windowS uint64) (count uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return uint64(len(p.loginFailures(key, windowS))), nil
+}
+
+func (p *MemCache) LockLogin(ctx context.Context, key string, lockS uint64) (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loginLocks[key] = time.Now().Add(time.Duration(lockS) * time.Second)
+	return nil
+}
+
+func (p *MemCache) GetLoginLockTtl(ctx context.Context, key string) (ttlS uint64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until, ok := p.loginLocks[key]
+	if !ok {
+		return 0, nil
+	}
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		delete(p.loginLocks, key)
+		return 0, nil
+	}
+	return uint64((ttl + time.Second - 1) / time.Second), nil
+}
+
+func (p *MemCache) ClearLoginFailures(ctx context.Context, key string) (wasLocked bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until, ok := p.loginLocks[key]
+	delete(p.loginFails, key)
+	delete(p.loginLocks, key)
+	return ok && time.Now().Before(until), nil
+}
+
+func (p *MemCache) GetUserAuth(ctx context.Context, username string) (user *types.UmUserInfo, err error) {
+	return nil, &CacheNotFoundError{Key: username}
+}
+
+func (p *MemCache) CacheUserAuthenticate(ctx context.Context, user *types.UmUserInfo) (err error) {
+	return nil
+}
+
+func (p *MemCache) ClearCacheUserAuthenticate(ctx context.Context, username string) (err error) {
+	return nil
+}
+
+func (p *MemCache) GetContactList(ctx context.Context, uid uint64) (contactsUid []uint64, fillVersion uint64, err error) {
+	return nil, 0, &CacheNotFoundError{Key: fmt.Sprintf("contact:list:%d", uid)}
+}
+
+func (p *MemCache) CacheContactList(ctx context.Context, uid uint64, fillVersion uint64, contactsUid []uint64) (err error) {
+	return nil
+}
+
+func (p *MemCache) GetContactRelation(ctx context.Context, uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, fillVersion uint64, err error) {
+	return false, "", 0, &CacheNotFoundError{Key: fmt.Sprintf("contact:relation:%d:%d", uid, contactUid)}
+}
+
+func (p *MemCache) CacheContactRelation(ctx context.Context, uid uint64, contactUid uint64, fillVersion uint64, isMutualContact bool, remarkName string) (err error) {
+	return nil
+}
+
+func (p *MemCache) ClearCacheContacts(ctx context.Context, uid uint64, contactUid uint64) (err error) {
+	return nil
+}
+
+func (p *MemCache) GroupGetList(ctx context.Context, uid uint64) (ConvIdList []uint64, fillVersion uint64, err error) {
+	return nil, 0, &CacheNotFoundError{Key: fmt.Sprintf("group:convlist:%d", uid)}
+}
+
+func (p *MemCache) CacheGroupConvList(ctx context.Context, uid uint64, fillVersion uint64, ConvIdList []uint64) (err error) {
+	return nil
+}
+
+func (p *MemCache) IsUserInConv(ctx context.Context, convId uint64, uid uint64) (inConv bool, fillVersion uint64, err error) {
+	return false, 0, &CacheNotFoundError{Key: fmt.Sprintf("conv:isuserin:%d:%d", convId, uid)}
+}
+
+func (p *MemCache) CacheIsUserInConv(ctx context.Context, convId uint64, uid uint64, fillVersion uint64, inConv bool) (err error) {
+	return nil
+}
+
+func (p *MemCache) ClearCacheGroupMembers(ctx context.Context, groupId uint64, uids []uint64) (err error) {
+	return nil
+}
+
+func (p *MemCache) GetChatMsgList(ctx context.Context, uid uint64, seqId uint64, limit int) (msgs []types.ChatMsgOfConv, fillVersion uint64, err error) {
+	return nil, 0, &CacheNotFoundError{Key: fmt.Sprintf("inbox:tail:{%d}", uid)}
+}
+
+func (p *MemCache) CacheChatMsgList(ctx context.Context, uid uint64, seqId uint64, fillVersion uint64, msgs []types.ChatMsgOfConv) (err error) {
+	return nil
+}
+
+func (p *MemCache) BeginAppendChatMsg(ctx context.Context, uids []uint64) (err error) {
+	return nil
+}
+
+func (p *MemCache) EndAppendChatMsg(ctx context.Context, uids []uint64, msgs map[uint64][]types.ChatMsgOfConv) (err error) {
+	return nil
+}
+
+func (p *MemCache) ClearCacheChatMsgList(ctx context.Context, uids []uint64) (err error) {
+	return nil
+}
@@ -0,0 +1,347 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	. "social_server/src/utils/log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 迁移文件按数据库分目录存放，命名为 NNNN_name.up.sql / NNNN_name.down.sql
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+const (
+	migrateLockName     = "social_server_migrate"
+	migrateLockTimeoutS = 600
+)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration *Migration
+	Applied   bool
+}
+
+// loadMigrations 读取指定数据库的迁移，按版本号升序
+func loadMigrations(driver string) ([]*Migration, error) {
+	dir := path.Join("migrations", driver)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("ReadDir: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		version, _ := strconv.ParseUint(match[1], 10, 64)
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("ReadFile: %w", err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s, %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	var migrations []*Migration
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// splitSqlStatements 按行尾的分号拆分语句，去掉整行注释
+func splitSqlStatements(content string) []string {
+	var stmts []string
+	var cur strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		}
+	}
+	if strings.TrimSpace(cur.String()) != "" {
+		stmts = append(stmts, strings.TrimSpace(cur.String()))
+	}
+	return stmts
+}
+
+// LatestSchemaVersion 当前程序需要的表结构版本
+func (p *DB) LatestSchemaVersion() (uint64, error) {
+	migrations, err := loadMigrations(p.driver)
+	if err != nil {
+		return 0, fmt.Errorf("loadMigrations: %w", err)
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+func (p *DB) ensureSchemaVersionTable() error {
+	_, err := p.db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("create schema_version: %w", err)
+	}
+	return nil
+}
+
+func (p *DB) appliedVersions() (map[uint64]bool, error) {
+	err := p.ensureSchemaVersionTable()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := p.db.Query("SELECT version FROM schema_version")
+	if err != nil {
+		return nil, fmt.Errorf("Query: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint64]bool)
+	for rows.Next() {
+		var version uint64
+		err = rows.Scan(&version)
+		if err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// SchemaVersion 数据库当前的表结构版本，未做过迁移时为 0
+func (p *DB) SchemaVersion() (uint64, error) {
+	err := p.ensureSchemaVersionTable()
+	if err != nil {
+		return 0, err
+	}
+	var version sql.NullInt64
+	err = p.db.QueryRow("SELECT MAX(version) FROM schema_version").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("Scan: %w", err)
+	}
+	return uint64(version.Int64), nil
+}
+
+// MigrationStatus 所有迁移及是否已执行
+func (p *DB) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(p.driver)
+	if err != nil {
+		return nil, fmt.Errorf("loadMigrations: %w", err)
+	}
+	applied, err := p.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	var status []MigrationStatus
+	for _, m := range migrations {
+		status = append(status, MigrationStatus{Migration: m, Applied: applied[m.Version]})
+	}
+	return status, nil
+}
+
+// runMigration 执行一次迁移并更新 schema_version。
+// SQLite 在事务中执行；MySQL 的 DDL 会隐式提交，只能逐条执行
+func (p *DB) runMigration(m *Migration, up bool) error {
+	content := m.Down
+	record := "DELETE FROM schema_version WHERE version = ?"
+	args := []interface{}{m.Version}
+	if up {
+		content = m.Up
+		record = "INSERT INTO schema_version (version, name) VALUES (?, ?)"
+		args = append(args, m.Name)
+	}
+
+	var exec func(query string, args ...interface{}) (sql.Result, error)
+	var tx *sql.Tx
+	var err error
+	if p.driver == driverSqlite {
+		tx, err = p.db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+		exec = tx.Exec
+	} else {
+		exec = p.db.Exec
+	}
+
+	for _, stmt := range splitSqlStatements(content) {
+		_, err = exec(stmt)
+		if err != nil {
+			return fmt.Errorf("exec %q: %w", stmt, err)
+		}
+	}
+	_, err = exec(record, args...)
+	if err != nil {
+		return fmt.Errorf("update schema_version: %w", err)
+	}
+
+	if tx != nil {
+		err = tx.Commit()
+		if err != nil {
+			return fmt.Errorf("Commit: %w", err)
+		}
+	}
+	return nil
+}
+
+// lockMigration 在 MySQL 上加全局锁，多个节点同时启动时依次迁移。
+// MySQL 的 DDL 不在事务中，两个节点同时执行同一个迁移会有一个中途失败。
+// GET_LOCK 属于连接，需要占用一个连接直到释放。SQLite 的迁移在事务中执行，不需要加锁
+func (p *DB) lockMigration() (unlock func(), err error) {
+	if p.driver != driverMysql {
+		return func() {}, nil
+	}
+	ctx := context.Background()
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Conn: %w", err)
+	}
+	var locked sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrateLockName, migrateLockTimeoutS).Scan(&locked)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("GET_LOCK: %w", err)
+	}
+	if locked.Int64 != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("timed out waiting for lock %s", migrateLockName)
+	}
+	return func() {
+		_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", migrateLockName)
+		if err != nil {
+			Log.Warn("RELEASE_LOCK: %v", err)
+		}
+		conn.Close()
+	}, nil
+}
+
+// MigrateUp 按版本号升序执行未执行的迁移。steps 为 0 时执行全部
+func (p *DB) MigrateUp(steps int) (done []*Migration, err error) {
+	unlock, err := p.lockMigration()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// 加锁后再读取已执行的迁移，等锁期间其他节点可能已经执行过
+	status, err := p.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range status {
+		if s.Applied {
+			continue
+		}
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		err = p.runMigration(s.Migration, true)
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s: %w", s.Migration.Version, s.Migration.Name, err)
+		}
+		done = append(done, s.Migration)
+	}
+	return done, nil
+}
+
+// MigrateDown 按版本号降序回滚已执行的迁移。steps 为 0 时回滚一个
+func (p *DB) MigrateDown(steps int) (done []*Migration, err error) {
+	if steps <= 0 {
+		steps = 1
+	}
+	unlock, err := p.lockMigration()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	status, err := p.MigrationStatus()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(status) - 1; i >= 0 && len(done) < steps; i-- {
+		if !status[i].Applied {
+			continue
+		}
+		err = p.runMigration(status[i].Migration, false)
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s: %w", status[i].Migration.Version, status[i].Migration.Name, err)
+		}
+		done = append(done, status[i].Migration)
+	}
+	return done, nil
+}
+
+// checkSchema 连接建立后检查表结构版本，开启自动迁移时先执行迁移
+func (p *DB) checkSchema() error {
+	if p.autoMigrate {
+		done, err := p.MigrateUp(0)
+		if err != nil {
+			return fmt.Errorf("MigrateUp: %w", err)
+		}
+		for _, m := range done {
+			Log.Info("Applied migration %d_%s", m.Version, m.Name)
+		}
+	}
+
+	version, err := p.SchemaVersion()
+	if err != nil {
+		return fmt.Errorf("SchemaVersion: %w", err)
+	}
+	latest, err := p.LatestSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("LatestSchemaVersion: %w", err)
+	}
+	if version < latest {
+		return fmt.Errorf("schema version %d is behind %d, run `migrate up` first", version, latest)
+	}
+	if version > latest {
+		Log.Warn("schema version %d is newer than %d known to this build", version, latest)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package data
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// sqliteSchema 返回除 schema_version 和 SQLite 内部表外所有表和索引的定义
+func sqliteSchema(t *testing.T, p *DB) map[string]string {
+	t.Helper()
+	rows, err := p.db.Query("SELECT name, sql FROM sqlite_master WHERE sql IS NOT NULL AND name <> 'schema_version' AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	defer rows.Close()
+	schema := make(map[string]string)
+	for rows.Next() {
+		var name, sql string
+		err = rows.Scan(&name, &sql)
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		schema[name] = sql
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatalf("rows.Err: %v", err)
+	}
+	return schema
+}
+
+// TestMigrateUpDownUp 逐个回滚所有迁移后表结构为空，再次迁移得到相同的表结构
+func TestMigrateUpDownUp(t *testing.T) {
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "migrate.db"))
+	p := NewSqliteStorage()
+	err := p.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+
+	migrations, err := loadMigrations(driverSqlite)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	latest, err := p.LatestSchemaVersion()
+	if err != nil {
+		t.Fatalf("LatestSchemaVersion: %v", err)
+	}
+
+	done, err := p.MigrateUp(0)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if len(done) != len(migrations) {
+		t.Fatalf("MigrateUp applied %d migrations, want %d", len(done), len(migrations))
+	}
+	upSchema := sqliteSchema(t, p)
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		done, err = p.MigrateDown(1)
+		if err != nil {
+			t.Fatalf("MigrateDown: %v", err)
+		}
+		if len(done) != 1 || done[0].Version != migrations[i].Version {
+			t.Fatalf("MigrateDown rolled back %v, want %d_%s", done, migrations[i].Version, migrations[i].Name)
+		}
+		var want uint64
+		if i > 0 {
+			want = migrations[i-1].Version
+		}
+		version, err := p.SchemaVersion()
+		if err != nil {
+			t.Fatalf("SchemaVersion: %v", err)
+		}
+		if version != want {
+			t.Fatalf("schema version %d after rolling back %d, want %d", version, migrations[i].Version, want)
+		}
+	}
+	if schema := sqliteSchema(t, p); len(schema) != 0 {
+		t.Fatalf("tables left after rolling back every migration: %v", schema)
+	}
+	done, err = p.MigrateDown(1)
+	if err != nil || len(done) != 0 {
+		t.Fatalf("MigrateDown on an empty schema: %v %v", done, err)
+	}
+
+	done, err = p.MigrateUp(0)
+	if err != nil {
+		t.Fatalf("MigrateUp again: %v", err)
+	}
+	if len(done) != len(migrations) {
+		t.Fatalf("MigrateUp again applied %d migrations, want %d", len(done), len(migrations))
+	}
+	version, err := p.SchemaVersion()
+	if err != nil || version != latest {
+		t.Fatalf("schema version %d, %v, want %d", version, err, latest)
+	}
+	if schema := sqliteSchema(t, p); !reflect.DeepEqual(schema, upSchema) {
+		t.Fatalf("schema after down and up differs:\n%v\nwant\n%v", schema, upSchema)
+	}
+}
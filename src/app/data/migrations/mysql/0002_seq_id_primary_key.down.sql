@@ -0,0 +1,3 @@
+ALTER TABLE tb_seq_id_user DROP PRIMARY KEY, ADD PRIMARY KEY (user_id, seq_id);
+ALTER TABLE tb_seq_id_chat DROP PRIMARY KEY, ADD PRIMARY KEY (user1_id, user2_id, seq_id);
+ALTER TABLE tb_seq_id_group DROP PRIMARY KEY, ADD PRIMARY KEY (group_id, seq_id);
@@ -0,0 +1,9 @@
+-- 计数器表每个 key 只保留一行，主键改为 key 本身，供原子自增使用
+DELETE t1 FROM tb_seq_id_user t1 JOIN tb_seq_id_user t2 ON t1.user_id = t2.user_id AND t1.seq_id < t2.seq_id;
+ALTER TABLE tb_seq_id_user DROP PRIMARY KEY, ADD PRIMARY KEY (user_id);
+
+DELETE t1 FROM tb_seq_id_chat t1 JOIN tb_seq_id_chat t2 ON t1.user1_id = t2.user1_id AND t1.user2_id = t2.user2_id AND t1.seq_id < t2.seq_id;
+ALTER TABLE tb_seq_id_chat DROP PRIMARY KEY, ADD PRIMARY KEY (user1_id, user2_id);
+
+DELETE t1 FROM tb_seq_id_group t1 JOIN tb_seq_id_group t2 ON t1.group_id = t2.group_id AND t1.seq_id < t2.seq_id;
+ALTER TABLE tb_seq_id_group DROP PRIMARY KEY, ADD PRIMARY KEY (group_id);
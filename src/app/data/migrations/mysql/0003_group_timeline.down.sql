@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tb_group_cursor;
+DROP TABLE IF EXISTS tb_seq_id_group_timeline;
+DROP TABLE IF EXISTS tb_group_timeline;
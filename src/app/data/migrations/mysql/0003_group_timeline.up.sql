@@ -0,0 +1,32 @@
+-- 大群读扩散：群消息只在时间线中保存一份，成员拉取时按游标同步到自己的收件箱
+CREATE TABLE IF NOT EXISTS tb_group_timeline (
+	group_id BIGINT UNSIGNED NOT NULL,
+	seq_id BIGINT UNSIGNED NOT NULL,
+
+	sender_id BIGINT UNSIGNED NOT NULL,
+
+	conv_msg_id BIGINT UNSIGNED NOT NULL,
+	rand_msg_id BIGINT UNSIGNED DEFAULT 0,
+	message_type INT NOT NULL,
+	content TEXT NOT NULL,
+	read_msg_id BIGINT UNSIGNED DEFAULT 0,
+
+	sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+	PRIMARY KEY (group_id, seq_id)
+);
+
+-- 时间线 seqId 计数器。有此行的群即为读扩散模式
+CREATE TABLE IF NOT EXISTS tb_seq_id_group_timeline (
+	group_id BIGINT UNSIGNED,
+	seq_id BIGINT UNSIGNED NOT NULL,
+	PRIMARY KEY (group_id)
+);
+
+-- 成员已同步到收件箱的时间线 seqId
+CREATE TABLE IF NOT EXISTS tb_group_cursor (
+	group_id BIGINT UNSIGNED,
+	user_id BIGINT UNSIGNED,
+	seq_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+	PRIMARY KEY (group_id, user_id)
+);
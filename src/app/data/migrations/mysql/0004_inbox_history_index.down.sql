@@ -0,0 +1,2 @@
+DROP INDEX idx_inbox_peer_conv ON tb_user_inbox;
+DROP INDEX idx_inbox_group_conv ON tb_user_inbox;
@@ -0,0 +1 @@
+ALTER TABLE tb_users MODIFY password VARCHAR(100) NOT NULL;
@@ -0,0 +1,2 @@
+-- 密码哈希改为 argon2id（PHC 字符串），预留更换算法的长度
+ALTER TABLE tb_users MODIFY password VARCHAR(255) NOT NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS tb_seq_id_group;
+DROP TABLE IF EXISTS tb_seq_id_chat;
+DROP TABLE IF EXISTS tb_seq_id_user;
+DROP TABLE IF EXISTS tb_user_inbox;
+DROP TABLE IF EXISTS tb_group_members;
+DROP TABLE IF EXISTS tb_groups;
+DROP TABLE IF EXISTS tb_user_contacts;
+DROP TABLE IF EXISTS tb_users;
@@ -0,0 +1,27 @@
+CREATE TABLE tb_seq_id_user_old (
+    user_id BIGINT UNSIGNED,
+    seq_id BIGINT UNSIGNED,
+    PRIMARY KEY (user_id, seq_id)
+);
+INSERT INTO tb_seq_id_user_old (user_id, seq_id) SELECT user_id, seq_id FROM tb_seq_id_user;
+DROP TABLE tb_seq_id_user;
+ALTER TABLE tb_seq_id_user_old RENAME TO tb_seq_id_user;
+
+CREATE TABLE tb_seq_id_chat_old (
+    user1_id BIGINT UNSIGNED,
+    user2_id BIGINT UNSIGNED,
+    seq_id BIGINT UNSIGNED,
+    PRIMARY KEY (user1_id, user2_id, seq_id)
+);
+INSERT INTO tb_seq_id_chat_old (user1_id, user2_id, seq_id) SELECT user1_id, user2_id, seq_id FROM tb_seq_id_chat;
+DROP TABLE tb_seq_id_chat;
+ALTER TABLE tb_seq_id_chat_old RENAME TO tb_seq_id_chat;
+
+CREATE TABLE tb_seq_id_group_old (
+    group_id BIGINT UNSIGNED,
+    seq_id BIGINT UNSIGNED,
+    PRIMARY KEY (group_id, seq_id)
+);
+INSERT INTO tb_seq_id_group_old (group_id, seq_id) SELECT group_id, seq_id FROM tb_seq_id_group;
+DROP TABLE tb_seq_id_group;
+ALTER TABLE tb_seq_id_group_old RENAME TO tb_seq_id_group;
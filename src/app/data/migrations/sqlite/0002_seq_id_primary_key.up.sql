@@ -0,0 +1,28 @@
+-- 计数器表每个 key 只保留一行，主键改为 key 本身，供原子自增使用。SQLite 不能修改主键，只能重建表
+CREATE TABLE tb_seq_id_user_new (
+    user_id BIGINT UNSIGNED,
+    seq_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (user_id)
+);
+INSERT INTO tb_seq_id_user_new (user_id, seq_id) SELECT user_id, MAX(seq_id) FROM tb_seq_id_user GROUP BY user_id;
+DROP TABLE tb_seq_id_user;
+ALTER TABLE tb_seq_id_user_new RENAME TO tb_seq_id_user;
+
+CREATE TABLE tb_seq_id_chat_new (
+    user1_id BIGINT UNSIGNED,
+    user2_id BIGINT UNSIGNED,
+    seq_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (user1_id, user2_id)
+);
+INSERT INTO tb_seq_id_chat_new (user1_id, user2_id, seq_id) SELECT user1_id, user2_id, MAX(seq_id) FROM tb_seq_id_chat GROUP BY user1_id, user2_id;
+DROP TABLE tb_seq_id_chat;
+ALTER TABLE tb_seq_id_chat_new RENAME TO tb_seq_id_chat;
+
+CREATE TABLE tb_seq_id_group_new (
+    group_id BIGINT UNSIGNED,
+    seq_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (group_id)
+);
+INSERT INTO tb_seq_id_group_new (group_id, seq_id) SELECT group_id, MAX(seq_id) FROM tb_seq_id_group GROUP BY group_id;
+DROP TABLE tb_seq_id_group;
+ALTER TABLE tb_seq_id_group_new RENAME TO tb_seq_id_group;
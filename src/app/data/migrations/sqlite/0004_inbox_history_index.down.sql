@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_inbox_peer_conv;
+DROP INDEX IF EXISTS idx_inbox_group_conv;
@@ -0,0 +1,3 @@
+-- 按会话翻页读取历史消息
+CREATE INDEX idx_inbox_group_conv ON tb_user_inbox (user_id, group_id, conv_msg_id);
+CREATE INDEX idx_inbox_peer_conv ON tb_user_inbox (user_id, sender_id, receiver_id, conv_msg_id);
@@ -0,0 +1,26 @@
+-- 超出保留策略的收件箱消息移到归档表，历史消息接口仍可读取
+CREATE TABLE IF NOT EXISTS tb_user_inbox_archive (
+    user_id BIGINT UNSIGNED NOT NULL,
+    seq_id BIGINT UNSIGNED NOT NULL,
+
+    sender_id BIGINT UNSIGNED NOT NULL,
+    receiver_id BIGINT UNSIGNED,
+    group_id BIGINT UNSIGNED,
+
+    conv_msg_id BIGINT UNSIGNED NOT NULL,
+    rand_msg_id BIGINT UNSIGNED DEFAULT 0,
+    message_type INT NOT NULL,
+    content TEXT NOT NULL,
+    read_msg_id BIGINT UNSIGNED DEFAULT 0,
+
+    is_read BOOLEAN DEFAULT FALSE,
+    status INT DEFAULT 0,
+
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    archived_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, seq_id)
+);
+
+CREATE INDEX idx_archive_group_conv ON tb_user_inbox_archive (user_id, group_id, conv_msg_id);
+CREATE INDEX idx_archive_peer_conv ON tb_user_inbox_archive (user_id, sender_id, receiver_id, conv_msg_id);
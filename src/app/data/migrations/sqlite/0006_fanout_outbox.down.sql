@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tb_fanout_applied;
+DROP TABLE IF EXISTS tb_fanout_outbox;
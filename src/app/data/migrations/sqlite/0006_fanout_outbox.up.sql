@@ -0,0 +1,23 @@
+-- 跨分片扇出：消息先写入主库的 outbox，再写入各分片，全部完成后删除。
+-- 分片在同一事务中记录已写入的 outbox id，重试时跳过
+CREATE TABLE IF NOT EXISTS tb_fanout_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    sender_id BIGINT UNSIGNED NOT NULL,
+    receiver_id BIGINT UNSIGNED,
+    group_id BIGINT UNSIGNED,
+
+    conv_msg_id BIGINT UNSIGNED NOT NULL,
+    rand_msg_id BIGINT UNSIGNED DEFAULT 0,
+    message_type INT NOT NULL,
+    content TEXT NOT NULL,
+    read_msg_id BIGINT UNSIGNED DEFAULT 0,
+
+    target_uids TEXT NOT NULL,     -- 逗号分隔的接收者 uid
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tb_fanout_applied (
+    outbox_id BIGINT UNSIGNED PRIMARY KEY,
+    applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+-- SQLite 不限制 VARCHAR 长度，无需修改表结构
@@ -0,0 +1 @@
+-- SQLite 不限制 VARCHAR 长度，密码哈希改为 argon2id 无需修改表结构
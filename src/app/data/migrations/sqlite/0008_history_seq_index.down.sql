@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_archive_peer_seq;
+DROP INDEX IF EXISTS idx_archive_group_seq;
+CREATE INDEX idx_archive_group_conv ON tb_user_inbox_archive (user_id, group_id, conv_msg_id);
+CREATE INDEX idx_archive_peer_conv ON tb_user_inbox_archive (user_id, sender_id, receiver_id, conv_msg_id);
+DROP INDEX IF EXISTS idx_inbox_peer_seq;
+DROP INDEX IF EXISTS idx_inbox_group_seq;
+CREATE INDEX idx_inbox_group_conv ON tb_user_inbox (user_id, group_id, conv_msg_id);
+CREATE INDEX idx_inbox_peer_conv ON tb_user_inbox (user_id, sender_id, receiver_id, conv_msg_id);
@@ -0,0 +1,9 @@
+-- 历史消息改为按 seqId 翻页，convMsgId 不一定与写入顺序一致
+DROP INDEX IF EXISTS idx_inbox_group_conv;
+DROP INDEX IF EXISTS idx_inbox_peer_conv;
+CREATE INDEX idx_inbox_group_seq ON tb_user_inbox (user_id, group_id, seq_id);
+CREATE INDEX idx_inbox_peer_seq ON tb_user_inbox (user_id, sender_id, receiver_id, seq_id);
+DROP INDEX IF EXISTS idx_archive_group_conv;
+DROP INDEX IF EXISTS idx_archive_peer_conv;
+CREATE INDEX idx_archive_group_seq ON tb_user_inbox_archive (user_id, group_id, seq_id);
+CREATE INDEX idx_archive_peer_seq ON tb_user_inbox_archive (user_id, sender_id, receiver_id, seq_id);
@@ -0,0 +1,252 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	. "social_server/src/utils/log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 读写分离：
+// 只读查询按 readKey 路由。key 近期没有写入时走延迟不超过上限的副本，否则走主库，保证读到自己的写入。
+// 本节点的写入直接标记；其他节点的写入通过消息通知到达后由业务层调用 MarkUserWritten 标记。
+// 副本不可用或查询失败时退回主库
+
+// 副本延迟检查间隔
+const replicaCheckInterval = time.Second
+
+// readKey 判断近期是否有写入的粒度
+type readKey struct {
+	isGroup bool
+	id      uint64
+}
+
+func userKey(uid uint64) readKey {
+	return readKey{id: uid}
+}
+
+func groupKey(groupId uint64) readKey {
+	return readKey{isGroup: true, id: groupId}
+}
+
+type replica struct {
+	addr   string
+	db     *sql.DB
+	usable atomic.Bool
+}
+
+type replicaSet struct {
+	replicas []*replica
+	maxLag   time.Duration
+	// 写入后在此时间内读主库。延迟按秒取整且每个检查间隔才更新一次，各加一个间隔的余量
+	stickyWindow time.Duration
+	next         atomic.Uint32
+
+	mu           sync.Mutex
+	recentWrites map[readKey]time.Time
+}
+
+// newReplicaSet addrs 为副本地址，dsnOf 生成对应的 DSN。没有副本时返回 nil，所有读取走主库
+func newReplicaSet(driver string, addrs []string, dsnOf func(addr string) string, maxLag time.Duration) (*replicaSet, error) {
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+	s := &replicaSet{
+		maxLag:       maxLag,
+		stickyWindow: maxLag + 2*replicaCheckInterval,
+		recentWrites: make(map[readKey]time.Time),
+	}
+	for _, addr := range addrs {
+		db, err := sql.Open(driver, dsnOf(addr))
+		if err != nil {
+			return nil, fmt.Errorf("open replica %s: %w", addr, err)
+		}
+		db.SetConnMaxLifetime(30 * time.Second)
+		db.SetMaxOpenConns(10)
+		db.SetMaxIdleConns(10)
+		s.replicas = append(s.replicas, &replica{addr: addr, db: db})
+	}
+	return s, nil
+}
+
+func (s *replicaSet) start() {
+	if s == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(replicaCheckInterval)
+		defer ticker.Stop()
+		for {
+			s.check()
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *replicaSet) close() {
+	if s == nil {
+		return
+	}
+	for _, r := range s.replicas {
+		r.db.Close()
+	}
+}
+
+// check 更新副本是否可用，并清理过期的写入标记
+func (s *replicaSet) check() {
+	for _, r := range s.replicas {
+		lag, err := replicaLag(r.db)
+		if err == nil && lag > s.maxLag {
+			err = fmt.Errorf("lag %v exceeds %v", lag, s.maxLag)
+		}
+		usable := err == nil
+		if r.usable.Swap(usable) != usable {
+			if usable {
+				Log.Info("Replica %s is usable", r.addr)
+			} else {
+				Log.Warn("Replica %s is not usable: %v", r.addr, err)
+			}
+		}
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	for key, until := range s.recentWrites {
+		if now.After(until) {
+			delete(s.recentWrites, key)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// replicaLag 查询复制延迟。MySQL 8.0.22 起为 SHOW REPLICA STATUS，之前为 SHOW SLAVE STATUS
+func replicaLag(db *sql.DB) (time.Duration, error) {
+	rows, err := db.Query("SHOW REPLICA STATUS")
+	if err != nil {
+		rows, err = db.Query("SHOW SLAVE STATUS")
+		if err != nil {
+			return 0, fmt.Errorf("Query: %w", err)
+		}
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("Columns: %w", err)
+	}
+	if !rows.Next() {
+		return 0, errors.New("not a replica")
+	}
+	values := make([]sql.NullString, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	err = rows.Scan(ptrs...)
+	if err != nil {
+		return 0, fmt.Errorf("Scan: %w", err)
+	}
+	for i, col := range cols {
+		if col != "Seconds_Behind_Source" && col != "Seconds_Behind_Master" {
+			continue
+		}
+		// 为 NULL 时复制线程未运行
+		if !values[i].Valid {
+			return 0, errors.New("replication is not running")
+		}
+		secs, err := strconv.ParseUint(values[i].String, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("ParseUint: %w", err)
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+	return 0, errors.New("replication lag column not found")
+}
+
+// markWritten 标记 key 刚有写入
+func (s *replicaSet) markWritten(keys ...readKey) {
+	if s == nil || len(keys) == 0 {
+		return
+	}
+	until := time.Now().Add(s.stickyWindow)
+	s.mu.Lock()
+	for _, key := range keys {
+		s.recentWrites[key] = until
+	}
+	s.mu.Unlock()
+}
+
+// pick 选择一个可用副本，key 近期有写入或没有可用副本时返回 nil
+func (s *replicaSet) pick(key readKey) *replica {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	until, written := s.recentWrites[key]
+	s.mu.Unlock()
+	if written && time.Now().Before(until) {
+		return nil
+	}
+
+	n := uint32(len(s.replicas))
+	start := s.next.Add(1)
+	for i := uint32(0); i < n; i++ {
+		r := s.replicas[(start+i)%n]
+		if r.usable.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// markDown 查询失败的副本在下次检查前不再使用
+func (r *replica) markDown(err error) {
+	if r.usable.Swap(false) {
+		Log.Warn("Replica %s is not usable: %v", r.addr, err)
+	}
+}
+
+// readQueryRows 只读查询，优先走副本
+func (p *DB) readQueryRows(ctx context.Context, key readKey, query string, args ...interface{}) (*sql.Rows, error) {
+	if r := p.replicas.pick(key); r != nil {
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err == nil {
+			return rows, nil
+		}
+		// 请求取消或超时不是副本的问题，主库也不用再查
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		r.markDown(err)
+	}
+	return p.queryRows(ctx, query, args...)
+}
+
+// readQueryRow 只读查询单行，优先走副本
+func (p *DB) readQueryRow(ctx context.Context, key readKey, query string, args ...interface{}) (*sql.Row, error) {
+	if r := p.replicas.pick(key); r != nil {
+		row := r.db.QueryRowContext(ctx, query, args...)
+		if row.Err() == nil {
+			return row, nil
+		}
+		if ctx.Err() != nil {
+			return nil, row.Err()
+		}
+		r.markDown(row.Err())
+	}
+	return p.queryRow(ctx, query, args...)
+}
+
+// markWritten 标记本节点的写入，之后一段时间内相关读取走主库
+func (p *DB) markWritten(keys ...readKey) {
+	p.replicas.markWritten(keys...)
+}
+
+func (p *DB) MarkUserWritten(uid uint64) {
+	p.replicas.markWritten(userKey(uid))
+}
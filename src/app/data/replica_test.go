@@ -0,0 +1,51 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	. "social_server/src/utils/log"
+	"testing"
+	"time"
+)
+
+// TestReplicaMarkDownOnlyOnReplicaError 请求取消导致的查询失败不应停用副本
+func TestReplicaMarkDownOnlyOnReplicaError(t *testing.T) {
+	setupLoggerOnce.Do(SetupLogger)
+	dir := t.TempDir()
+	p := newTestSqliteDB(t, filepath.Join(dir, "primary.db"), "1")
+	// 副本是一个没有表的空库，查询表时失败
+	replicaDB, err := sql.Open(driverSqlite, "file:"+filepath.Join(dir, "replica.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { replicaDB.Close() })
+	r := &replica{addr: "replica", db: replicaDB}
+	r.usable.Store(true)
+	p.replicas = &replicaSet{replicas: []*replica{r}, recentWrites: make(map[readKey]time.Time)}
+
+	const query = "SELECT COUNT(*) FROM tb_users"
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = p.readQueryRows(ctx, userKey(1), query)
+	if err == nil {
+		t.Fatalf("readQueryRows with a canceled ctx succeeded")
+	}
+	_, err = p.readQueryRow(ctx, userKey(1), query)
+	if err == nil {
+		t.Fatalf("readQueryRow with a canceled ctx succeeded")
+	}
+	if !r.usable.Load() {
+		t.Fatalf("replica marked down after a canceled request")
+	}
+
+	// 副本自身出错时停用并退回主库
+	rows, err := p.readQueryRows(context.Background(), userKey(1), query)
+	if err != nil {
+		t.Fatalf("readQueryRows: %v", err)
+	}
+	rows.Close()
+	if r.usable.Load() {
+		t.Fatalf("replica still usable after a failed query")
+	}
+}
@@ -0,0 +1,203 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// seqSegment 已租用的号段 [next, end)
+type seqSegment struct {
+	mu   sync.Mutex
+	next uint64
+	end  uint64
+}
+
+// SeqAllocator 号段式 seqId 分配器。
+// 每次从数据库租用 segmentSize 个连续 id，用完再租，期间分配不访问数据库。
+// 同一个 key 分配出的 id 唯一且严格递增；重启后未用完的号段作废，id 会跳号。
+// 多个节点同时为同一个 key 分配时，各节点的号段交错，只能保证唯一，不能保证跨节点递增。
+// 号段分配出的 id 与写入不在同一个事务中，提交顺序可能与 id 顺序不同，
+// 所以只用于会话和群的消息 id；收件箱 seqId 是客户端拉取的游标，在写入事务中分配，见 allocateUserSeqIdsTx
+type SeqAllocator[K comparable] struct {
+	segmentSize uint64
+	// lease 原子地租用 n 个连续 id，返回第一个
+	lease func(ctx context.Context, key K, n uint64) (first uint64, err error)
+
+	mu       sync.Mutex
+	segments map[K]*seqSegment
+}
+
+func NewSeqAllocator[K comparable](segmentSize uint64, lease func(ctx context.Context, key K, n uint64) (uint64, error)) *SeqAllocator[K] {
+	if segmentSize == 0 {
+		segmentSize = 1
+	}
+	return &SeqAllocator[K]{
+		segmentSize: segmentSize,
+		lease:       lease,
+		segments:    make(map[K]*seqSegment),
+	}
+}
+
+func (p *SeqAllocator[K]) segment(key K) *seqSegment {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	seg, ok := p.segments[key]
+	if !ok {
+		seg = &seqSegment{}
+		p.segments[key] = seg
+	}
+	return seg
+}
+
+// Alloc 分配 n 个连续 id，返回第一个
+func (p *SeqAllocator[K]) Alloc(ctx context.Context, key K, n uint64) (first uint64, err error) {
+	if n == 0 {
+		return 0, fmt.Errorf("n must be greater than zero")
+	}
+	// 不预分配时直接租用，不缓存号段
+	if p.segmentSize == 1 {
+		return p.lease(ctx, key, n)
+	}
+
+	seg := p.segment(key)
+	seg.mu.Lock()
+	defer seg.mu.Unlock()
+
+	if seg.end-seg.next < n {
+		// 剩余不足时整段作废，重新租用，保证返回的 id 连续
+		size := p.segmentSize
+		if n > size {
+			size = n
+		}
+		first, err = p.lease(ctx, key, size)
+		if err != nil {
+			return 0, err
+		}
+		seg.next = first
+		seg.end = first + size
+	}
+
+	first = seg.next
+	seg.next += n
+	return first, nil
+}
+
+// seqUpsertSql 计数器加一个增量，不存在时插入。每行的参数为 key 和初始值，最后一个参数为增量
+func (p *DB) seqUpsertSql(table string, keyCols []string, rows int) string {
+	row := "(" + strings.Repeat("?, ", len(keyCols)) + "?)"
+	values := strings.TrimSuffix(strings.Repeat(row+", ", rows), ", ")
+	if p.driver == driverSqlite {
+		return fmt.Sprintf("INSERT INTO %s (%s, seq_id) VALUES %s ON CONFLICT (%s) DO UPDATE SET seq_id = seq_id + ?",
+			table, strings.Join(keyCols, ", "), values, strings.Join(keyCols, ", "))
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s, seq_id) VALUES %s ON DUPLICATE KEY UPDATE seq_id = seq_id + ?",
+		table, strings.Join(keyCols, ", "), values)
+}
+
+// allocateSeqIdsTx 在事务 tx 中把计数器加 n，返回分配到的第一个 id。
+// 计数器保存下一个可分配的 id，不存在时从 1 开始；行锁一直持有到事务提交
+func (p *DB) allocateSeqIdsTx(ctx context.Context, tx *sql.Tx, table string, keyCols []string, keyVals []interface{}, n uint64) (first uint64, err error) {
+	args := append(append([]interface{}{}, keyVals...), 1+n, n)
+	_, err = p.sqlTxExec(ctx, tx, p.seqUpsertSql(table, keyCols, 1), args...)
+	if err != nil {
+		return 0, fmt.Errorf("sqlTxExec: %w", err)
+	}
+
+	row, err := p.sqlTxQueryRow(ctx, tx, fmt.Sprintf("SELECT seq_id FROM %s WHERE %s = ?", table, strings.Join(keyCols, " = ? AND ")), keyVals...)
+	if err != nil {
+		return 0, fmt.Errorf("sqlTxQueryRow: %w", err)
+	}
+	var next uint64
+	err = row.Scan(&next)
+	if err != nil {
+		return 0, fmt.Errorf("Scan: %w", err)
+	}
+	return next - n, nil
+}
+
+// leaseSeqIds 原子地把计数器加 n，返回租到的第一个 id
+func (p *DB) leaseSeqIds(ctx context.Context, table string, keyCols []string, keyVals []interface{}, n uint64) (first uint64, err error) {
+	tx, err := p.beginTx(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("beginTx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 行锁保证并发租用互斥
+	first, err = p.allocateSeqIdsTx(ctx, tx, table, keyCols, keyVals, n)
+	if err != nil {
+		return 0, fmt.Errorf("allocateSeqIdsTx: %w", err)
+	}
+
+	err = p.sqlTxCommit(tx)
+	if err != nil {
+		return 0, fmt.Errorf("sqlTxCommit: %w", err)
+	}
+	return first, nil
+}
+
+// allocateUserSeqIdsTx 在事务 tx 中为多个用户各分配一个 seqId，uids 须已去重。
+// 计数器行锁一直持有到事务提交，保证同一用户的消息按 seqId 顺序可见
+func (p *DB) allocateUserSeqIdsTx(ctx context.Context, tx *sql.Tx, uids []uint64) (seqIds map[uint64]uint64, err error) {
+	seqIds = make(map[uint64]uint64, len(uids))
+	// 按 uid 排序加锁，避免并发扇出时死锁
+	sorted := append([]uint64{}, uids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for start := 0; start < len(sorted); start += sqlBatchSize {
+		end := start + sqlBatchSize
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		batch := sorted[start:end]
+
+		var upsertArgs, uidArgs []interface{}
+		for _, uid := range batch {
+			upsertArgs = append(upsertArgs, uid, 2)
+			uidArgs = append(uidArgs, uid)
+		}
+		upsertArgs = append(upsertArgs, 1)
+		_, err = p.sqlTxExec(ctx, tx, p.seqUpsertSql("tb_seq_id_user", []string{"user_id"}, len(batch)), upsertArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("sqlTxExec: %w", err)
+		}
+
+		rows, err := p.sqlTxQueryRows(ctx, tx, fmt.Sprintf("SELECT user_id, seq_id FROM tb_seq_id_user WHERE user_id IN (%s)",
+			sqlPlaceholders(len(batch))), uidArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("sqlTxQueryRows: %w", err)
+		}
+		for rows.Next() {
+			var uid, next uint64
+			err = rows.Scan(&uid, &next)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("Scan: %w", err)
+			}
+			seqIds[uid] = next - 1
+		}
+		rows.Close()
+	}
+	return seqIds, nil
+}
+
+// 计数器按各自的 key 路由到分片
+func (p *DB) leaseUserSeqIds(ctx context.Context, uid uint64, n uint64) (uint64, error) {
+	return p.userShard(uid).leaseSeqIds(ctx, "tb_seq_id_user", []string{"user_id"}, []interface{}{uid}, n)
+}
+
+func (p *DB) leaseChatSeqIds(ctx context.Context, uids [2]uint64, n uint64) (uint64, error) {
+	return p.chatShard(uids).leaseSeqIds(ctx, "tb_seq_id_chat", []string{"user1_id", "user2_id"}, []interface{}{uids[0], uids[1]}, n)
+}
+
+func (p *DB) leaseGroupSeqIds(ctx context.Context, groupId uint64, n uint64) (uint64, error) {
+	return p.groupShard(groupId).leaseSeqIds(ctx, "tb_seq_id_group", []string{"group_id"}, []interface{}{groupId}, n)
+}
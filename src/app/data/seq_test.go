@@ -0,0 +1,214 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"social_server/src/app/common/types"
+	"sync"
+	"testing"
+)
+
+// checkSeqIds 检查所有 id 唯一，且每个协程拿到的 id 严格递增
+func checkSeqIds(t *testing.T, perWorker [][]uint64) map[uint64]bool {
+	t.Helper()
+	seen := make(map[uint64]bool)
+	for w, ids := range perWorker {
+		for i, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate seq id %d", id)
+			}
+			seen[id] = true
+			if i > 0 && id <= ids[i-1] {
+				t.Fatalf("worker %d: seq id %d after %d is not increasing", w, id, ids[i-1])
+			}
+		}
+	}
+	return seen
+}
+
+func TestSeqAllocatorConcurrent(t *testing.T) {
+	const (
+		workers   = 32
+		allocsPer = 500
+		keys      = 3
+	)
+
+	var mu sync.Mutex
+	counters := make(map[uint64]uint64)
+	leases := 0
+	lease := func(ctx context.Context, key uint64, n uint64) (uint64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		leases++
+		first := counters[key] + 1
+		counters[key] += n
+		return first, nil
+	}
+
+	for _, segmentSize := range []uint64{1, 7, 64} {
+		counters = make(map[uint64]uint64)
+		leases = 0
+		alloc := NewSeqAllocator(segmentSize, lease)
+
+		perWorker := make([][][]uint64, keys)
+		for k := range perWorker {
+			perWorker[k] = make([][]uint64, workers)
+		}
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < allocsPer; i++ {
+					key := uint64(i % keys)
+					// 偶尔批量分配，覆盖号段剩余不足的情况
+					n := uint64(1 + i%3)
+					first, err := alloc.Alloc(context.Background(), key, n)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					for j := uint64(0); j < n; j++ {
+						perWorker[key][w] = append(perWorker[key][w], first+j)
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		total := 0
+		for k := 0; k < keys; k++ {
+			total += len(checkSeqIds(t, perWorker[k]))
+		}
+		if segmentSize > 1 && leases >= total {
+			t.Fatalf("segment size %d: %d leases for %d ids, segments not reused", segmentSize, leases, total)
+		}
+	}
+}
+
+func newTestSqliteDB(t *testing.T, path string, segmentSize string) *DB {
+	t.Helper()
+	t.Setenv("SQLITE_PATH", path)
+	t.Setenv("SEQ_SEGMENT_SIZE", segmentSize)
+	p := NewSqliteStorage()
+	err := p.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	_, err = p.MigrateUp(0)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	return p
+}
+
+func TestDBAllocateSeqIdConcurrent(t *testing.T) {
+	const (
+		workers   = 16
+		allocsPer = 50
+	)
+
+	for _, segmentSize := range []string{"1", "10"} {
+		path := filepath.Join(t.TempDir(), "seq.db")
+		p := newTestSqliteDB(t, path, segmentSize)
+
+		allocate := map[string]func() (uint64, error){
+			"user":  func() (uint64, error) { return p.AllocateSeqId(context.Background(), 1) },
+			"chat":  func() (uint64, error) { return p.AllocateChatSeqId(context.Background(), 2, 1) },
+			"group": func() (uint64, error) { return p.AllocateGroupSeqId(context.Background(), 1) },
+		}
+		for name, fn := range allocate {
+			perWorker := make([][]uint64, workers)
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < allocsPer; i++ {
+						id, err := fn()
+						if err != nil {
+							t.Errorf("%s: %v", name, err)
+							return
+						}
+						perWorker[w] = append(perWorker[w], id)
+					}
+				}(w)
+			}
+			wg.Wait()
+
+			seen := checkSeqIds(t, perWorker)
+			if len(seen) != workers*allocsPer {
+				t.Fatalf("%s: got %d ids, want %d", name, len(seen), workers*allocsPer)
+			}
+		}
+
+		// 重启后从数据库中的计数器继续分配，不会与之前的 id 重复
+		p.Close()
+		restarted := newTestSqliteDB(t, path, segmentSize)
+		id, err := restarted.AllocateSeqId(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= workers*allocsPer {
+			t.Fatalf("seq id %d after restart reuses an allocated id", id)
+		}
+	}
+}
+
+// TestInboxSeqIdCommitOrder 两个节点使用号段时，先租用的节点后提交。
+// 客户端按 seqId 游标拉取，后提交的消息 seqId 须大于已拉取到的游标，否则会被漏掉
+func TestInboxSeqIdCommitOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seq.db")
+	nodeA := newTestSqliteDB(t, path, "10")
+	nodeB := newTestSqliteDB(t, path, "10")
+	ctx := context.Background()
+
+	const sender, receiver = 1, 2
+	send := func(node *DB, convMsgId uint64, content string) {
+		t.Helper()
+		err := node.ChatSendMsgToUser(ctx, receiver, types.ChatMsgOfConv{
+			ConvMsgId:  convMsgId,
+			RandMsgId:  convMsgId,
+			ReceiverId: types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: receiver},
+			Msg:        types.ChatMsg{SenderUid: sender, MsgContent: content},
+		})
+		if err != nil {
+			t.Fatalf("ChatSendMsgToUser: %v", err)
+		}
+	}
+	alloc := func(node *DB) uint64 {
+		t.Helper()
+		id, err := node.AllocateChatSeqId(ctx, sender, receiver)
+		if err != nil {
+			t.Fatalf("AllocateChatSeqId: %v", err)
+		}
+		return id
+	}
+
+	// A 先租用号段并写入一条消息，然后 B 租用
+	send(nodeA, alloc(nodeA), "a0")
+	idA := alloc(nodeA)
+	idB := alloc(nodeB)
+
+	// B 先提交，客户端拉取到 B 的消息
+	send(nodeB, idB, "b")
+	msgs, err := nodeB.ChatGetMsgList(ctx, receiver, 0, 100)
+	if err != nil {
+		t.Fatalf("ChatGetMsgList: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	cursor := msgs[len(msgs)-1].SeqId
+
+	// A 后提交，从游标之后继续拉取仍能收到
+	send(nodeA, idA, "a1")
+	msgs, err = nodeB.ChatGetMsgList(ctx, receiver, cursor, 100)
+	if err != nil {
+		t.Fatalf("ChatGetMsgList: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].ConvMsgId != idA {
+		t.Fatalf("got %v after seq id %d, want the message committed last", msgs, cursor)
+	}
+}
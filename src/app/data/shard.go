@@ -0,0 +1,391 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"social_server/src/app/common/types"
+	"social_server/src/gen/grpc"
+	. "social_server/src/utils/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 分片：
+// 用户、联系人、群和群成员保存在主库。收件箱、归档、用户 seqId 计数器和群游标按 user_id 路由到分片，
+// 群 seqId 计数器和群时间线按 group_id 路由，单聊 seqId 计数器按较小的 uid 路由。
+// 未配置分片时只有主库自身一个分片。分片数确定后不能再更改，否则已有数据会路由错位。
+//
+// 一条消息的接收者分布在多个分片时，先把消息和接收者写入主库的 tb_fanout_outbox，再逐个分片写入收件箱，
+// 全部成功后删除 outbox 行。分片在写收件箱的同一事务中记录 outbox id，后台任务重放未完成的 outbox 时跳过已写入的分片，
+// 所以 outbox 写入成功后消息不会丢失也不会重复
+
+const (
+	// 后台重放间隔，以及 outbox 行至少存在多久才重放，避免与正在进行的扇出重复劳动
+	fanOutRedriveInterval = 5 * time.Second
+	fanOutRedriveDelay    = 10 * time.Second
+	fanOutRedriveBatch    = 100
+	// 已完成的写入标记保留时间
+	fanOutAppliedRetention = time.Hour
+)
+
+// parseHostList 解析逗号分隔的 host 或 host:port，缺少端口时使用 defPort
+func parseHostList(s string, defPort string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(s, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, defPort)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// newShards 按 DB_SHARD_HOSTS 创建分片，未配置时返回 nil
+func newShards(dsnOf func(addr string) string) []*DB {
+	var shards []*DB
+	for _, addr := range parseHostList(os.Getenv("DB_SHARD_HOSTS"), "3306") {
+		shards = append(shards, newDB(driverMysql, dsnOf(addr), os.Getenv("DB_AUTO_MIGRATE") == "true"))
+	}
+	return shards
+}
+
+func (p *DB) sharded() bool {
+	return len(p.shards) > 1 || p.shards[0] != p
+}
+
+// ShardDBs 返回主库之外的分片，用于迁移等命令
+func (p *DB) ShardDBs() []*DB {
+	if !p.sharded() {
+		return nil
+	}
+	return p.shards
+}
+
+func (p *DB) shardOf(key uint64) *DB {
+	return p.shards[key%uint64(len(p.shards))]
+}
+
+func (p *DB) userShard(uid uint64) *DB {
+	return p.shardOf(uid)
+}
+
+func (p *DB) groupShard(groupId uint64) *DB {
+	return p.shardOf(groupId)
+}
+
+// chatShard uids 为排好序的一对用户
+func (p *DB) chatShard(uids [2]uint64) *DB {
+	return p.shardOf(uids[0])
+}
+
+// shardUids 同一分片上的用户
+type shardUids struct {
+	shard *DB
+	uids  []uint64
+}
+
+// groupByShard 按分片分组，分组顺序与分片顺序一致
+func (p *DB) groupByShard(uids []uint64) []shardUids {
+	byShard := make(map[*DB][]uint64)
+	for _, uid := range uids {
+		s := p.userShard(uid)
+		byShard[s] = append(byShard[s], uid)
+	}
+	groups := make([]shardUids, 0, len(byShard))
+	for _, s := range p.shards {
+		if uids, ok := byShard[s]; ok {
+			groups = append(groups, shardUids{shard: s, uids: uids})
+		}
+	}
+	return groups
+}
+
+// execAllShards 在每个分片上执行，用于不带 user_id 的更新
+func (p *DB) execAllShards(ctx context.Context, query string, args ...interface{}) error {
+	for _, s := range p.shards {
+		_, err := s.sqlExec(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("sqlExec: %w", err)
+		}
+	}
+	return nil
+}
+
+// fanOutCrossShard 经 outbox 把消息写入多个分片的收件箱。outbox 写入成功后即返回成功，
+// 写入失败的分片由后台任务重放
+func (p *DB) fanOutCrossShard(ctx context.Context, groups []shardUids, convMsg types.ChatMsgOfConv) error {
+	var receiverId, groupId interface{}
+	if convMsg.ReceiverId.PeerIdType == types.EmPeerIdType_Uid {
+		receiverId = convMsg.ReceiverId.Uid
+	} else {
+		groupId = convMsg.ReceiverId.GroupId
+	}
+	var targets []string
+	for _, g := range groups {
+		for _, uid := range g.uids {
+			targets = append(targets, strconv.FormatUint(uid, 10))
+		}
+	}
+	res, err := p.sqlExec(ctx, `
+		INSERT INTO tb_fanout_outbox (sender_id, receiver_id, group_id, conv_msg_id, rand_msg_id, message_type, content, read_msg_id, target_uids)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		convMsg.Msg.SenderUid, receiverId, groupId, convMsg.ConvMsgId, convMsg.RandMsgId, convMsg.Msg.MsgType,
+		convMsg.Msg.MsgContent, convMsg.Msg.ReadMsgId, strings.Join(targets, ","))
+	if err != nil {
+		return fmt.Errorf("outbox sqlExec: %w", err)
+	}
+	outboxId, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("LastInsertId: %w", err)
+	}
+
+	// outbox 写入后扇出不再随请求取消，避免无谓的重放
+	err = p.applyOutbox(context.Background(), uint64(outboxId), groups, convMsg)
+	if err != nil {
+		Log.Warn("fan-out %d is incomplete and will be redriven: %v", outboxId, err)
+	}
+	return nil
+}
+
+// applyOutbox 把 outbox 中的消息写入各分片，全部成功后删除 outbox 行
+func (p *DB) applyOutbox(ctx context.Context, outboxId uint64, groups []shardUids, convMsg types.ChatMsgOfConv) error {
+	var firstErr error
+	for _, g := range groups {
+		err := p.fanOutToShard(ctx, g.shard, g.uids, convMsg, outboxId)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		keys := make([]readKey, len(g.uids))
+		for i, uid := range g.uids {
+			keys[i] = userKey(uid)
+		}
+		p.markWritten(keys...)
+	}
+	if firstErr != nil {
+		return fmt.Errorf("fanOutToShard: %w", firstErr)
+	}
+
+	_, err := p.sqlExec(ctx, "DELETE FROM tb_fanout_outbox WHERE id = ?", outboxId)
+	if err != nil {
+		return fmt.Errorf("delete sqlExec: %w", err)
+	}
+	return nil
+}
+
+func (p *DB) startFanOutRedriver() {
+	if !p.sharded() {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(fanOutRedriveInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			err := p.redriveFanOuts(context.Background())
+			if err != nil {
+				Log.Error("redriveFanOuts: %v", err)
+			}
+		}
+	}()
+}
+
+// redriveFanOuts 重放未完成的跨分片扇出，并清理不再需要的写入标记
+func (p *DB) redriveFanOuts(ctx context.Context) error {
+	before := time.Now().UTC().Add(-fanOutRedriveDelay).Format("2006-01-02 15:04:05")
+	rows, err := p.queryRows(ctx, `
+		SELECT id, sender_id, receiver_id, group_id, conv_msg_id, rand_msg_id, message_type, content, read_msg_id, target_uids
+		FROM tb_fanout_outbox WHERE created_at < ? ORDER BY id LIMIT ?`, before, fanOutRedriveBatch)
+	if err != nil {
+		return fmt.Errorf("queryRows: %w", err)
+	}
+
+	type pendingFanOut struct {
+		id      uint64
+		convMsg types.ChatMsgOfConv
+		uids    []uint64
+	}
+	var pending []pendingFanOut
+	for rows.Next() {
+		var f pendingFanOut
+		var receiverId, groupId sql.NullInt64
+		var targets string
+		err = rows.Scan(&f.id, &f.convMsg.Msg.SenderUid, &receiverId, &groupId, &f.convMsg.ConvMsgId, &f.convMsg.RandMsgId,
+			&f.convMsg.Msg.MsgType, &f.convMsg.Msg.MsgContent, &f.convMsg.Msg.ReadMsgId, &targets)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("Scan: %w", err)
+		}
+		if groupId.Valid {
+			f.convMsg.ReceiverId = types.PeerId{PeerIdType: types.EmPeerIdType_GroupId, GroupId: uint64(groupId.Int64)}
+		} else {
+			f.convMsg.ReceiverId = types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: uint64(receiverId.Int64)}
+		}
+		for _, s := range strings.Split(targets, ",") {
+			uid, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("ParseUint: %w", err)
+			}
+			f.uids = append(f.uids, uid)
+		}
+		pending = append(pending, f)
+	}
+	rows.Close()
+
+	for _, f := range pending {
+		err = p.applyOutbox(ctx, f.id, p.groupByShard(f.uids), f.convMsg)
+		if err != nil {
+			Log.Warn("redrive fan-out %d: %v", f.id, err)
+		}
+	}
+
+	// outbox 中最小的 id 之前的扇出都已完成，其写入标记可以删除
+	row, err := p.queryRow(ctx, "SELECT COALESCE(MIN(id), 0) FROM tb_fanout_outbox")
+	if err != nil {
+		return fmt.Errorf("queryRow: %w", err)
+	}
+	var minPending uint64
+	err = row.Scan(&minPending)
+	if err != nil {
+		return fmt.Errorf("Scan: %w", err)
+	}
+	appliedBefore := time.Now().UTC().Add(-fanOutAppliedRetention).Format("2006-01-02 15:04:05")
+	for _, s := range p.shards {
+		query := "DELETE FROM tb_fanout_applied WHERE applied_at < ?"
+		args := []interface{}{appliedBefore}
+		if minPending != 0 {
+			query += " AND outbox_id < ?"
+			args = append(args, minPending)
+		}
+		_, err = s.sqlExec(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("applied sqlExec: %w", err)
+		}
+	}
+	return nil
+}
+
+// fanOutToShard 在分片 s 的一个事务中把消息写入 uids 的收件箱：删除被取代的已读消息、分配 seqId、批量插入。
+// outboxId 非 0 时同一事务中记录写入标记，已写入过则跳过
+func (p *DB) fanOutToShard(ctx context.Context, s *DB, uids []uint64, convMsg types.ChatMsgOfConv, outboxId uint64) (err error) {
+	var receiverId interface{}
+	var groupId interface{}
+	var peerCond string
+	if convMsg.ReceiverId.PeerIdType == types.EmPeerIdType_Uid {
+		receiverId = convMsg.ReceiverId.Uid
+		peerCond = "receiver_id = ?"
+	} else {
+		groupId = convMsg.ReceiverId.GroupId
+		peerCond = "group_id = ?"
+	}
+
+	// 提交后把新消息追加到收件箱尾部缓存；已读消息会删除旧的已读消息，改为清除缓存
+	tail := p.beginInboxAppend(ctx, uids)
+	var tailMsgs map[uint64][]types.ChatMsgOfConv
+	defer func() {
+		if tailMsgs != nil && convMsg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
+			tail.endClear()
+		} else {
+			tail.end(tailMsgs)
+		}
+	}()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("beginTx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if outboxId != 0 {
+		var res sql.Result
+		res, err = s.sqlTxExec(ctx, tx, s.insertIgnoreSql()+" INTO tb_fanout_applied (outbox_id) VALUES (?)", outboxId)
+		if err != nil {
+			return fmt.Errorf("applied sqlTxExec: %w", err)
+		}
+		var n int64
+		n, err = res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("RowsAffected: %w", err)
+		}
+		if n == 0 {
+			// 已写入过
+			tx.Rollback()
+			return nil
+		}
+	}
+
+	sentAt := time.Now().UTC().Truncate(time.Second)
+	written := make(map[uint64]uint64, len(uids))
+	for start := 0; start < len(uids); start += sqlBatchSize {
+		end := start + sqlBatchSize
+		if end > len(uids) {
+			end = len(uids)
+		}
+		batch := uids[start:end]
+
+		if convMsg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
+			// 删除 sender 发向 receiver 或 group 的旧已读消息
+			args := make([]interface{}, 0, len(batch)+4)
+			for _, uid := range batch {
+				args = append(args, uid)
+			}
+			if receiverId != nil {
+				args = append(args, convMsg.Msg.SenderUid, receiverId)
+			} else {
+				args = append(args, convMsg.Msg.SenderUid, groupId)
+			}
+			args = append(args, gen_grpc.ChatMsgType_emChatMsgType_MarkRead, convMsg.Msg.ReadMsgId)
+			_, err = s.sqlTxExec(ctx, tx, fmt.Sprintf("DELETE FROM tb_user_inbox WHERE user_id IN (%s) AND sender_id = ? AND %s AND message_type = ? AND read_msg_id <= ?",
+				sqlPlaceholders(len(batch)), peerCond), args...)
+			if err != nil {
+				return fmt.Errorf("delete sqlTxExec: %w", err)
+			}
+		}
+
+		// 在扇出事务中分配 seqId，不使用号段：计数器行锁持有到提交，客户端按 seqId 游标拉取时不会漏掉后提交的消息
+		var seqIds map[uint64]uint64
+		seqIds, err = s.allocateUserSeqIdsTx(ctx, tx, batch)
+		if err != nil {
+			return fmt.Errorf("allocateUserSeqIdsTx: %w", err)
+		}
+
+		// 添加消息。发送时间由这里写入，缓存中的消息与数据库一致
+		args := make([]interface{}, 0, 11*len(batch))
+		for _, uid := range batch {
+			args = append(args, uid, seqIds[uid], convMsg.ConvMsgId, convMsg.RandMsgId, convMsg.Msg.SenderUid, receiverId, groupId,
+				convMsg.Msg.MsgContent, convMsg.Msg.MsgType, convMsg.Msg.ReadMsgId, sentAt.Format("2006-01-02 15:04:05"))
+			written[uid] = seqIds[uid]
+		}
+		values := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?), ", len(batch)), ", ")
+		_, err = s.sqlTxExec(ctx, tx, "INSERT INTO tb_user_inbox (user_id, seq_id, conv_msg_id, rand_msg_id, sender_id, receiver_id, group_id, content, message_type, read_msg_id, sent_at) VALUES "+values,
+			args...)
+		if err != nil {
+			return fmt.Errorf("insert sqlTxExec: %w", err)
+		}
+	}
+
+	err = s.sqlTxCommit(tx)
+	if err != nil {
+		return fmt.Errorf("sqlTxCommit: %w", err)
+	}
+
+	tailMsgs = make(map[uint64][]types.ChatMsgOfConv, len(written))
+	for uid, seqId := range written {
+		tailMsgs[uid] = []types.ChatMsgOfConv{inboxTailMsg(convMsg, seqId, sentAt)}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"social_server/src/app/common/types"
+	. "social_server/src/utils/log"
+	"testing"
+)
+
+// TestFanOutRedriveExactlyOnce 跨分片扇出中途一个分片失败，重放后每个接收者恰好收到一条消息
+func TestFanOutRedriveExactlyOnce(t *testing.T) {
+	setupLoggerOnce.Do(SetupLogger)
+	dir := t.TempDir()
+	p := newTestSqliteDB(t, filepath.Join(dir, "main.db"), "1")
+	shardA := newTestSqliteDB(t, filepath.Join(dir, "shard_a.db"), "1")
+	shardB := newTestSqliteDB(t, filepath.Join(dir, "shard_b.db"), "1")
+	// 偶数 uid 在 shardA，奇数 uid 在 shardB，先写 shardA
+	p.shards = []*DB{shardA, shardB}
+	ctx := context.Background()
+
+	// shardB 写收件箱失败
+	_, err := shardB.sqlExec(ctx, "ALTER TABLE tb_user_inbox RENAME TO tb_user_inbox_off")
+	if err != nil {
+		t.Fatalf("rename sqlExec: %v", err)
+	}
+
+	uids := []uint64{2, 3, 4, 5}
+	convMsg := types.ChatMsgOfConv{
+		ConvMsgId:  7,
+		RandMsgId:  7,
+		ReceiverId: types.PeerId{PeerIdType: types.EmPeerIdType_GroupId, GroupId: 9},
+		Msg:        types.ChatMsg{SenderUid: 1, MsgContent: "hello"},
+	}
+	err = p.fanOutMsg(ctx, uids, convMsg)
+	if err != nil {
+		t.Fatalf("fanOutMsg: %v", err)
+	}
+
+	countMsgs := func(uid uint64) int {
+		t.Helper()
+		row, err := p.userShard(uid).queryRow(ctx, "SELECT COUNT(*) FROM tb_user_inbox WHERE user_id = ? AND conv_msg_id = ?", uid, convMsg.ConvMsgId)
+		if err != nil {
+			t.Fatalf("queryRow: %v", err)
+		}
+		var n int
+		err = row.Scan(&n)
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		return n
+	}
+	countOutbox := func() int {
+		t.Helper()
+		row, err := p.queryRow(ctx, "SELECT COUNT(*) FROM tb_fanout_outbox")
+		if err != nil {
+			t.Fatalf("queryRow: %v", err)
+		}
+		var n int
+		err = row.Scan(&n)
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		return n
+	}
+	if n := countOutbox(); n != 1 {
+		t.Fatalf("%d outbox rows after partial fan-out, want 1", n)
+	}
+
+	// shardB 恢复，outbox 行到达重放延迟后重放，shardA 已写入的不再重复写入
+	_, err = shardB.sqlExec(ctx, "ALTER TABLE tb_user_inbox_off RENAME TO tb_user_inbox")
+	if err != nil {
+		t.Fatalf("rename sqlExec: %v", err)
+	}
+	_, err = p.sqlExec(ctx, "UPDATE tb_fanout_outbox SET created_at = '2000-01-01 00:00:00'")
+	if err != nil {
+		t.Fatalf("outbox sqlExec: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		err = p.redriveFanOuts(ctx)
+		if err != nil {
+			t.Fatalf("redriveFanOuts: %v", err)
+		}
+	}
+
+	for _, uid := range uids {
+		if n := countMsgs(uid); n != 1 {
+			t.Fatalf("uid %d has %d copies of the message, want 1", uid, n)
+		}
+	}
+	if n := countOutbox(); n != 0 {
+		t.Fatalf("%d outbox rows after redrive, want 0", n)
+	}
+}
@@ -0,0 +1,289 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"social_server/src/app/common/types"
+	"strconv"
+)
+
+// SeqStorage 序列号分配
+type SeqStorage interface {
+	AllocateSeqId(ctx context.Context, uid uint64) (seqId uint64, err error)
+	AllocateChatSeqId(ctx context.Context, uid1 uint64, uid2 uint64) (seqId uint64, err error)
+	AllocateGroupSeqId(ctx context.Context, groupId uint64) (seqId uint64, err error)
+}
+
+// UserStorage 用户
+type UserStorage interface {
+	UserIsUsernameExisted(ctx context.Context, username string) (bool, error)
+	UserRegister(ctx context.Context, param *types.UmRegisterParam) (uint64, error)
+	UserUnregister(ctx context.Context, param *types.UmUnregisterParam) error
+	UserGetInfo(ctx context.Context, uid uint64) (user *types.UmUserInfo, err error)
+	UserGetInfoByUsername(ctx context.Context, username string) (user *types.UmUserInfo, err error)
+	UserUpdateInfo(ctx context.Context, uid uint64, nickname string, email string, avatar string, password string) (err error)
+	// UserUpdatePassHash 密码哈希仍为 oldPassHash 时改为 newPassHash，密码已被修改时返回 false
+	UserUpdatePassHash(ctx context.Context, uid uint64, oldPassHash string, newPassHash string) (updated bool, err error)
+}
+
+// ContactStorage 联系人
+type ContactStorage interface {
+	ContactGetList(ctx context.Context, uid uint64) (contactUidList []uint64, err error)
+	ContactGetRelation(ctx context.Context, uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, err error)
+	ContactAdd(ctx context.Context, uid uint64, contactUid uint64) error
+	ContactAccept(ctx context.Context, uid uint64, contactUid uint64) error
+	ContactDel(ctx context.Context, uid uint64, contactUid uint64) error
+	ContactReject(ctx context.Context, uid uint64, contactUid uint64) error
+}
+
+// GroupStorage 群组
+type GroupStorage interface {
+	GroupGetList(ctx context.Context, uid uint64) (ConvIdList []uint64, err error)
+	GroupGetInfo(ctx context.Context, groupId uint64) (groupInfo *types.UmGroupInfo, err error)
+	GroupUpdateInfo(ctx context.Context, groupId uint64, groupName string, avatar string) (err error)
+	GroupCreate(ctx context.Context, uid uint64, groupName string) (ConvId uint64, err error)
+	GroupDelete(ctx context.Context, uid uint64, groupId uint64) (err error)
+	GroupGetMemList(ctx context.Context, groupId uint64) (memUidList []uint64, err error)
+	GroupGetAdminList(ctx context.Context, groupId uint64) (adminUid []uint64, err error)
+	GroupIsOwner(ctx context.Context, groupId uint64, uid uint64) (isOwner bool, err error)
+	GroupIsAdmin(ctx context.Context, groupId uint64, uid uint64) (isAdmin bool, err error)
+	GroupIsMem(ctx context.Context, groupId uint64, uid uint64) (inGroup bool, err error)
+	GroupClearMsg(ctx context.Context, groupId uint64, uid uint64) (err error)
+	GroupLeave(ctx context.Context, groupId uint64, uid uint64) (err error)
+	GroupAddMem(ctx context.Context, groupId uint64, uid uint64, role uint) (err error)
+	GroupDelMem(ctx context.Context, groupId uint64, uid uint64) (err error)
+	GroupAccept(ctx context.Context, groupId uint64, uid uint64) (err error)
+	GroupReject(ctx context.Context, groupId uint64, uid uint64) (err error)
+	GroupIgnore(ctx context.Context, groupId uint64, uid uint64) (err error)
+	GroupUpdateMem(ctx context.Context, groupId uint64, uid uint64, role uint) (err error)
+}
+
+// InboxStorage 用户收件箱
+type InboxStorage interface {
+	ChatSendMsgToUser(ctx context.Context, uid uint64, convMsg types.ChatMsgOfConv) (err error)
+	ChatSendMsg(ctx context.Context, convMsg types.ChatMsgOfConv) (err error)
+	ChatSendMsgToAdmins(ctx context.Context, convMsg types.ChatMsgOfConv) (err error)
+	ChatMarkRead(ctx context.Context, uid uint64, contactId uint64, readMsgId uint64) (err error)
+	ChatReadGroupMsg(ctx context.Context, uid uint64, groupId uint64, readMsgId uint64) (err error)
+	// ChatSyncInbox 把读扩散群的新消息同步到收件箱，ChatGetMsgList 之前调用
+	ChatSyncInbox(ctx context.Context, uid uint64) (err error)
+	// ChatGetMsgList 返回 seqId 之后的最多 limit 条消息，按 seqId 升序
+	ChatGetMsgList(ctx context.Context, uid uint64, seqId uint64, limit int) (msgs []types.ChatMsgOfConv, err error)
+	// ChatGetHistory 返回会话中 seqId 小于 beforeSeqId 的最近 limit 条消息，按 seqId 升序。
+	// beforeSeqId 为 0 时不限制
+	ChatGetHistory(ctx context.Context, uid uint64, peerId types.PeerId, beforeSeqId uint64, limit int) (msgs []types.ChatMsgOfConv, err error)
+	// ArchiveInbox 把超出保留策略的收件箱消息移到归档，返回归档的条数
+	ArchiveInbox(ctx context.Context, retention InboxRetention) (archived int64, err error)
+}
+
+// ReadRouting 读写分离
+type ReadRouting interface {
+	// MarkUserWritten 标记用户刚有写入，如收到其他节点发来的新消息通知。之后一段时间内该用户的读取走主库
+	MarkUserWritten(uid uint64)
+}
+
+// Storage 持久化存储，业务层只依赖此接口
+type Storage interface {
+	SeqStorage
+	UserStorage
+	ContactStorage
+	GroupStorage
+	InboxStorage
+	ReadRouting
+}
+
+// SessionStorage 会话存储
+type SessionStorage interface {
+	CreateSess(ctx context.Context, username string, uid uint64, device types.SessDevice, expireAfterSecs uint64) (sessId types.SessId, err error)
+	GetSessCtx(ctx context.Context, sessId types.SessId) (sessCtx *types.SessCtx, err error)
+	GetSessCtxByUid(ctx context.Context, uid uint64) (sessCtx *types.SessCtx, err error)
+	// GetUserSessList 返回用户所有未过期的会话，顺序不定
+	GetUserSessList(ctx context.Context, uid uint64) (sessList []*types.SessCtx, err error)
+	RenewSessCtx(ctx context.Context, sessCtx *types.SessCtx, expireAfterSecs uint64) (err error)
+	DeleteSess(ctx context.Context, sessId types.SessId) (err error)
+	DeleteUserSess(ctx context.Context, uid uint64) error
+}
+
+// UserAuthCache 用户认证缓存，保存 uid、用户名和密码哈希。未命中时返回 *CacheNotFoundError
+type UserAuthCache interface {
+	GetUserAuth(ctx context.Context, username string) (user *types.UmUserInfo, err error)
+	CacheUserAuthenticate(ctx context.Context, user *types.UmUserInfo) (err error)
+	ClearCacheUserAuthenticate(ctx context.Context, username string) (err error)
+}
+
+// ContactGroupCache 联系人和群成员关系缓存。未命中时返回 *CacheNotFoundError
+//
+// 与收件箱尾部缓存相同，读取未命中时返回 fillVersion，回源后用它写回；
+// 期间缓存被清除时放弃写回，回源读到的旧数据不会留在缓存中。fillVersion 为 0 时不写回
+type ContactGroupCache interface {
+	GetContactList(ctx context.Context, uid uint64) (contactsUid []uint64, fillVersion uint64, err error)
+	CacheContactList(ctx context.Context, uid uint64, fillVersion uint64, contactsUid []uint64) (err error)
+	GetContactRelation(ctx context.Context, uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, fillVersion uint64, err error)
+	CacheContactRelation(ctx context.Context, uid uint64, contactUid uint64, fillVersion uint64, isMutualContact bool, remarkName string) (err error)
+	// ClearCacheContacts 清除两个用户之间双向的联系人缓存
+	ClearCacheContacts(ctx context.Context, uid uint64, contactUid uint64) (err error)
+	GroupGetList(ctx context.Context, uid uint64) (ConvIdList []uint64, fillVersion uint64, err error)
+	CacheGroupConvList(ctx context.Context, uid uint64, fillVersion uint64, ConvIdList []uint64) (err error)
+	IsUserInConv(ctx context.Context, convId uint64, uid uint64) (inConv bool, fillVersion uint64, err error)
+	CacheIsUserInConv(ctx context.Context, convId uint64, uid uint64, fillVersion uint64, inConv bool) (err error)
+	// ClearCacheGroupMembers 清除 uids 的群列表缓存和在群中的成员关系缓存
+	ClearCacheGroupMembers(ctx context.Context, groupId uint64, uids []uint64) (err error)
+}
+
+// InboxTailCache 收件箱尾部缓存，保存每个用户最近的若干条消息。
+// 缓存完整覆盖 seqId 之后的范围时才命中，否则返回 *CacheNotFoundError
+type InboxTailCache interface {
+	// GetChatMsgList 返回 seqId 之后的最多 limit 条消息，按 seqId 升序。
+	// 未命中时 fillVersion 非 0 表示可以用回源结果写回
+	GetChatMsgList(ctx context.Context, uid uint64, seqId uint64, limit int) (msgs []types.ChatMsgOfConv, fillVersion uint64, err error)
+	// CacheChatMsgList 写回 seqId 之后的全部消息。缓存在此期间被清除或有追加未能写入时放弃
+	CacheChatMsgList(ctx context.Context, uid uint64, seqId uint64, fillVersion uint64, msgs []types.ChatMsgOfConv) (err error)
+	// BeginAppendChatMsg 写入收件箱前调用，EndAppendChatMsg 之前缓存不命中
+	BeginAppendChatMsg(ctx context.Context, uids []uint64) (err error)
+	// EndAppendChatMsg 写入结束，把各用户新写入的消息按 seqId 升序追加到缓存。msgs 为 nil 时只结束写入
+	EndAppendChatMsg(ctx context.Context, uids []uint64, msgs map[uint64][]types.ChatMsgOfConv) (err error)
+	// ClearCacheChatMsgList 收件箱消息被修改或删除后清除缓存
+	ClearCacheChatMsgList(ctx context.Context, uids []uint64) (err error)
+}
+
+// TokenRevocationStorage 访问令牌吊销列表，记录已下线会话的 sessKey，到 until（Unix 秒）后自动移除
+type TokenRevocationStorage interface {
+	RevokeTokenSess(ctx context.Context, sessKeys []string, until uint64) (err error)
+	// GetRevokedTokenSess 返回未到期的吊销记录，sessKey 对应 until
+	GetRevokedTokenSess(ctx context.Context) (revoked map[string]uint64, err error)
+}
+
+// LoginThrottleStorage 登录失败计数和锁定，key 为用户名或 IP，失败次数按滑动窗口统计
+type LoginThrottleStorage interface {
+	// AddLoginFailure 记录一次登录失败，返回最近 windowS 秒内的失败次数
+	AddLoginFailure(ctx context.Context, key string, windowS uint64) (count uint64, err error)
+	// GetLoginFailureCount 返回最近 windowS 秒内的失败次数
+	GetLoginFailureCount(ctx context.Context, key string, windowS uint64) (count uint64, err error)
+	// LockLogin 锁定 lockS 秒
+	LockLogin(ctx context.Context, key string, lockS uint64) (err error)
+	// GetLoginLockTtl 返回剩余的锁定秒数，未锁定时为 0
+	GetLoginLockTtl(ctx context.Context, key string) (ttlS uint64, err error)
+	// ClearLoginFailures 清除失败记录并解除锁定，返回清除前是否处于锁定
+	ClearLoginFailures(ctx context.Context, key string) (wasLocked bool, err error)
+}
+
+// CacheStorage 缓存层，包括会话
+type CacheStorage interface {
+	SessionStorage
+	TokenRevocationStorage
+	LoginThrottleStorage
+	UserAuthCache
+	ContactGroupCache
+	InboxTailCache
+}
+
+var (
+	_ Storage      = (*DB)(nil)
+	_ Storage      = (*MemStorage)(nil)
+	_ CacheStorage = (*Cache)(nil)
+	_ CacheStorage = (*MemCache)(nil)
+)
+
+const (
+	StorageBackendMysql  = "mysql"
+	StorageBackendSqlite = "sqlite"
+	StorageBackendMemory = "memory"
+)
+
+func storageBackend() string {
+	backend := os.Getenv("STORAGE_BACKEND")
+	if backend == "" {
+		backend = StorageBackendMysql
+	}
+	return backend
+}
+
+const (
+	CacheBackendRedis  = "redis"
+	CacheBackendMemory = "memory"
+)
+
+// CacheBackend 读取 CACHE_BACKEND，未设置时内存存储使用 memory，其余使用 redis
+func CacheBackend() string {
+	backend := os.Getenv("CACHE_BACKEND")
+	if backend == "" {
+		if storageBackend() == StorageBackendMemory {
+			return CacheBackendMemory
+		}
+		backend = CacheBackendRedis
+	}
+	return backend
+}
+
+// newCacheBackend 根据 CACHE_BACKEND 环境变量创建缓存。memory 时会话保存在进程内，不需要 Redis
+func newCacheBackend() (CacheStorage, error) {
+	switch backend := CacheBackend(); backend {
+	case CacheBackendRedis:
+		return NewCache(), nil
+	case CacheBackendMemory:
+		return NewMemCache(), nil
+	default:
+		return nil, fmt.Errorf("unknown CACHE_BACKEND: %s", backend)
+	}
+}
+
+// envUint 读取非负整数环境变量，未设置时返回 def
+func envUint(name string, def uint64) uint64 {
+	s := os.Getenv(name)
+	if s == "" {
+		return def
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", name, s)
+	}
+	return v
+}
+
+// groupReadDiffusionThreshold 成员数超过此值的群改为读扩散，为 0 时不启用
+func groupReadDiffusionThreshold() uint64 {
+	return envUint("GROUP_READ_DIFFUSION_THRESHOLD", 0)
+}
+
+// NewSqlStorage 根据 STORAGE_BACKEND 环境变量创建 SQL 存储，不建立连接
+func NewSqlStorage() (*DB, error) {
+	switch backend := storageBackend(); backend {
+	case StorageBackendMysql:
+		return NewStorage(), nil
+	case StorageBackendSqlite:
+		return NewSqliteStorage(), nil
+	default:
+		return nil, fmt.Errorf("STORAGE_BACKEND %s is not a SQL backend", backend)
+	}
+}
+
+// NewStorageBackend 根据 STORAGE_BACKEND 环境变量创建存储和缓存
+func NewStorageBackend() (Storage, CacheStorage, error) {
+	switch backend := storageBackend(); backend {
+	case StorageBackendMysql, StorageBackendSqlite:
+		storage, err := NewSqlStorage()
+		if err != nil {
+			return nil, nil, err
+		}
+		storage.Init()
+		startInboxArchiver(storage)
+		cache, err := newCacheBackend()
+		if err != nil {
+			return nil, nil, err
+		}
+		storage.SetInboxTailCache(cache)
+		return storage, cache, nil
+	case StorageBackendMemory:
+		// 数据随进程消失，Redis 中的缓存会在重启后失效
+		if CacheBackend() != CacheBackendMemory {
+			return nil, nil, fmt.Errorf("STORAGE_BACKEND memory requires CACHE_BACKEND memory")
+		}
+		storage := NewMemStorage()
+		startInboxArchiver(storage)
+		return storage, NewMemCache(), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown STORAGE_BACKEND: %s", backend)
+	}
+}
@@ -0,0 +1,395 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"social_server/src/app/common/types"
+	"social_server/src/gen/grpc"
+	"strings"
+)
+
+// 大群读扩散：
+// 群消息只写一份到 tb_group_timeline，按时间线 seqId 排序。
+// 每个成员在 tb_group_cursor 中记录已同步到的时间线 seqId，
+// 拉取消息时先把游标之后的时间线消息批量复制到自己的收件箱，再按收件箱 seqId 返回。
+// 不拉取消息的成员不产生任何写入
+
+// isReadDiffusionGroup 群消息是否写入时间线。
+// 成员数超过阈值的群在下一条消息时切换为读扩散，切换后不再切回，保证游标之后的消息都在时间线中
+func (p *DB) isReadDiffusionGroup(ctx context.Context, groupId uint64) (bool, error) {
+	row, err := p.groupShard(groupId).queryRow(ctx, "SELECT COUNT(*) FROM tb_seq_id_group_timeline WHERE group_id = ?", groupId)
+	if err != nil {
+		return false, fmt.Errorf("queryRow: %w", err)
+	}
+	var count int
+	err = row.Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("Scan: %w", err)
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if p.readDiffusionThreshold == 0 {
+		return false, nil
+	}
+
+	row, err = p.queryRow(ctx, "SELECT mem_count FROM tb_groups WHERE group_id = ?", groupId)
+	if err != nil {
+		return false, fmt.Errorf("queryRow: %w", err)
+	}
+	var memCount uint64
+	err = row.Scan(&memCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("Scan: %w", err)
+	}
+	return memCount > p.readDiffusionThreshold, nil
+}
+
+// appendGroupTimeline 把群消息写入时间线。
+// 计数器行锁持有到事务提交，时间线按 seqId 顺序可见，成员游标不会越过未提交的消息
+func (p *DB) appendGroupTimeline(ctx context.Context, convMsg types.ChatMsgOfConv) (err error) {
+	groupId := convMsg.ReceiverId.GroupId
+	s := p.groupShard(groupId)
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("beginTx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if convMsg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
+		// 删除 sender 发向 group 的旧已读消息
+		_, err = s.sqlTxExec(ctx, tx, "DELETE FROM tb_group_timeline WHERE group_id = ? AND sender_id = ? AND message_type = ? AND read_msg_id <= ?",
+			groupId, convMsg.Msg.SenderUid, gen_grpc.ChatMsgType_emChatMsgType_MarkRead, convMsg.Msg.ReadMsgId)
+		if err != nil {
+			return fmt.Errorf("delete sqlTxExec: %w", err)
+		}
+	}
+
+	seqId, err := s.allocateSeqIdsTx(ctx, tx, "tb_seq_id_group_timeline", []string{"group_id"}, []interface{}{groupId}, 1)
+	if err != nil {
+		return fmt.Errorf("allocateSeqIdsTx: %w", err)
+	}
+
+	_, err = s.sqlTxExec(ctx, tx, "INSERT INTO tb_group_timeline (group_id, seq_id, conv_msg_id, rand_msg_id, sender_id, content, message_type, read_msg_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		groupId, seqId, convMsg.ConvMsgId, convMsg.RandMsgId, convMsg.Msg.SenderUid, convMsg.Msg.MsgContent, convMsg.Msg.MsgType, convMsg.Msg.ReadMsgId)
+	if err != nil {
+		return fmt.Errorf("insert sqlTxExec: %w", err)
+	}
+
+	err = s.sqlTxCommit(tx)
+	if err != nil {
+		return fmt.Errorf("sqlTxCommit: %w", err)
+	}
+	return nil
+}
+
+// upsertGroupCursorSql 设置成员游标，不存在时插入
+func (p *DB) upsertGroupCursorSql() string {
+	if p.driver == driverSqlite {
+		return `
+		INSERT INTO tb_group_cursor (group_id, user_id, seq_id) VALUES (?, ?, ?)
+		ON CONFLICT (group_id, user_id) DO UPDATE SET seq_id = excluded.seq_id
+	`
+	}
+	return `
+		INSERT INTO tb_group_cursor (group_id, user_id, seq_id) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE seq_id = VALUES(seq_id)
+	`
+}
+
+// resetGroupCursor 把成员游标移到时间线末尾，之前的时间线消息不再同步。
+// 时间线计数器在群所在分片，游标在成员所在分片
+func (p *DB) resetGroupCursor(ctx context.Context, groupId uint64, uid uint64) error {
+	row, err := p.groupShard(groupId).queryRow(ctx, "SELECT COALESCE(MAX(seq_id), 1) - 1 FROM tb_seq_id_group_timeline WHERE group_id = ?", groupId)
+	if err != nil {
+		return fmt.Errorf("queryRow: %w", err)
+	}
+	var last uint64
+	err = row.Scan(&last)
+	if err != nil {
+		return fmt.Errorf("Scan: %w", err)
+	}
+
+	s := p.userShard(uid)
+	_, err = s.sqlExec(ctx, s.upsertGroupCursorSql(), groupId, uid, last)
+	if err != nil {
+		return fmt.Errorf("sqlExec: %w", err)
+	}
+	return nil
+}
+
+func (p *DB) deleteGroupCursor(ctx context.Context, groupId uint64, uid uint64) error {
+	_, err := p.userShard(uid).sqlExec(ctx, "DELETE FROM tb_group_cursor WHERE group_id = ? AND user_id = ?", groupId, uid)
+	if err != nil {
+		return fmt.Errorf("sqlExec: %w", err)
+	}
+	return nil
+}
+
+// syncGroupTimelines 把用户所在读扩散群的新消息同步到收件箱
+func (p *DB) syncGroupTimelines(ctx context.Context, uid uint64) (err error) {
+	// 先读完再同步，SQLite 单连接时不能在遍历结果的同时开启事务
+	pending, err := p.pendingGroupTimelines(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("pendingGroupTimelines: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// 已提交的批次追加到收件箱尾部缓存；同步了已读消息时旧的已读消息被删除，出错时不确定写入了哪些，都改为清除缓存
+	tail := p.beginInboxAppend(ctx, []uint64{uid})
+	var tailMsgs []types.ChatMsgOfConv
+	markRead := false
+	defer func() {
+		if markRead || err != nil {
+			tail.endClear()
+		} else if len(tailMsgs) > 0 {
+			tail.end(map[uint64][]types.ChatMsgOfConv{uid: tailMsgs})
+		} else {
+			tail.end(nil)
+		}
+	}()
+
+	for groupId := range pending {
+		for {
+			var synced []types.ChatMsgOfConv
+			synced, err = p.syncGroupTimeline(ctx, uid, groupId)
+			if err != nil {
+				return fmt.Errorf("syncGroupTimeline: %w", err)
+			}
+			for _, msg := range synced {
+				if msg.Msg.MsgType == gen_grpc.ChatMsgType_emChatMsgType_MarkRead {
+					markRead = true
+				}
+			}
+			tailMsgs = append(tailMsgs, synced...)
+			if len(synced) < sqlBatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// pendingGroupTimelines 返回用户所在读扩散群中预计未同步的消息条数。
+// 每次拉取都会检查，查询走副本；副本延迟时少报的消息在下次拉取时同步，
+// 收到新消息通知后该用户的读取走主库，不会因此漏掉刚通知的消息
+func (p *DB) pendingGroupTimelines(ctx context.Context, uid uint64) (pending map[uint64]uint64, err error) {
+	pending = make(map[uint64]uint64)
+	if !p.sharded() {
+		rows, err := p.readQueryRows(ctx, userKey(uid), `
+			SELECT s.group_id, s.seq_id, COALESCE(c.seq_id, 0) FROM tb_group_members m
+			JOIN tb_seq_id_group_timeline s ON s.group_id = m.group_id
+			LEFT JOIN tb_group_cursor c ON c.group_id = m.group_id AND c.user_id = m.user_id
+			WHERE m.user_id = ?`, uid)
+		if err != nil {
+			return nil, fmt.Errorf("queryRows: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var groupId, next, cursor uint64
+			err = rows.Scan(&groupId, &next, &cursor)
+			if err != nil {
+				return nil, fmt.Errorf("Scan: %w", err)
+			}
+			if next-1 > cursor {
+				pending[groupId] = next - 1 - cursor
+			}
+		}
+		return pending, rows.Err()
+	}
+
+	// 分片时群成员、时间线计数器和游标不在同一个库，分别查询
+	rows, err := p.readQueryRows(ctx, userKey(uid), "SELECT group_id FROM tb_group_members WHERE user_id = ?", uid)
+	if err != nil {
+		return nil, fmt.Errorf("members queryRows: %w", err)
+	}
+	groupIds, err := scanUids(rows)
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("members Scan: %w", err)
+	}
+	if len(groupIds) == 0 {
+		return pending, nil
+	}
+
+	nexts := make(map[uint64]uint64)
+	byShard := make(map[*DB][]interface{})
+	for _, groupId := range groupIds {
+		s := p.groupShard(groupId)
+		byShard[s] = append(byShard[s], groupId)
+	}
+	for s, args := range byShard {
+		rows, err := s.readQueryRows(ctx, userKey(uid), fmt.Sprintf("SELECT group_id, seq_id FROM tb_seq_id_group_timeline WHERE group_id IN (%s)",
+			sqlPlaceholders(len(args))), args...)
+		if err != nil {
+			return nil, fmt.Errorf("timeline queryRows: %w", err)
+		}
+		for rows.Next() {
+			var groupId, next uint64
+			err = rows.Scan(&groupId, &next)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("timeline Scan: %w", err)
+			}
+			nexts[groupId] = next
+		}
+		rows.Close()
+	}
+	if len(nexts) == 0 {
+		return pending, nil
+	}
+
+	cursors := make(map[uint64]uint64)
+	rows, err = p.userShard(uid).readQueryRows(ctx, userKey(uid), "SELECT group_id, seq_id FROM tb_group_cursor WHERE user_id = ?", uid)
+	if err != nil {
+		return nil, fmt.Errorf("cursor queryRows: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var groupId, cursor uint64
+		err = rows.Scan(&groupId, &cursor)
+		if err != nil {
+			return nil, fmt.Errorf("cursor Scan: %w", err)
+		}
+		cursors[groupId] = cursor
+	}
+	for groupId, next := range nexts {
+		if next-1 > cursors[groupId] {
+			pending[groupId] = next - 1 - cursors[groupId]
+		}
+	}
+	return pending, rows.Err()
+}
+
+// syncGroupTimeline 把游标之后的一批时间线消息复制到用户收件箱并移动游标，返回写入收件箱的消息
+func (p *DB) syncGroupTimeline(ctx context.Context, uid uint64, groupId uint64) (synced []types.ChatMsgOfConv, err error) {
+	limit := sqlBatchSize
+
+	// 保证游标行存在，供事务中加锁
+	// 游标和收件箱在用户所在分片，时间线在群所在分片
+	s, g := p.userShard(uid), p.groupShard(groupId)
+	_, err = s.sqlExec(ctx, s.insertIgnoreSql()+" INTO tb_group_cursor (group_id, user_id, seq_id) VALUES (?, ?, 0)", groupId, uid)
+	if err != nil {
+		return nil, fmt.Errorf("cursor sqlExec: %w", err)
+	}
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginTx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 锁住游标，同一用户并发拉取时串行同步
+	forUpdate := " FOR UPDATE"
+	if s.driver == driverSqlite {
+		forUpdate = ""
+	}
+	row, err := s.sqlTxQueryRow(ctx, tx, "SELECT seq_id FROM tb_group_cursor WHERE group_id = ? AND user_id = ?"+forUpdate, groupId, uid)
+	if err != nil {
+		return nil, fmt.Errorf("cursor sqlTxQueryRow: %w", err)
+	}
+	var cursor uint64
+	err = row.Scan(&cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cursor Scan: %w", err)
+	}
+
+	timelineQuery := `
+		SELECT seq_id, conv_msg_id, rand_msg_id, sender_id, content, message_type, read_msg_id, sent_at
+		FROM tb_group_timeline WHERE group_id = ? AND seq_id > ? ORDER BY seq_id ASC LIMIT ?`
+	var rows *sql.Rows
+	if g == s {
+		rows, err = s.sqlTxQueryRows(ctx, tx, timelineQuery, groupId, cursor, limit)
+	} else {
+		rows, err = g.queryRows(ctx, timelineQuery, groupId, cursor, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("timeline queryRows: %w", err)
+	}
+	var msgs, inboxMsgs []InboxMsg
+	for rows.Next() {
+		var msg InboxMsg
+		err = rows.Scan(&msg.SeqID, &msg.ConvMsgId, &msg.RandMsgId, &msg.SenderID, &msg.Content, &msg.MessageType, &msg.ReadMsgId, &msg.SentAt)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("timeline Scan: %w", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	rows.Close()
+
+	if len(msgs) > 0 {
+		// 在同步事务中分配 seqId，计数器行锁持有到提交，保证按 seqId 顺序可见
+		first, err := s.allocateSeqIdsTx(ctx, tx, "tb_seq_id_user", []string{"user_id"}, []interface{}{uid}, uint64(len(msgs)))
+		if err != nil {
+			return nil, fmt.Errorf("allocateSeqIdsTx: %w", err)
+		}
+
+		args := make([]interface{}, 0, 10*len(msgs))
+		for i, msg := range msgs {
+			if msg.MessageType == int(gen_grpc.ChatMsgType_emChatMsgType_MarkRead) {
+				// 删除 sender 发向 group 的旧已读消息
+				_, err = s.sqlTxExec(ctx, tx, "DELETE FROM tb_user_inbox WHERE user_id = ? AND sender_id = ? AND group_id = ? AND message_type = ? AND read_msg_id <= ?",
+					uid, msg.SenderID, groupId, msg.MessageType, msg.ReadMsgId)
+				if err != nil {
+					return nil, fmt.Errorf("delete sqlTxExec: %w", err)
+				}
+			}
+			args = append(args, uid, first+uint64(i), msg.ConvMsgId, msg.RandMsgId, msg.SenderID, groupId,
+				msg.Content, msg.MessageType, msg.ReadMsgId, msg.SentAt)
+
+			inboxMsg := msg
+			inboxMsg.UserID = uid
+			inboxMsg.SeqID = first + uint64(i)
+			inboxMsg.GroupID = sql.NullInt64{Int64: int64(groupId), Valid: true}
+			inboxMsgs = append(inboxMsgs, inboxMsg)
+		}
+		values := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?), ", len(msgs)), ", ")
+		_, err = s.sqlTxExec(ctx, tx, "INSERT INTO tb_user_inbox (user_id, seq_id, conv_msg_id, rand_msg_id, sender_id, group_id, content, message_type, read_msg_id, sent_at) VALUES "+values,
+			args...)
+		if err != nil {
+			return nil, fmt.Errorf("insert sqlTxExec: %w", err)
+		}
+
+		_, err = s.sqlTxExec(ctx, tx, "UPDATE tb_group_cursor SET seq_id = ? WHERE group_id = ? AND user_id = ?",
+			msgs[len(msgs)-1].SeqID, groupId, uid)
+		if err != nil {
+			return nil, fmt.Errorf("cursor sqlTxExec: %w", err)
+		}
+	}
+
+	err = s.sqlTxCommit(tx)
+	if err != nil {
+		return nil, fmt.Errorf("sqlTxCommit: %w", err)
+	}
+	if len(msgs) > 0 {
+		// 随后读取收件箱时要能读到刚同步的消息
+		p.markWritten(userKey(uid))
+	}
+
+	// 与从收件箱读出的一致，供追加到尾部缓存
+	for _, inboxMsg := range inboxMsgs {
+		msg, err := convertDbMsgToChatMsgOfConv(inboxMsg)
+		if err != nil {
+			return nil, fmt.Errorf("convertDbMsgToChatMsgOfConv: %w", err)
+		}
+		synced = append(synced, msg)
+	}
+	return synced, nil
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"social_server/src/app/common/types"
+	"social_server/src/gen/grpc"
+	"testing"
+)
+
+// TestSyncGroupTimelineAppendsTail 同步的时间线消息追加到收件箱尾部缓存，同步了已读消息时清除缓存
+func TestSyncGroupTimelineAppendsTail(t *testing.T) {
+	t.Setenv("GROUP_READ_DIFFUSION_THRESHOLD", "1")
+	cache := newTestCache(t)
+	p := newTestSqliteDB(t, filepath.Join(t.TempDir(), "timeline.db"), "1")
+	p.SetInboxTailCache(cache)
+	ctx := context.Background()
+
+	var uids []uint64
+	for _, username := range []string{"owner", "member"} {
+		uid, err := p.UserRegister(ctx, &types.UmRegisterParam{Username: username, Passwd: "x", Email: username + "@example.com"})
+		if err != nil {
+			t.Fatalf("UserRegister: %v", err)
+		}
+		uids = append(uids, uid)
+	}
+	owner, member := uids[0], uids[1]
+	groupId, err := p.GroupCreate(ctx, owner, "g")
+	if err != nil {
+		t.Fatalf("GroupCreate: %v", err)
+	}
+	err = p.GroupAddMem(ctx, groupId, member, 0)
+	if err != nil {
+		t.Fatalf("GroupAddMem: %v", err)
+	}
+
+	// 写回空的收件箱，建立缓存
+	err = p.ChatSyncInbox(ctx, member)
+	if err != nil {
+		t.Fatalf("ChatSyncInbox: %v", err)
+	}
+	_, fillVersion, err := cache.GetChatMsgList(ctx, member, 0, 10)
+	var notFound *CacheNotFoundError
+	if !errors.As(err, &notFound) || fillVersion == 0 {
+		t.Fatalf("GetChatMsgList: fillVersion %d, err %v, want miss", fillVersion, err)
+	}
+	err = cache.CacheChatMsgList(ctx, member, 0, fillVersion, nil)
+	if err != nil {
+		t.Fatalf("CacheChatMsgList: %v", err)
+	}
+
+	send := func(msgType gen_grpc.ChatMsgType, content string, readMsgId uint64) {
+		t.Helper()
+		err := p.ChatSendMsg(ctx, types.ChatMsgOfConv{
+			ReceiverId: types.PeerId{PeerIdType: types.EmPeerIdType_GroupId, GroupId: groupId},
+			Msg:        types.ChatMsg{SenderUid: owner, MsgType: msgType, MsgContent: content, ReadMsgId: readMsgId},
+		})
+		if err != nil {
+			t.Fatalf("ChatSendMsg: %v", err)
+		}
+	}
+	send(gen_grpc.ChatMsgType_emChatMsgType_Text, "m1", 0)
+	send(gen_grpc.ChatMsgType_emChatMsgType_Text, "m2", 0)
+	err = p.ChatSyncInbox(ctx, member)
+	if err != nil {
+		t.Fatalf("ChatSyncInbox: %v", err)
+	}
+
+	cached, _, err := cache.GetChatMsgList(ctx, member, 0, 10)
+	if err != nil {
+		t.Fatalf("GetChatMsgList after sync: %v, want hit", err)
+	}
+	stored, err := p.ChatGetMsgList(ctx, member, 0, 10)
+	if err != nil {
+		t.Fatalf("ChatGetMsgList: %v", err)
+	}
+	if len(stored) != 2 || !reflect.DeepEqual(cached, stored) {
+		t.Fatalf("cached %+v, want %+v", cached, stored)
+	}
+
+	// 已读消息会删除旧的已读消息，改为清除缓存
+	send(gen_grpc.ChatMsgType_emChatMsgType_MarkRead, "", stored[1].ConvMsgId)
+	err = p.ChatSyncInbox(ctx, member)
+	if err != nil {
+		t.Fatalf("ChatSyncInbox: %v", err)
+	}
+	_, _, err = cache.GetChatMsgList(ctx, member, 0, 10)
+	if !errors.As(err, &notFound) {
+		t.Fatalf("GetChatMsgList after mark read: %v, want miss", err)
+	}
+}
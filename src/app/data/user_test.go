@@ -0,0 +1,74 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"social_server/src/app/common/types"
+	"social_server/src/app/common/utils"
+	"testing"
+)
+
+// TestUserUpdatePassHashCompareAndSwap 登录时升级哈希不能覆盖期间修改过的密码
+func TestUserUpdatePassHashCompareAndSwap(t *testing.T) {
+	storages := []struct {
+		name       string
+		newStorage func(t *testing.T) Storage
+	}{
+		{"memory", func(t *testing.T) Storage {
+			return NewMemStorage()
+		}},
+		{"sqlite", func(t *testing.T) Storage {
+			return newTestSqliteDB(t, filepath.Join(t.TempDir(), "user.db"), "1")
+		}},
+	}
+	for _, tc := range storages {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := tc.newStorage(t)
+			ctx := context.Background()
+
+			legacyHash := utils.CalPassHash("old")
+			uid, err := storage.UserRegister(ctx, &types.UmRegisterParam{Username: "alice", Passwd: legacyHash, Email: "alice@example.com"})
+			if err != nil {
+				t.Fatalf("UserRegister: %v", err)
+			}
+			rehashed, err := utils.HashPassword("old")
+			if err != nil {
+				t.Fatalf("HashPassword: %v", err)
+			}
+
+			// 验证旧密码之后、升级哈希之前，密码被修改
+			changedHash, err := utils.HashPassword("new")
+			if err != nil {
+				t.Fatalf("HashPassword: %v", err)
+			}
+			err = storage.UserUpdateInfo(ctx, uid, "", "", "", changedHash)
+			if err != nil {
+				t.Fatalf("UserUpdateInfo: %v", err)
+			}
+			updated, err := storage.UserUpdatePassHash(ctx, uid, legacyHash, rehashed)
+			if err != nil || updated {
+				t.Fatalf("UserUpdatePassHash over a changed password: %v %v, want not updated", updated, err)
+			}
+			user, err := storage.UserGetInfo(ctx, uid)
+			if err != nil {
+				t.Fatalf("UserGetInfo: %v", err)
+			}
+			if user.Password != changedHash {
+				t.Fatalf("password hash is %s, want the concurrently changed %s", user.Password, changedHash)
+			}
+
+			// 哈希未变时正常升级
+			updated, err = storage.UserUpdatePassHash(ctx, uid, changedHash, rehashed)
+			if err != nil || !updated {
+				t.Fatalf("UserUpdatePassHash: %v %v, want updated", updated, err)
+			}
+			user, err = storage.UserGetInfo(ctx, uid)
+			if err != nil {
+				t.Fatalf("UserGetInfo: %v", err)
+			}
+			if user.Password != rehashed {
+				t.Fatalf("password hash is %s, want %s", user.Password, rehashed)
+			}
+		})
+	}
+}
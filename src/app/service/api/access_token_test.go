@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"social_server/src/app/service/sess_mgmt"
+	. "social_server/src/gen/grpc"
+	"testing"
+)
+
+func bearerCtx(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestAccessToken(t *testing.T) {
+	key, err := sess_mgmt.GenerateTokenKey("k1")
+	if err != nil {
+		t.Fatalf("GenerateTokenKey: %v", err)
+	}
+	t.Setenv("AUTH_TOKEN_MODE", sess_mgmt.AuthTokenModeJwt)
+	t.Setenv("JWT_KEYS", key)
+	client := newTestGrpcClient(t)
+	ctx := context.Background()
+
+	regRes, err := client.UmRegister(ctx, &UmRegisterReq{Username: "carol", Password: "password123", Email: "carol@example.com"})
+	if err != nil || regRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("UmRegister: %v %v", regRes.GetErrCode(), err)
+	}
+	loginRes, err := client.SessUserLogin(ctx, &SessUserLoginReq{Username: "carol", Password: "password123"})
+	if err != nil || loginRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessUserLogin: %v %v", loginRes.GetErrCode(), err)
+	}
+	if loginRes.GetSessId() != "" || loginRes.GetAccessToken() == "" || loginRes.GetRefreshToken() == "" ||
+		loginRes.GetAccessTokenExpiresTsMs() == 0 {
+		t.Fatalf("unexpected login response: %v", loginRes)
+	}
+	refreshToken := loginRes.GetRefreshToken()
+
+	if code := testSessErrCode(t, client, bearerCtx(loginRes.GetAccessToken())); code != ErrCode_emErrCode_Ok {
+		t.Fatalf("access token: got %v", code)
+	}
+	// 刷新令牌不能当作访问令牌使用
+	if code := testSessErrCode(t, client, bearerCtx(refreshToken)); code != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("refresh token as access token: got %v", code)
+	}
+
+	refreshRes, err := client.SessRefresh(ctx, &SessRefreshReq{RefreshToken: refreshToken})
+	if err != nil || refreshRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessRefresh: %v %v", refreshRes.GetErrCode(), err)
+	}
+	authCtx := bearerCtx(refreshRes.GetAccessToken())
+	sessList := testSessList(t, client, authCtx)
+	if len(sessList) != 1 || !sessList[0].GetIsCurrent() {
+		t.Fatalf("got %v, want the current session", sessList)
+	}
+
+	// 登出后访问令牌被吊销，刷新令牌失效
+	logoutRes, err := client.SessUserLogout(authCtx, &SessUserLogoutReq{})
+	if err != nil || logoutRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessUserLogout: %v %v", logoutRes.GetErrCode(), err)
+	}
+	if code := testSessErrCode(t, client, authCtx); code != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("access token after logout: got %v", code)
+	}
+	refreshRes, err = client.SessRefresh(ctx, &SessRefreshReq{RefreshToken: refreshToken})
+	if err != nil {
+		t.Fatalf("SessRefresh: %v", err)
+	}
+	if refreshRes.GetErrCode() != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("SessRefresh after logout: got %v", refreshRes.GetErrCode())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"log"
 	"net/http"
 	"social_server/src/app/service/core"
 	. "social_server/src/gen/grpc"
@@ -20,11 +21,22 @@ type sessCtxT struct {
 
 type ModApi struct {
 	aGrpcApiServer *grpcApiServer
+	aWsServer      *wsServer
+	aRestServer    *restServer
+	proxies        trustedProxies
 }
 
 func NewModApi() *ModApi {
+	aGrpcApiServer := NewGrpcApiServer()
+	proxies, err := loadTrustedProxies()
+	if err != nil {
+		log.Fatalf("loadTrustedProxies: %v", err)
+	}
 	return &ModApi{
-		aGrpcApiServer: NewGrpcApiServer(),
+		aGrpcApiServer: aGrpcApiServer,
+		aWsServer:      newWsServer(aGrpcApiServer.Core, proxies),
+		aRestServer:    newRestServer(aGrpcApiServer.Core, proxies),
+		proxies:        proxies,
 	}
 }
 
@@ -40,100 +52,120 @@ func NewGrpcApiServer() *grpcApiServer {
 }
 
 func (p *grpcApiServer) SessUserLogin(ctx context.Context, req *SessUserLoginReq) (*SessUserLoginRes, error) {
-	return p.Core.SessUserLogin(req)
+	return p.Core.SessUserLogin(ctx, req)
 }
 func (p *grpcApiServer) SessUserLogout(ctx context.Context, req *SessUserLogoutReq) (*SessUserLogoutRes, error) {
-	return p.Core.SessUserLogout(req)
+	return p.Core.SessUserLogout(ctx, req)
+}
+func (p *grpcApiServer) SessGetList(ctx context.Context, req *SessGetListReq) (*SessGetListRes, error) {
+	return p.Core.SessGetList(ctx, req)
+}
+func (p *grpcApiServer) SessRevoke(ctx context.Context, req *SessRevokeReq) (*SessRevokeRes, error) {
+	return p.Core.SessRevoke(ctx, req)
+}
+func (p *grpcApiServer) SessRevokeOthers(ctx context.Context, req *SessRevokeOthersReq) (*SessRevokeOthersRes, error) {
+	return p.Core.SessRevokeOthers(ctx, req)
+}
+func (p *grpcApiServer) SessRefresh(ctx context.Context, req *SessRefreshReq) (*SessRefreshRes, error) {
+	return p.Core.SessRefresh(ctx, req)
 }
 
 func (p *grpcApiServer) UmRegister(ctx context.Context, req *UmRegisterReq) (*UmRegisterRes, error) {
-	return p.Core.UmRegister(req)
+	return p.Core.UmRegister(ctx, req)
 }
 func (p *grpcApiServer) UmUnregister(ctx context.Context, req *UmUnregisterReq) (*UmUnregisterRes, error) {
-	return p.Core.UmUnregister(req)
+	return p.Core.UmUnregister(ctx, req)
 }
 func (p *grpcApiServer) UmUserUpdateInfo(ctx context.Context, req *UmUserUpdateInfoReq) (*UmUserUpdateInfoRes, error) {
-	return p.Core.UmUserUpdateInfo(req)
+	return p.Core.UmUserUpdateInfo(ctx, req)
 }
 func (p *grpcApiServer) UmContactGetList(ctx context.Context, req *UmContactGetListReq) (*UmContactGetListRes, error) {
-	return p.Core.UmContactGetList(req)
+	return p.Core.UmContactGetList(ctx, req)
 }
 func (p *grpcApiServer) UmContactGetInfo(ctx context.Context, req *UmContactGetInfoReq) (*UmContactGetInfoRes, error) {
-	return p.Core.UmContactGetInfo(req)
+	return p.Core.UmContactGetInfo(ctx, req)
 }
 func (p *grpcApiServer) UmContactFind(ctx context.Context, req *UmContactFindReq) (*UmContactFindRes, error) {
-	return p.Core.UmContactFind(req)
+	return p.Core.UmContactFind(ctx, req)
 }
 func (p *grpcApiServer) UmContactAddRequest(ctx context.Context, req *UmContactAddRequestReq) (*UmContactAddRequestRes, error) {
-	return p.Core.UmContactAddRequest(req)
+	return p.Core.UmContactAddRequest(ctx, req)
 }
 func (p *grpcApiServer) UmContactAccept(ctx context.Context, req *UmContactAcceptReq) (*UmContactAcceptRes, error) {
-	return p.Core.UmContactAccept(req)
+	return p.Core.UmContactAccept(ctx, req)
 }
 func (p *grpcApiServer) UmContactReject(ctx context.Context, req *UmContactRejectReq) (*UmContactRejectRes, error) {
-	return p.Core.UmContactReject(req)
+	return p.Core.UmContactReject(ctx, req)
 }
 func (p *grpcApiServer) UmContactDel(ctx context.Context, req *UmContactDelReq) (*UmContactDelRes, error) {
-	return p.Core.UmContactDel(req)
+	return p.Core.UmContactDel(ctx, req)
 }
 func (p *grpcApiServer) UmGroupGetList(ctx context.Context, req *UmGroupGetListReq) (*UmGroupGetListRes, error) {
-	return p.Core.UmGroupGetList(req)
+	return p.Core.UmGroupGetList(ctx, req)
 }
 func (p *grpcApiServer) UmGroupGetInfo(ctx context.Context, req *UmGroupGetInfoReq) (*UmGroupGetInfoRes, error) {
-	return p.Core.UmGroupGetInfo(req)
+	return p.Core.UmGroupGetInfo(ctx, req)
 }
 func (p *grpcApiServer) UmGroupUpdateInfo(ctx context.Context, req *UmGroupUpdateInfoReq) (*UmGroupUpdateInfoRes, error) {
-	return p.Core.UmGroupUpdateInfo(req)
+	return p.Core.UmGroupUpdateInfo(ctx, req)
 }
 func (p *grpcApiServer) UmGroupFind(ctx context.Context, req *UmGroupFindReq) (*UmGroupFindRes, error) {
-	return p.Core.UmGroupFind(req)
+	return p.Core.UmGroupFind(ctx, req)
 }
 func (p *grpcApiServer) UmGroupCreate(ctx context.Context, req *UmGroupCreateReq) (*UmGroupCreateRes, error) {
-	return p.Core.UmGroupCreate(req)
+	return p.Core.UmGroupCreate(ctx, req)
 }
 func (p *grpcApiServer) UmGroupDelete(ctx context.Context, req *UmGroupDeleteReq) (*UmGroupDeleteRes, error) {
-	return p.Core.UmGroupDelete(req)
+	return p.Core.UmGroupDelete(ctx, req)
 }
 func (p *grpcApiServer) UmGroupGetMemList(ctx context.Context, req *UmGroupGetMemListReq) (*UmGroupGetMemListRes, error) {
-	return p.Core.UmGroupGetMemList(req)
+	return p.Core.UmGroupGetMemList(ctx, req)
 }
 func (p *grpcApiServer) UmGroupJoinRequest(ctx context.Context, req *UmGroupJoinRequestReq) (*UmGroupJoinRequestRes, error) {
-	return p.Core.UmGroupJoinRequest(req)
+	return p.Core.UmGroupJoinRequest(ctx, req)
 }
 func (p *grpcApiServer) UmGroupAccept(ctx context.Context, req *UmGroupAcceptReq) (*UmGroupAcceptRes, error) {
-	return p.Core.UmGroupAccept(req)
+	return p.Core.UmGroupAccept(ctx, req)
 }
 func (p *grpcApiServer) UmGroupReject(ctx context.Context, req *UmGroupRejectReq) (*UmGroupRejectRes, error) {
-	return p.Core.UmGroupReject(req)
+	return p.Core.UmGroupReject(ctx, req)
 }
 func (p *grpcApiServer) UmGroupLeave(ctx context.Context, req *UmGroupLeaveReq) (*UmGroupLeaveRes, error) {
-	return p.Core.UmGroupLeave(req)
+	return p.Core.UmGroupLeave(ctx, req)
 }
 func (p *grpcApiServer) UmGroupAddMem(ctx context.Context, req *UmGroupAddMemReq) (*UmGroupAddMemRes, error) {
-	return p.Core.UmGroupAddMem(req)
+	return p.Core.UmGroupAddMem(ctx, req)
 }
 func (p *grpcApiServer) UmGroupDelMem(ctx context.Context, req *UmGroupDelMemReq) (*UmGroupDelMemRes, error) {
-	return p.Core.UmGroupDelMem(req)
+	return p.Core.UmGroupDelMem(ctx, req)
 }
 func (p *grpcApiServer) UmGroupUpdateMem(ctx context.Context, req *UmGroupUpdateMemReq) (*UmGroupUpdateMemRes, error) {
-	return p.Core.UmGroupUpdateMem(req)
+	return p.Core.UmGroupUpdateMem(ctx, req)
 }
 
 func (p *grpcApiServer) ChatSendMsg(ctx context.Context, req *ChatSendMsgReq) (*ChatSendMsgRes, error) {
-	return p.Core.ChatSendMsg(req)
+	return p.Core.ChatSendMsg(ctx, req)
 }
 func (p *grpcApiServer) ChatMarkRead(ctx context.Context, req *ChatMarkReadReq) (*ChatMarkReadRes, error) {
-	return p.Core.ChatMarkRead(req)
+	return p.Core.ChatMarkRead(ctx, req)
+}
+func (p *grpcApiServer) ChatGetHistory(ctx context.Context, req *ChatGetHistoryReq) (*ChatGetHistoryRes, error) {
+	return p.Core.ChatGetHistory(ctx, req)
 }
 
 func (p *grpcApiServer) GetUpdateList(ctx context.Context, req *GetUpdateListReq) (*GetUpdateListRes, error) {
-	return p.Core.GetUpdateList(req)
+	return p.Core.GetUpdateList(ctx, req)
+}
+func (p *grpcApiServer) Subscribe(req *SubscribeReq, stream GrpcApi_SubscribeServer) error {
+	return p.Core.Subscribe(stream.Context(), req, stream.Send)
 }
 
 func (p *ModApi) StartRpcServer() (error) {
 	// 准备 grpc server
-	// var opts []grpc.ServerOption
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unarySessInterceptor(p.aGrpcApiServer.Core, p.proxies)),
+		grpc.ChainStreamInterceptor(streamSessInterceptor(p.aGrpcApiServer.Core)),
+	)
 	RegisterGrpcApiServer(grpcServer, p.aGrpcApiServer)
 
 	grpcWebServer := grpcweb.WrapServer(
@@ -155,6 +187,12 @@ func (p *ModApi) StartRpcServer() (error) {
 			} else if strings.Contains(contentType, "application/grpc-web") {
 				Log.Debug("HTTP/1.1 grpc-web")
 				grpcWebServer.ServeHTTP(w, r)
+			} else if isWebSocketRequest(r) {
+				Log.Debug("WebSocket")
+				p.aWsServer.ServeHTTP(w, r)
+			} else if isRestRequest(r) {
+				Log.Debug("RESTful API")
+				p.aRestServer.ServeHTTP(w, r)
 			} else {
 				Log.Debug("Normal HTTP")
 				w.WriteHeader(http.StatusOK)
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/metadata"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// trustedProxies 可信的反向代理，只有来自这些地址的请求才读取 X-Forwarded-For / X-Real-IP。
+// 否则客户端可以伪造请求头绕过按 IP 的登录限制
+type trustedProxies []*net.IPNet
+
+// loadTrustedProxies 读取 LOGIN_TRUSTED_PROXIES，逗号分隔的 IP 或 CIDR
+func loadTrustedProxies() (trustedProxies, error) {
+	var proxies trustedProxies
+	for _, s := range strings.Split(os.Getenv("LOGIN_TRUSTED_PROXIES"), ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid LOGIN_TRUSTED_PROXIES value: %s", s)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			s = fmt.Sprintf("%s/%d", s, bits)
+		}
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LOGIN_TRUSTED_PROXIES value: %s", s)
+		}
+		proxies = append(proxies, ipNet)
+	}
+	return proxies, nil
+}
+
+func (p trustedProxies) contains(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range p {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIp 对端是可信代理时，取 X-Forwarded-For 中从右往左第一个不是可信代理的地址，
+// 没有 X-Forwarded-For 时取 X-Real-IP；否则就是对端 IP
+func (p trustedProxies) clientIp(peerIp string, forwardedFor []string, realIp string) string {
+	if !p.contains(peerIp) {
+		return peerIp
+	}
+	var hops []string
+	for _, v := range forwardedFor {
+		for _, hop := range strings.Split(v, ",") {
+			hop = strings.TrimSpace(hop)
+			if hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !p.contains(hops[i]) || i == 0 {
+			return hops[i]
+		}
+	}
+	realIp = strings.TrimSpace(realIp)
+	if realIp != "" {
+		return realIp
+	}
+	return peerIp
+}
+
+// httpClientIp HTTP 请求的来源 IP
+func (p trustedProxies) httpClientIp(r *http.Request) string {
+	return p.clientIp(remoteIp(r.RemoteAddr), r.Header.Values("X-Forwarded-For"), r.Header.Get("X-Real-IP"))
+}
+
+// grpcClientIp gRPC 请求的来源 IP。grpc-web 请求的 HTTP 头会转为元数据
+func (p trustedProxies) grpcClientIp(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var realIp string
+	if v := md.Get("x-real-ip"); len(v) > 0 {
+		realIp = v[0]
+	}
+	return p.clientIp(grpcPeerIp(ctx), md.Get("x-forwarded-for"), realIp)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestClientIp 只信任可信代理转发的请求头
+func TestClientIp(t *testing.T) {
+	t.Setenv("LOGIN_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1, ::1")
+	proxies, err := loadTrustedProxies()
+	if err != nil {
+		t.Fatalf("loadTrustedProxies: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		realIp       string
+		want         string
+	}{
+		{"direct", "203.0.113.5:1234", nil, "", "203.0.113.5"},
+		{"untrusted peer", "203.0.113.5:1234", []string{"1.2.3.4"}, "1.2.3.4", "203.0.113.5"},
+		{"trusted proxy", "10.1.2.3:1234", []string{"198.51.100.7"}, "", "198.51.100.7"},
+		{"single trusted ip", "192.168.1.1:1234", []string{"198.51.100.7"}, "", "198.51.100.7"},
+		{"ipv6 proxy", "[::1]:1234", []string{"198.51.100.7"}, "", "198.51.100.7"},
+		// 客户端自己填的地址在最左边，取最右边不是可信代理的地址
+		{"spoofed hop", "10.1.2.3:1234", []string{"1.2.3.4, 198.51.100.7, 10.9.9.9"}, "", "198.51.100.7"},
+		{"multiple headers", "10.1.2.3:1234", []string{"1.2.3.4", "198.51.100.7"}, "", "198.51.100.7"},
+		{"all trusted", "10.1.2.3:1234", []string{"10.0.0.1, 10.0.0.2"}, "", "10.0.0.1"},
+		{"real ip", "10.1.2.3:1234", nil, "198.51.100.7", "198.51.100.7"},
+		{"no headers", "10.1.2.3:1234", nil, "", "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v1/sessions", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.realIp != "" {
+				r.Header.Set("X-Real-IP", tt.realIp)
+			}
+			if got := proxies.httpClientIp(r); got != tt.want {
+				t.Errorf("httpClientIp = %s, want %s", got, tt.want)
+			}
+
+			// gRPC 及 grpc-web 从元数据读取
+			addr, err := net.ResolveTCPAddr("tcp", tt.remoteAddr)
+			if err != nil {
+				t.Fatalf("ResolveTCPAddr: %v", err)
+			}
+			md := metadata.MD{}
+			for _, v := range tt.forwardedFor {
+				md.Append("x-forwarded-for", v)
+			}
+			if tt.realIp != "" {
+				md.Set("x-real-ip", tt.realIp)
+			}
+			ctx := metadata.NewIncomingContext(peer.NewContext(context.Background(), &peer.Peer{Addr: addr}), md)
+			if got := proxies.grpcClientIp(ctx); got != tt.want {
+				t.Errorf("grpcClientIp = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// 未配置时不信任任何请求头
+	r := httptest.NewRequest("POST", "/api/v1/sessions", nil)
+	r.RemoteAddr = "10.1.2.3:1234"
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+	if got := trustedProxies(nil).httpClientIp(r); got != "10.1.2.3" {
+		t.Errorf("httpClientIp without trusted proxies = %s, want 10.1.2.3", got)
+	}
+
+	t.Setenv("LOGIN_TRUSTED_PROXIES", "10.0.0.0/8,bad")
+	if _, err := loadTrustedProxies(); err == nil {
+		t.Errorf("loadTrustedProxies accepted an invalid address")
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"google.golang.org/protobuf/proto"
+	"social_server/src/app/service/core"
+)
+
+// coreMethod 对 Core 一元方法的类型擦除包装，供 JSON 类协议（WebSocket、REST）复用
+type coreMethod struct {
+	name   string
+	newReq func() proto.Message
+	newRes func() proto.Message
+	call   func(c *core.Core, ctx context.Context, req proto.Message) (proto.Message, error)
+}
+
+func newCoreMethod[Req any, PReq interface {
+	*Req
+	proto.Message
+}, Res proto.Message](name string, fn func(*core.Core, context.Context, PReq) (Res, error)) *coreMethod {
+	return &coreMethod{
+		name:   name,
+		newReq: func() proto.Message { return PReq(new(Req)) },
+		newRes: func() proto.Message {
+			var res Res
+			return res.ProtoReflect().Type().New().Interface()
+		},
+		call: func(c *core.Core, ctx context.Context, req proto.Message) (proto.Message, error) {
+			return fn(c, ctx, req.(PReq))
+		},
+	}
+}
+
+var coreMethods = map[string]*coreMethod{}
+
+func registerCoreMethods(methods ...*coreMethod) {
+	for _, m := range methods {
+		coreMethods[m.name] = m
+	}
+}
+
+func init() {
+	registerCoreMethods(
+		newCoreMethod("SessUserLogin", (*core.Core).SessUserLogin),
+		newCoreMethod("SessUserLogout", (*core.Core).SessUserLogout),
+		newCoreMethod("SessGetList", (*core.Core).SessGetList),
+		newCoreMethod("SessRevoke", (*core.Core).SessRevoke),
+		newCoreMethod("SessRevokeOthers", (*core.Core).SessRevokeOthers),
+		newCoreMethod("SessRefresh", (*core.Core).SessRefresh),
+
+		newCoreMethod("UmRegister", (*core.Core).UmRegister),
+		newCoreMethod("UmUnregister", (*core.Core).UmUnregister),
+		newCoreMethod("UmUserUpdateInfo", (*core.Core).UmUserUpdateInfo),
+
+		newCoreMethod("UmContactGetList", (*core.Core).UmContactGetList),
+		newCoreMethod("UmContactGetInfo", (*core.Core).UmContactGetInfo),
+		newCoreMethod("UmContactFind", (*core.Core).UmContactFind),
+		newCoreMethod("UmContactAddRequest", (*core.Core).UmContactAddRequest),
+		newCoreMethod("UmContactAccept", (*core.Core).UmContactAccept),
+		newCoreMethod("UmContactReject", (*core.Core).UmContactReject),
+		newCoreMethod("UmContactDel", (*core.Core).UmContactDel),
+
+		newCoreMethod("UmGroupGetList", (*core.Core).UmGroupGetList),
+		newCoreMethod("UmGroupGetInfo", (*core.Core).UmGroupGetInfo),
+		newCoreMethod("UmGroupUpdateInfo", (*core.Core).UmGroupUpdateInfo),
+		newCoreMethod("UmGroupFind", (*core.Core).UmGroupFind),
+		newCoreMethod("UmGroupCreate", (*core.Core).UmGroupCreate),
+		newCoreMethod("UmGroupDelete", (*core.Core).UmGroupDelete),
+		newCoreMethod("UmGroupGetMemList", (*core.Core).UmGroupGetMemList),
+		newCoreMethod("UmGroupJoinRequest", (*core.Core).UmGroupJoinRequest),
+		newCoreMethod("UmGroupAccept", (*core.Core).UmGroupAccept),
+		newCoreMethod("UmGroupReject", (*core.Core).UmGroupReject),
+		newCoreMethod("UmGroupLeave", (*core.Core).UmGroupLeave),
+		newCoreMethod("UmGroupAddMem", (*core.Core).UmGroupAddMem),
+		newCoreMethod("UmGroupDelMem", (*core.Core).UmGroupDelMem),
+		newCoreMethod("UmGroupUpdateMem", (*core.Core).UmGroupUpdateMem),
+
+		newCoreMethod("ChatSendMsg", (*core.Core).ChatSendMsg),
+		newCoreMethod("ChatMarkRead", (*core.Core).ChatMarkRead),
+		newCoreMethod("ChatGetHistory", (*core.Core).ChatGetHistory),
+
+		newCoreMethod("GetUpdateList", (*core.Core).GetUpdateList),
+	)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	. "social_server/src/gen/grpc"
+	"testing"
+)
+
+func TestLoginLockout(t *testing.T) {
+	t.Setenv("LOGIN_FAIL_DELAY_AFTER", "0")
+	t.Setenv("LOGIN_USER_MAX_FAILS", "2")
+	client := newTestGrpcClient(t)
+	ctx := context.Background()
+
+	regRes, err := client.UmRegister(ctx, &UmRegisterReq{Username: "dave", Password: "password123", Email: "dave@example.com"})
+	if err != nil || regRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("UmRegister: %v %v", regRes.GetErrCode(), err)
+	}
+
+	// 用户名不区分大小写，换用不同写法也计入同一个用户
+	tests := []struct {
+		name     string
+		username string
+		password string
+		want     ErrCode
+	}{
+		{"first failure", "dave", "wrong", ErrCode_emErrCode_UserFailedToAuth},
+		{"locking failure", "DAVE", "wrong", ErrCode_emErrCode_LoginLocked},
+		{"correct password while locked", "Dave", "password123", ErrCode_emErrCode_LoginLocked},
+	}
+	for _, tt := range tests {
+		res, err := client.SessUserLogin(ctx, &SessUserLoginReq{Username: tt.username, Password: tt.password})
+		if err != nil {
+			t.Fatalf("%s: SessUserLogin: %v", tt.name, err)
+		}
+		if res.GetErrCode() != tt.want {
+			t.Fatalf("%s: got %v, want %v", tt.name, res.GetErrCode(), tt.want)
+		}
+		if tt.want == ErrCode_emErrCode_LoginLocked && res.GetRetryAfterS() == 0 {
+			t.Fatalf("%s: retryAfterS not set", tt.name)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"io"
+	"net/http"
+	"social_server/src/app/service/core"
+	. "social_server/src/gen/grpc"
+	. "social_server/src/utils/log"
+	"strconv"
+	"strings"
+)
+
+const (
+	restPathPrefix  = "/api/v1/"
+	restMaxBodySize = 1 << 20
+)
+
+// restRoute 一个 REST 路由对应一个 Core 方法。
+// 路径参数与查询参数按名字填入请求消息的同名字段，请求体按 proto JSON 格式解析，sessId 取自 Bearer 头
+type restRoute struct {
+	method        string
+	pattern       string
+	coreMethod    string
+	tag           string
+	summary       string
+	auth          bool
+	hasBody       bool
+	query         []string
+	successStatus int
+
+	segments []string
+}
+
+var restRoutes = []*restRoute{
+	// 会话
+	{method: http.MethodPost, pattern: "/api/v1/sessions", coreMethod: "SessUserLogin", tag: "sessions",
+		summary: "Log in and create a session", hasBody: true, successStatus: http.StatusCreated},
+	{method: http.MethodDelete, pattern: "/api/v1/sessions/current", coreMethod: "SessUserLogout", tag: "sessions",
+		summary: "Log out the current session", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodGet, pattern: "/api/v1/sessions", coreMethod: "SessGetList", tag: "sessions",
+		summary: "List the current user's sessions", auth: true, successStatus: http.StatusOK},
+	{method: http.MethodDelete, pattern: "/api/v1/sessions/others", coreMethod: "SessRevokeOthers", tag: "sessions",
+		summary: "Log out all other sessions", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodDelete, pattern: "/api/v1/sessions/{sessKey}", coreMethod: "SessRevoke", tag: "sessions",
+		summary: "Log out a session", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodPost, pattern: "/api/v1/sessions/refresh", coreMethod: "SessRefresh", tag: "sessions",
+		summary: "Issue a new access token with a refresh token", hasBody: true, successStatus: http.StatusOK},
+
+	// 用户
+	{method: http.MethodPost, pattern: "/api/v1/users", coreMethod: "UmRegister", tag: "users",
+		summary: "Register a user", hasBody: true, successStatus: http.StatusCreated},
+	{method: http.MethodDelete, pattern: "/api/v1/users/me", coreMethod: "UmUnregister", tag: "users",
+		summary: "Unregister the current user", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodPatch, pattern: "/api/v1/users/me", coreMethod: "UmUserUpdateInfo", tag: "users",
+		summary: "Update the current user", auth: true, hasBody: true, successStatus: http.StatusNoContent},
+
+	// 联系人
+	{method: http.MethodGet, pattern: "/api/v1/contacts", coreMethod: "UmContactGetList", tag: "contacts",
+		summary: "List contacts", auth: true, successStatus: http.StatusOK},
+	{method: http.MethodGet, pattern: "/api/v1/contacts/search", coreMethod: "UmContactFind", tag: "contacts",
+		summary: "Find a user by username", auth: true, query: []string{"username"}, successStatus: http.StatusOK},
+	{method: http.MethodPost, pattern: "/api/v1/contacts/requests", coreMethod: "UmContactAddRequest", tag: "contacts",
+		summary: "Send a contact request", auth: true, hasBody: true, successStatus: http.StatusAccepted},
+	{method: http.MethodPost, pattern: "/api/v1/contacts/requests/{contactUid}/accept", coreMethod: "UmContactAccept", tag: "contacts",
+		summary: "Accept a contact request", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodPost, pattern: "/api/v1/contacts/requests/{contactUid}/reject", coreMethod: "UmContactReject", tag: "contacts",
+		summary: "Reject a contact request", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodGet, pattern: "/api/v1/contacts/{userId}", coreMethod: "UmContactGetInfo", tag: "contacts",
+		summary: "Get a user's info", auth: true, successStatus: http.StatusOK},
+	{method: http.MethodDelete, pattern: "/api/v1/contacts/{contactUid}", coreMethod: "UmContactDel", tag: "contacts",
+		summary: "Delete a contact", auth: true, successStatus: http.StatusNoContent},
+
+	// 群组
+	{method: http.MethodGet, pattern: "/api/v1/groups", coreMethod: "UmGroupGetList", tag: "groups",
+		summary: "List joined groups", auth: true, successStatus: http.StatusOK},
+	{method: http.MethodPost, pattern: "/api/v1/groups", coreMethod: "UmGroupCreate", tag: "groups",
+		summary: "Create a group", auth: true, hasBody: true, successStatus: http.StatusCreated},
+	{method: http.MethodGet, pattern: "/api/v1/groups/search", coreMethod: "UmGroupFind", tag: "groups",
+		summary: "Find a group by id", auth: true, query: []string{"groupId"}, successStatus: http.StatusOK},
+	{method: http.MethodGet, pattern: "/api/v1/groups/{groupId}", coreMethod: "UmGroupGetInfo", tag: "groups",
+		summary: "Get group info", auth: true, successStatus: http.StatusOK},
+	{method: http.MethodPatch, pattern: "/api/v1/groups/{groupId}", coreMethod: "UmGroupUpdateInfo", tag: "groups",
+		summary: "Update group info", auth: true, hasBody: true, successStatus: http.StatusNoContent},
+	{method: http.MethodDelete, pattern: "/api/v1/groups/{groupId}", coreMethod: "UmGroupDelete", tag: "groups",
+		summary: "Delete a group", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodGet, pattern: "/api/v1/groups/{groupId}/members", coreMethod: "UmGroupGetMemList", tag: "groups",
+		summary: "List group members", auth: true, successStatus: http.StatusOK},
+	{method: http.MethodPost, pattern: "/api/v1/groups/{groupId}/members", coreMethod: "UmGroupAddMem", tag: "groups",
+		summary: "Add a group member", auth: true, hasBody: true, successStatus: http.StatusNoContent},
+	{method: http.MethodDelete, pattern: "/api/v1/groups/{groupId}/members/me", coreMethod: "UmGroupLeave", tag: "groups",
+		summary: "Leave a group", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodPatch, pattern: "/api/v1/groups/{groupId}/members/{uid}", coreMethod: "UmGroupUpdateMem", tag: "groups",
+		summary: "Update a member's role", auth: true, hasBody: true, successStatus: http.StatusNoContent},
+	{method: http.MethodDelete, pattern: "/api/v1/groups/{groupId}/members/{uid}", coreMethod: "UmGroupDelMem", tag: "groups",
+		summary: "Remove a group member", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodPost, pattern: "/api/v1/groups/{groupId}/join-requests", coreMethod: "UmGroupJoinRequest", tag: "groups",
+		summary: "Request to join a group", auth: true, successStatus: http.StatusAccepted},
+	{method: http.MethodPost, pattern: "/api/v1/groups/{groupId}/join-requests/{uid}/accept", coreMethod: "UmGroupAccept", tag: "groups",
+		summary: "Accept a join request", auth: true, successStatus: http.StatusNoContent},
+	{method: http.MethodPost, pattern: "/api/v1/groups/{groupId}/join-requests/{uid}/reject", coreMethod: "UmGroupReject", tag: "groups",
+		summary: "Reject a join request", auth: true, successStatus: http.StatusNoContent},
+
+	// 消息
+	{method: http.MethodPost, pattern: "/api/v1/messages", coreMethod: "ChatSendMsg", tag: "messages",
+		summary: "Send a message", auth: true, hasBody: true, successStatus: http.StatusCreated},
+	{method: http.MethodPost, pattern: "/api/v1/messages/read", coreMethod: "ChatMarkRead", tag: "messages",
+		summary: "Mark a conversation as read", auth: true, hasBody: true, successStatus: http.StatusNoContent},
+	{method: http.MethodGet, pattern: "/api/v1/messages/history", coreMethod: "ChatGetHistory", tag: "messages",
+		summary: "Page backwards through a conversation", auth: true,
+		query: []string{"peerId.uid", "peerId.groupId", "beforeSeqId", "limit"}, successStatus: http.StatusOK},
+
+	// 同步
+	{method: http.MethodGet, pattern: "/api/v1/sync", coreMethod: "GetUpdateList", tag: "sync",
+		summary: "Long-poll for updates after localSeqId", auth: true, query: []string{"localSeqId", "maxCount"}, successStatus: http.StatusOK},
+}
+
+// 错误码对应的 HTTP 状态码
+var restErrCodeStatus = map[ErrCode]int{
+	ErrCode_emErrCode_UnknownErr:            http.StatusInternalServerError,
+	ErrCode_emErrCode_Timeout:               http.StatusNoContent,
+	ErrCode_emErrCode_SessNotExisted:        http.StatusUnauthorized,
+	ErrCode_emErrCode_UserNotRegistered:     http.StatusNotFound,
+	ErrCode_emErrCode_UserAlreadyRegistered: http.StatusConflict,
+	ErrCode_emErrCode_UserFailedToAuth:      http.StatusUnauthorized,
+	ErrCode_emErrCode_LoginLocked:           http.StatusTooManyRequests,
+	ErrCode_emErrCode_IsContact:             http.StatusConflict,
+	ErrCode_emErrCode_IsNotContact:          http.StatusForbidden,
+	ErrCode_emErrCode_GroupNotExisted:       http.StatusNotFound,
+	ErrCode_emErrCode_UserNotInGroup:        http.StatusForbidden,
+}
+
+var restMarshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+type restServer struct {
+	core    *core.Core
+	routes  []*restRoute
+	proxies trustedProxies
+}
+
+func newRestServer(c *core.Core, proxies trustedProxies) *restServer {
+	for _, route := range restRoutes {
+		if _, ok := coreMethods[route.coreMethod]; !ok {
+			panic(fmt.Sprintf("rest route %s %s: unknown core method %s", route.method, route.pattern, route.coreMethod))
+		}
+		route.segments = strings.Split(strings.Trim(route.pattern, "/"), "/")
+	}
+	return &restServer{
+		core:    c,
+		routes:  restRoutes,
+		proxies: proxies,
+	}
+}
+
+func isRestRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, restPathPrefix)
+}
+
+// match 匹配路径，返回路径参数。路径匹配但方法不符时 route 为 nil，allowed 为该路径支持的方法
+func (p *restServer) match(method string, path string) (route *restRoute, params map[string]string, allowed []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, r := range p.routes {
+		ps, ok := r.matchPath(segments)
+		if !ok {
+			continue
+		}
+		if r.method == method {
+			return r, ps, nil
+		}
+		if !containsString(allowed, r.method) {
+			allowed = append(allowed, r.method)
+		}
+	}
+	return nil, nil, allowed
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *restRoute) matchPath(segments []string) (params map[string]string, ok bool) {
+	if len(segments) != len(p.segments) {
+		return nil, false
+	}
+	params = map[string]string{}
+	for i, seg := range p.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[seg[1:len(seg)-1]] = segments[i]
+		} else if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (p *restServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		switch r.URL.Path {
+		case restOpenApiPath:
+			p.serveOpenApi(w)
+			return
+		case restTokenJwksPath:
+			p.serveTokenJwks(w)
+			return
+		case restTokenRevokedPath:
+			p.serveTokenRevoked(w)
+			return
+		}
+	}
+
+	route, params, allowed := p.match(r.Method, r.URL.Path)
+	if route == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeRestError(w, http.StatusMethodNotAllowed, "method not allowed")
+		} else {
+			writeRestError(w, http.StatusNotFound, "not found")
+		}
+		return
+	}
+	method := coreMethods[route.coreMethod]
+	req := method.newReq()
+
+	// 请求体
+	if route.hasBody {
+		body, err := io.ReadAll(io.LimitReader(r.Body, restMaxBodySize))
+		if err != nil {
+			writeRestError(w, http.StatusBadRequest, fmt.Sprintf("read body: %v", err))
+			return
+		}
+		if len(body) > 0 {
+			err = protojson.Unmarshal(body, req)
+			if err != nil {
+				writeRestError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+				return
+			}
+		}
+	}
+
+	// 查询参数与路径参数
+	for _, name := range route.query {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		err := setProtoField(req, name, value)
+		if err != nil {
+			writeRestError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	for name, value := range params {
+		err := setProtoField(req, name, value)
+		if err != nil {
+			writeRestError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// 会话
+	if route.auth {
+		sessId := bearerToken(r)
+		if sessId == "" {
+			writeRestError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		err := setProtoField(req, "sessId", sessId)
+		if err != nil {
+			writeRestError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	res, err := method.call(p.core, core.WithClientIp(r.Context(), p.proxies.httpClientIp(r)), req)
+	if err != nil {
+		Log.Error("%s: %s", route.coreMethod, err.Error())
+		writeRestError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	status := route.successStatus
+	errCode := restResErrCode(res)
+	if errCode != ErrCode_emErrCode_Ok {
+		status = http.StatusInternalServerError
+		if s, ok := restErrCodeStatus[errCode]; ok {
+			status = s
+		}
+	}
+	writeRestMessage(w, status, res)
+}
+
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+func restResErrCode(res proto.Message) ErrCode {
+	m := res.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("errCode")
+	if fd == nil {
+		return ErrCode_emErrCode_Ok
+	}
+	return ErrCode(m.Get(fd).Enum())
+}
+
+// restFieldByPath 按以点分隔的字段路径查找字段，如 peerId.uid
+func restFieldByPath(md protoreflect.MessageDescriptor, name string) (path []protoreflect.FieldDescriptor) {
+	for _, part := range strings.Split(name, ".") {
+		if md == nil {
+			return nil
+		}
+		fd := md.Fields().ByName(protoreflect.Name(part))
+		if fd == nil || fd.IsList() || fd.IsMap() {
+			return nil
+		}
+		path = append(path, fd)
+		md = fd.Message()
+	}
+	return path
+}
+
+// setProtoField 把字符串形式的值写入消息的同名标量字段，name 可以是嵌套字段路径
+func setProtoField(msg proto.Message, name string, value string) error {
+	m := msg.ProtoReflect()
+	path := restFieldByPath(m.Descriptor(), name)
+	if path == nil {
+		return fmt.Errorf("unknown parameter %s", name)
+	}
+	for _, parent := range path[:len(path)-1] {
+		m = m.Mutable(parent).Message()
+	}
+	fd := path[len(path)-1]
+
+	var v protoreflect.Value
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		v = protoreflect.ValueOfString(value)
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid parameter %s: %v", name, err)
+		}
+		v = protoreflect.ValueOfBool(b)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid parameter %s: %v", name, err)
+		}
+		v = protoreflect.ValueOfUint64(n)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid parameter %s: %v", name, err)
+		}
+		v = protoreflect.ValueOfUint32(uint32(n))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid parameter %s: %v", name, err)
+		}
+		v = protoreflect.ValueOfInt64(n)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid parameter %s: %v", name, err)
+		}
+		v = protoreflect.ValueOfInt32(int32(n))
+	default:
+		return fmt.Errorf("unsupported parameter %s", name)
+	}
+	m.Set(fd, v)
+	return nil
+}
+
+func writeRestMessage(w http.ResponseWriter, status int, res proto.Message) {
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	data, err := restMarshalOptions.Marshal(res)
+	if err != nil {
+		Log.Error("Marshal: %v", err)
+		writeRestError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func writeRestError(w http.ResponseWriter, status int, message string) {
+	data, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
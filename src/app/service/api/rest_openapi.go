@@ -0,0 +1,221 @@
+package api
+
+import (
+	"encoding/json"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const restOpenApiPath = "/api/v1/openapi.json"
+
+type openApiSchemaBuilder struct {
+	schemas map[string]interface{}
+}
+
+func (p *openApiSchemaBuilder) ref(md protoreflect.MessageDescriptor) map[string]interface{} {
+	name := string(md.Name())
+	if _, ok := p.schemas[name]; !ok {
+		// 先占位，避免递归引用死循环
+		p.schemas[name] = nil
+		p.schemas[name] = p.messageSchema(md, nil)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// messageSchema 生成消息的 JSON schema，exclude 中的字段不输出
+func (p *openApiSchemaBuilder) messageSchema(md protoreflect.MessageDescriptor, exclude map[string]bool) map[string]interface{} {
+	properties := map[string]interface{}{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if exclude[string(fd.Name())] {
+			continue
+		}
+		schema := p.fieldSchema(fd)
+		if fd.IsList() {
+			schema = map[string]interface{}{"type": "array", "items": schema}
+		}
+		properties[fd.JSONName()] = schema
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if md.Oneofs().Len() > 0 {
+		schema["description"] = "Fields of a oneof are mutually exclusive."
+	}
+	return schema
+}
+
+func (p *openApiSchemaBuilder) fieldSchema(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// proto JSON 以字符串输出 64 位整数，输入时字符串和数字均可
+		return map[string]interface{}{"type": "string", "format": strings.ToLower(fd.Kind().String())}
+	case protoreflect.EnumKind:
+		var values []string
+		ev := fd.Enum().Values()
+		for i := 0; i < ev.Len(); i++ {
+			values = append(values, string(ev.Get(i).Name()))
+		}
+		return map[string]interface{}{"type": "string", "enum": values}
+	case protoreflect.MessageKind:
+		return p.ref(fd.Message())
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func restParamSchema(md protoreflect.MessageDescriptor, name string) map[string]interface{} {
+	path := restFieldByPath(md, name)
+	fd := path[len(path)-1]
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	default:
+		return map[string]interface{}{"type": "integer", "format": strings.ToLower(fd.Kind().String())}
+	}
+}
+
+// BuildOpenApiDoc 根据 REST 路由表和 proto 描述生成 OpenAPI 3 文档
+func BuildOpenApiDoc() map[string]interface{} {
+	builder := &openApiSchemaBuilder{schemas: map[string]interface{}{}}
+	paths := map[string]interface{}{}
+
+	for _, route := range restRoutes {
+		method := coreMethods[route.coreMethod]
+		reqDesc := method.newReq().ProtoReflect().Descriptor()
+		resDesc := method.newRes().ProtoReflect().Descriptor()
+
+		op := map[string]interface{}{
+			"operationId": route.coreMethod,
+			"summary":     route.summary,
+			"tags":        []string{route.tag},
+		}
+
+		// 参数
+		exclude := map[string]bool{"sessId": true}
+		var parameters []interface{}
+		for _, seg := range strings.Split(strings.Trim(route.pattern, "/"), "/") {
+			if !strings.HasPrefix(seg, "{") {
+				continue
+			}
+			name := seg[1 : len(seg)-1]
+			exclude[name] = true
+			parameters = append(parameters, map[string]interface{}{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   restParamSchema(reqDesc, name),
+			})
+		}
+		for _, name := range route.query {
+			exclude[name] = true
+			parameters = append(parameters, map[string]interface{}{
+				"name":   name,
+				"in":     "query",
+				"schema": restParamSchema(reqDesc, name),
+			})
+		}
+		if len(parameters) > 0 {
+			op["parameters"] = parameters
+		}
+
+		if route.hasBody {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": builder.messageSchema(reqDesc, exclude),
+					},
+				},
+			}
+		}
+		if route.auth {
+			op["security"] = []interface{}{map[string]interface{}{"bearerAuth": []string{}}}
+		}
+
+		resContent := map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": builder.ref(resDesc)},
+		}
+		responses := map[string]interface{}{}
+		if route.successStatus == http.StatusNoContent {
+			responses["204"] = map[string]interface{}{"description": http.StatusText(http.StatusNoContent)}
+		} else {
+			responses[strconv.Itoa(route.successStatus)] = map[string]interface{}{
+				"description": http.StatusText(route.successStatus),
+				"content":     resContent,
+			}
+		}
+		responses["default"] = map[string]interface{}{
+			"description": "Error. The body carries errCode when the request reached the service.",
+			"content":     resContent,
+		}
+		op["responses"] = responses
+
+		item, ok := paths[route.pattern].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[route.pattern] = item
+		}
+		item[strings.ToLower(route.method)] = op
+	}
+
+	var tags []interface{}
+	seen := map[string]bool{}
+	for _, route := range restRoutes {
+		if !seen[route.tag] {
+			seen[route.tag] = true
+			tags = append(tags, map[string]interface{}{"name": route.tag})
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Social Server REST API",
+			"version": "v1",
+		},
+		"tags":  tags,
+		"paths": paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "The sessId returned by POST /api/v1/sessions",
+				},
+			},
+			"schemas": builder.schemas,
+		},
+	}
+}
+
+// MarshalOpenApiDoc 输出格式化后的 OpenAPI 文档
+func MarshalOpenApiDoc() ([]byte, error) {
+	return json.MarshalIndent(BuildOpenApiDoc(), "", "  ")
+}
+
+func (p *restServer) serveOpenApi(w http.ResponseWriter) {
+	data, err := MarshalOpenApiDoc()
+	if err != nil {
+		writeRestError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
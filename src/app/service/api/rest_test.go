@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRestMethodNotAllowed 路径存在但方法不符时返回 405，Allow 头列出该路径支持的方法
+func TestRestMethodNotAllowed(t *testing.T) {
+	server := newRestServer(nil, nil)
+	tests := []struct {
+		method string
+		path   string
+		status int
+		allow  string
+	}{
+		{http.MethodPut, "/api/v1/sessions", http.StatusMethodNotAllowed, "POST, GET"},
+		{http.MethodGet, "/api/v1/users/me", http.StatusMethodNotAllowed, "DELETE, PATCH"},
+		// 同时匹配固定路径和路径参数的路由
+		{http.MethodGet, "/api/v1/sessions/refresh", http.StatusMethodNotAllowed, "DELETE, POST"},
+		{http.MethodGet, "/api/v1/nothing", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s %s: status %d, want %d", tt.method, tt.path, w.Code, tt.status)
+		}
+		if allow := w.Header().Get("Allow"); allow != tt.allow {
+			t.Errorf("%s %s: Allow %q, want %q", tt.method, tt.path, allow, tt.allow)
+		}
+	}
+}
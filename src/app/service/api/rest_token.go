@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// 启用访问令牌时，其他服务用这两个接口在本地校验访问令牌，不需要访问 Redis
+const (
+	restTokenJwksPath    = "/api/v1/auth/jwks.json"
+	restTokenRevokedPath = "/api/v1/auth/revoked"
+)
+
+// serveTokenJwks 返回校验访问令牌用的公钥，未启用访问令牌时返回 404
+func (p *restServer) serveTokenJwks(w http.ResponseWriter) {
+	data, ok, err := p.core.TokenJwks()
+	if !ok {
+		writeRestError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		writeRestError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// serveTokenRevoked 返回吊销列表，sessKey 对应记录到期的 Unix 秒。
+// 访问令牌的 sid 在列表中时视为无效
+func (p *restServer) serveTokenRevoked(w http.ResponseWriter) {
+	revoked, ok := p.core.RevokedTokenSess()
+	if !ok {
+		writeRestError(w, http.StatusNotFound, "not found")
+		return
+	}
+	data, err := json.Marshal(map[string]interface{}{"revoked": revoked})
+	if err != nil {
+		writeRestError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"net"
+	"social_server/src/app/service/core"
+	. "social_server/src/gen/grpc"
+	"strings"
+)
+
+// sessFreeMethods 不需要会话的方法
+var sessFreeMethods = map[string]bool{
+	"SessUserLogin": true,
+	"SessRefresh":   true,
+	"UmRegister":    true,
+}
+
+// errSessRejected 会话校验失败，已向流发送了带错误码的响应
+var errSessRejected = errors.New("session rejected")
+
+type sessIdGetter interface {
+	GetSessId() string
+}
+
+// grpcSessId 优先取 authorization 元数据（可带 Bearer 前缀），没有时取请求中的 sessId 字段
+func grpcSessId(ctx context.Context, req interface{}) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, auth := range md.Get("authorization") {
+		auth = strings.TrimSpace(auth)
+		if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+			auth = strings.TrimSpace(auth[7:])
+		}
+		if auth != "" {
+			return auth
+		}
+	}
+	if r, ok := req.(sessIdGetter); ok {
+		return r.GetSessId()
+	}
+	return ""
+}
+
+// remoteIp 取 host:port 中的 host
+func remoteIp(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// grpcPeerIp 取 gRPC 连接的对端 IP，grpc-web 请求为 HTTP 连接的对端
+func grpcPeerIp(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if !ok || pr.Addr == nil {
+		return ""
+	}
+	return remoteIp(pr.Addr.String())
+}
+
+// grpcMethodName 取 /pkg.Service/Method 中的方法名
+func grpcMethodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// setResErrCode 设置响应的 errCode 字段
+func setResErrCode(res proto.Message, errCode ErrCode) {
+	m := res.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("errCode")
+	if fd != nil {
+		m.Set(fd, protoreflect.ValueOfEnum(protoreflect.EnumNumber(errCode)))
+	}
+}
+
+// unarySessInterceptor 校验会话并放入 ctx，会话无效时直接返回带错误码的响应
+func unarySessInterceptor(c *core.Core, proxies trustedProxies) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = core.WithClientIp(ctx, proxies.grpcClientIp(ctx))
+		name := grpcMethodName(info.FullMethod)
+		if sessFreeMethods[name] {
+			return handler(ctx, req)
+		}
+
+		sessCtx, errCode := c.ResolveSess(ctx, grpcSessId(ctx, req))
+		if errCode != ErrCode_emErrCode_Ok {
+			method, ok := coreMethods[name]
+			if !ok {
+				return nil, status.Error(codes.Unauthenticated, errCode.String())
+			}
+			res := method.newRes()
+			setResErrCode(res, errCode)
+			return res, nil
+		}
+		return handler(core.WithSessCtx(ctx, sessCtx), req)
+	}
+}
+
+// sessServerStream 在收到第一个请求时校验会话，之后 Context 返回带会话的 ctx
+type sessServerStream struct {
+	grpc.ServerStream
+	core     *core.Core
+	ctx      context.Context
+	resolved bool
+}
+
+func (s *sessServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *sessServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err != nil || s.resolved {
+		return err
+	}
+	s.resolved = true
+
+	sessCtx, errCode := s.core.ResolveSess(s.ctx, grpcSessId(s.ctx, m))
+	if errCode != ErrCode_emErrCode_Ok {
+		// 和一元方法一样用响应中的错误码通知客户端，然后结束流
+		err = s.ServerStream.SendMsg(&SubscribeRes{ErrCode: errCode})
+		if err != nil {
+			return err
+		}
+		return errSessRejected
+	}
+	s.ctx = core.WithSessCtx(s.ctx, sessCtx)
+	return nil
+}
+
+// streamSessInterceptor 校验服务端流方法的会话，目前只有 Subscribe
+func streamSessInterceptor(c *core.Core) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if grpcMethodName(info.FullMethod) != "Subscribe" {
+			return handler(srv, ss)
+		}
+		err := handler(srv, &sessServerStream{ServerStream: ss, core: c, ctx: ss.Context()})
+		if errors.Is(err, errSessRejected) {
+			return nil
+		}
+		return err
+	}
+}
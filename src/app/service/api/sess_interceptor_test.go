@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"social_server/src/app/service/core"
+	. "social_server/src/gen/grpc"
+	. "social_server/src/utils/log"
+	"sync"
+	"testing"
+)
+
+var setupLoggerOnce sync.Once
+
+// newTestGrpcClient 启动带会话拦截器的 gRPC 服务，数据和会话保存在内存中
+func newTestGrpcClient(t *testing.T) GrpcApiClient {
+	t.Helper()
+	setupLoggerOnce.Do(SetupLogger)
+	t.Setenv("STORAGE_BACKEND", "memory")
+	t.Setenv("CACHE_BACKEND", "memory")
+	c := core.NewCore()
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unarySessInterceptor(c, nil)),
+		grpc.ChainStreamInterceptor(streamSessInterceptor(c)),
+	)
+	RegisterGrpcApiServer(grpcServer, &grpcApiServer{Core: c})
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("DialContext: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewGrpcApiClient(conn)
+}
+
+func TestSessInterceptor(t *testing.T) {
+	client := newTestGrpcClient(t)
+	ctx := context.Background()
+
+	regRes, err := client.UmRegister(ctx, &UmRegisterReq{Username: "alice", Password: "password123", Email: "alice@example.com"})
+	if err != nil || regRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("UmRegister: %v %v", regRes.GetErrCode(), err)
+	}
+	loginRes, err := client.SessUserLogin(ctx, &SessUserLoginReq{Username: "alice", Password: "password123"})
+	if err != nil || loginRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessUserLogin: %v %v", loginRes.GetErrCode(), err)
+	}
+	sessId := loginRes.GetSessId()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *UmContactGetListReq
+		want ErrCode
+	}{
+		{"metadata", metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+sessId), &UmContactGetListReq{}, ErrCode_emErrCode_Ok},
+		{"metadata without prefix", metadata.AppendToOutgoingContext(ctx, "authorization", sessId), &UmContactGetListReq{}, ErrCode_emErrCode_Ok},
+		{"legacy field", ctx, &UmContactGetListReq{SessId: sessId}, ErrCode_emErrCode_Ok},
+		{"metadata takes precedence", metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer bad"), &UmContactGetListReq{SessId: sessId}, ErrCode_emErrCode_SessNotExisted},
+		{"unknown session", ctx, &UmContactGetListReq{SessId: "bad"}, ErrCode_emErrCode_SessNotExisted},
+		{"missing session", ctx, &UmContactGetListReq{}, ErrCode_emErrCode_SessNotExisted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.UmContactGetList(tt.ctx, tt.req)
+			if err != nil {
+				t.Fatalf("UmContactGetList: %v", err)
+			}
+			if res.GetErrCode() != tt.want {
+				t.Fatalf("got %v, want %v", res.GetErrCode(), tt.want)
+			}
+		})
+	}
+
+	// 流方法同样在响应中返回错误码后结束
+	stream, err := client.Subscribe(ctx, &SubscribeReq{SessId: "bad"})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	subRes, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if subRes.GetErrCode() != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("got %v, want %v", subRes.GetErrCode(), ErrCode_emErrCode_SessNotExisted)
+	}
+
+	// 登出后再使用该会话
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+sessId)
+	logoutRes, err := client.SessUserLogout(authCtx, &SessUserLogoutReq{})
+	if err != nil || logoutRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessUserLogout: %v %v", logoutRes.GetErrCode(), err)
+	}
+	res, err := client.UmContactGetList(authCtx, &UmContactGetListReq{})
+	if err != nil {
+		t.Fatalf("UmContactGetList: %v", err)
+	}
+	if res.GetErrCode() != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("got %v after logout, want %v", res.GetErrCode(), ErrCode_emErrCode_SessNotExisted)
+	}
+}
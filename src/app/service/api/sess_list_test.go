@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	. "social_server/src/gen/grpc"
+	"testing"
+)
+
+// testLogin 登录并返回带会话的 ctx
+func testLogin(t *testing.T, client GrpcApiClient, password string, deviceName string) context.Context {
+	t.Helper()
+	res, err := client.SessUserLogin(context.Background(), &SessUserLoginReq{
+		Username: "bob", Password: password, DeviceName: deviceName, Platform: "test"})
+	if err != nil || res.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessUserLogin: %v %v", res.GetErrCode(), err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+res.GetSessId())
+}
+
+func testSessList(t *testing.T, client GrpcApiClient, ctx context.Context) []*SessInfo {
+	t.Helper()
+	res, err := client.SessGetList(ctx, &SessGetListReq{})
+	if err != nil || res.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessGetList: %v %v", res.GetErrCode(), err)
+	}
+	return res.GetSessList()
+}
+
+func testSessErrCode(t *testing.T, client GrpcApiClient, ctx context.Context) ErrCode {
+	t.Helper()
+	res, err := client.UmContactGetList(ctx, &UmContactGetListReq{})
+	if err != nil {
+		t.Fatalf("UmContactGetList: %v", err)
+	}
+	return res.GetErrCode()
+}
+
+func TestSessManagement(t *testing.T) {
+	client := newTestGrpcClient(t)
+
+	regRes, err := client.UmRegister(context.Background(), &UmRegisterReq{Username: "bob", Password: "password123", Email: "bob@example.com"})
+	if err != nil || regRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("UmRegister: %v %v", regRes.GetErrCode(), err)
+	}
+
+	phone := testLogin(t, client, "password123", "phone")
+	laptop := testLogin(t, client, "password123", "laptop")
+	tablet := testLogin(t, client, "password123", "tablet")
+
+	// 列出会话，按登录时间排列，标出当前会话
+	sessList := testSessList(t, client, laptop)
+	if len(sessList) != 3 {
+		t.Fatalf("got %d sessions, want 3", len(sessList))
+	}
+	var phoneKey string
+	for _, sess := range sessList {
+		if sess.GetIsCurrent() != (sess.GetDeviceName() == "laptop") {
+			t.Fatalf("session %s: isCurrent = %v", sess.GetDeviceName(), sess.GetIsCurrent())
+		}
+		if sess.GetPlatform() != "test" || sess.GetCreateTsMs() == 0 || sess.GetLastSeenTsMs() == 0 {
+			t.Fatalf("session %s: incomplete info %v", sess.GetDeviceName(), sess)
+		}
+		if sess.GetDeviceName() == "phone" {
+			phoneKey = sess.GetSessKey()
+		}
+	}
+
+	// 下线一个会话，重复下线也返回成功
+	for i := 0; i < 2; i++ {
+		res, err := client.SessRevoke(laptop, &SessRevokeReq{SessKey: phoneKey})
+		if err != nil || res.GetErrCode() != ErrCode_emErrCode_Ok {
+			t.Fatalf("SessRevoke: %v %v", res.GetErrCode(), err)
+		}
+	}
+	if code := testSessErrCode(t, client, phone); code != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("revoked session: got %v", code)
+	}
+	if n := len(testSessList(t, client, laptop)); n != 2 {
+		t.Fatalf("got %d sessions after revoke, want 2", n)
+	}
+
+	// 修改密码须提供正确的旧密码，否则不修改，其他会话也不下线
+	for _, password := range []string{"", "wrong-password"} {
+		updRes, err := client.UmUserUpdateInfo(laptop, &UmUserUpdateInfoReq{Password: password, NewPassword: "password456"})
+		if err != nil || updRes.GetErrCode() != ErrCode_emErrCode_UserFailedToAuth {
+			t.Fatalf("UmUserUpdateInfo with old password %q: %v %v", password, updRes.GetErrCode(), err)
+		}
+	}
+	if code := testSessErrCode(t, client, tablet); code != ErrCode_emErrCode_Ok {
+		t.Fatalf("session after rejected password change: got %v", code)
+	}
+
+	// 修改密码后其他会话下线，当前会话保留
+	updRes, err := client.UmUserUpdateInfo(laptop, &UmUserUpdateInfoReq{Password: "password123", NewPassword: "password456"})
+	if err != nil || updRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("UmUserUpdateInfo: %v %v", updRes.GetErrCode(), err)
+	}
+	if code := testSessErrCode(t, client, tablet); code != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("session after password change: got %v", code)
+	}
+	if code := testSessErrCode(t, client, laptop); code != ErrCode_emErrCode_Ok {
+		t.Fatalf("current session after password change: got %v", code)
+	}
+
+	// 下线其他所有会话
+	phone = testLogin(t, client, "password456", "phone")
+	res, err := client.SessRevokeOthers(laptop, &SessRevokeOthersReq{})
+	if err != nil || res.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessRevokeOthers: %v %v", res.GetErrCode(), err)
+	}
+	if code := testSessErrCode(t, client, phone); code != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("session after SessRevokeOthers: got %v", code)
+	}
+	sessList = testSessList(t, client, laptop)
+	if len(sessList) != 1 || !sessList[0].GetIsCurrent() {
+		t.Fatalf("got %v after SessRevokeOthers, want only the current session", sessList)
+	}
+}
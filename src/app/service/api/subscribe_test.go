@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	. "social_server/src/gen/grpc"
+	"testing"
+	"time"
+)
+
+// TestSubscribe 从 localSeqId 之后开始推送，新消息到达时立即推送，心跳带当前 seqId，登出后结束订阅
+func TestSubscribe(t *testing.T) {
+	client := newTestGrpcClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	login := func(username string) *SessUserLoginRes {
+		t.Helper()
+		regRes, err := client.UmRegister(ctx, &UmRegisterReq{Username: username, Password: "password123", Email: username + "@example.com"})
+		if err != nil || regRes.GetErrCode() != ErrCode_emErrCode_Ok {
+			t.Fatalf("UmRegister: %v %v", regRes.GetErrCode(), err)
+		}
+		loginRes, err := client.SessUserLogin(ctx, &SessUserLoginReq{Username: username, Password: "password123"})
+		if err != nil || loginRes.GetErrCode() != ErrCode_emErrCode_Ok {
+			t.Fatalf("SessUserLogin: %v %v", loginRes.GetErrCode(), err)
+		}
+		return loginRes
+	}
+	alice, bob := login("alice"), login("bob")
+
+	addRes, err := client.UmContactAddRequest(ctx, &UmContactAddRequestReq{SessId: alice.GetSessId(), ContactUid: bob.GetUid()})
+	if err != nil || addRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("UmContactAddRequest: %v %v", addRes.GetErrCode(), err)
+	}
+	acceptRes, err := client.UmContactAccept(ctx, &UmContactAcceptReq{SessId: bob.GetSessId(), ContactUid: alice.GetUid()})
+	if err != nil || acceptRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("UmContactAccept: %v %v", acceptRes.GetErrCode(), err)
+	}
+	updateRes, err := client.GetUpdateList(ctx, &GetUpdateListReq{SessId: alice.GetSessId()})
+	if err != nil || updateRes.GetErrCode() != ErrCode_emErrCode_Ok || updateRes.GetHasMore() {
+		t.Fatalf("GetUpdateList: %v %v", updateRes.GetErrCode(), err)
+	}
+	localSeqId := updateRes.GetSeqId()
+
+	send := func(content string) {
+		t.Helper()
+		res, err := client.ChatSendMsg(ctx, &ChatSendMsgReq{SessId: bob.GetSessId(), ConvMsg: &ChatConvMsg{
+			ReceiverId: &ChatPeerId{PeerIdUnion: &ChatPeerId_Uid{Uid: alice.GetUid()}},
+			Msg:        &ChatMsg{MsgType: ChatMsgType_emChatMsgType_Text, MsgContent: content},
+		}})
+		if err != nil || res.GetErrCode() != ErrCode_emErrCode_Ok {
+			t.Fatalf("ChatSendMsg: %v %v", res.GetErrCode(), err)
+		}
+	}
+	// 订阅之前到达的消息
+	send("m1")
+
+	stream, err := client.Subscribe(ctx, &SubscribeReq{SessId: alice.GetSessId(), LocalSeqId: localSeqId, HeartbeatIntervalS: 5})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	recvMsg := func(content string) uint64 {
+		t.Helper()
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if res.GetErrCode() != ErrCode_emErrCode_Ok || res.GetIsHeartbeat() || len(res.GetMsgList()) != 1 {
+			t.Fatalf("got %v, want a batch of %s", res, content)
+		}
+		msg := res.GetMsgList()[0]
+		if msg.GetMsg().GetMsgContent() != content || msg.GetSeqId() <= localSeqId || res.GetSeqId() != msg.GetSeqId() {
+			t.Fatalf("got %v after seqId %d, want %s", res, localSeqId, content)
+		}
+		localSeqId = res.GetSeqId()
+		return localSeqId
+	}
+	recvMsg("m1")
+	// 订阅之后到达的消息
+	send("m2")
+	seqId := recvMsg("m2")
+
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if res.GetErrCode() != ErrCode_emErrCode_Ok || !res.GetIsHeartbeat() || res.GetSeqId() != seqId || len(res.GetMsgList()) != 0 {
+		t.Fatalf("got %v, want a heartbeat at seqId %d", res, seqId)
+	}
+
+	// 登出后下一次心跳结束订阅
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+alice.GetSessId())
+	logoutRes, err := client.SessUserLogout(authCtx, &SessUserLogoutReq{})
+	if err != nil || logoutRes.GetErrCode() != ErrCode_emErrCode_Ok {
+		t.Fatalf("SessUserLogout: %v %v", logoutRes.GetErrCode(), err)
+	}
+	res, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if res.GetErrCode() != ErrCode_emErrCode_SessNotExisted {
+		t.Fatalf("got %v after logout, want %v", res, ErrCode_emErrCode_SessNotExisted)
+	}
+	res, err = stream.Recv()
+	if err == nil {
+		t.Fatalf("got %v, want the stream to end", res)
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("stream did not end before the test deadline")
+	}
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"net/http"
+	"social_server/src/app/service/core"
+	. "social_server/src/gen/grpc"
+	. "social_server/src/utils/log"
+	"sync"
+	"time"
+)
+
+const (
+	wsPath         = "/ws"
+	wsWriteWait    = 10 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsPingInterval = 25 * time.Second
+	wsMaxFrameSize = 1 << 20
+	// 每个连接同时处理的请求数上限，超出的请求直接返回错误
+	wsMaxInflight = 32
+)
+
+// 客户端发来的帧
+type wsRequest struct {
+	Id     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// 服务端下发的帧。应答与推送共用此结构，推送帧的 id 为对应 Subscribe 请求的 id
+type wsResponse struct {
+	Id     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+var wsMarshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+type wsServer struct {
+	core     *core.Core
+	upgrader websocket.Upgrader
+	proxies  trustedProxies
+}
+
+func newWsServer(c *core.Core, proxies trustedProxies) *wsServer {
+	return &wsServer{
+		core:    c,
+		proxies: proxies,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			// 与 grpc-web 一致，允许跨域
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+func isWebSocketRequest(r *http.Request) bool {
+	return r.URL.Path == wsPath && websocket.IsWebSocketUpgrade(r)
+}
+
+type wsConn struct {
+	server *wsServer
+	conn   *websocket.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	writeMu sync.Mutex
+
+	// 正在处理的请求，容量为 wsMaxInflight
+	inflight chan struct{}
+
+	// 每个连接同时只保留一个订阅
+	subMu     sync.Mutex
+	subCancel context.CancelFunc
+}
+
+func (p *wsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := p.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		Log.Error("Upgrade: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(core.WithClientIp(context.Background(), p.proxies.httpClientIp(r)))
+	c := &wsConn{
+		server:   p,
+		conn:     conn,
+		ctx:      ctx,
+		cancel:   cancel,
+		inflight: make(chan struct{}, wsMaxInflight),
+	}
+	go c.pingLoop()
+	c.readLoop()
+}
+
+func (p *wsConn) close() {
+	p.cancel()
+	p.conn.Close()
+}
+
+func (p *wsConn) readLoop() {
+	defer p.close()
+
+	p.conn.SetReadLimit(wsMaxFrameSize)
+	p.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	p.conn.SetPongHandler(func(string) error {
+		return p.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := p.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				Log.Warn("ReadMessage: %v", err)
+			}
+			return
+		}
+
+		var req wsRequest
+		err = json.Unmarshal(data, &req)
+		if err != nil {
+			p.writeResponse(&wsResponse{Error: fmt.Sprintf("invalid frame: %v", err)})
+			continue
+		}
+
+		if req.Method == "Subscribe" {
+			p.subscribe(&req)
+			continue
+		}
+
+		// 每个请求单独处理，GetUpdateList 之类的长轮询不阻塞后续请求
+		select {
+		case p.inflight <- struct{}{}:
+		default:
+			p.writeResponse(&wsResponse{Id: req.Id, Method: req.Method, Error: "too many concurrent requests"})
+			continue
+		}
+		go func() {
+			defer func() { <-p.inflight }()
+			p.handleRequest(&req)
+		}()
+	}
+}
+
+func (p *wsConn) pingLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.writeMu.Lock()
+			err := p.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+			p.writeMu.Unlock()
+			if err != nil {
+				p.close()
+				return
+			}
+		}
+	}
+}
+
+func (p *wsConn) handleRequest(req *wsRequest) {
+	method, ok := coreMethods[req.Method]
+	if !ok {
+		p.writeResponse(&wsResponse{Id: req.Id, Method: req.Method, Error: "unknown method"})
+		return
+	}
+
+	methodReq := method.newReq()
+	if len(req.Params) > 0 {
+		err := protojson.Unmarshal(req.Params, methodReq)
+		if err != nil {
+			p.writeResponse(&wsResponse{Id: req.Id, Method: req.Method, Error: fmt.Sprintf("invalid params: %v", err)})
+			return
+		}
+	}
+
+	res, err := method.call(p.server.core, p.ctx, methodReq)
+	if err != nil {
+		p.writeResponse(&wsResponse{Id: req.Id, Method: req.Method, Error: err.Error()})
+		return
+	}
+	p.writeResult(req, res)
+}
+
+func (p *wsConn) subscribe(req *wsRequest) {
+	var subReq SubscribeReq
+	if len(req.Params) > 0 {
+		err := protojson.Unmarshal(req.Params, &subReq)
+		if err != nil {
+			p.writeResponse(&wsResponse{Id: req.Id, Method: req.Method, Error: fmt.Sprintf("invalid params: %v", err)})
+			return
+		}
+	}
+
+	// 替换已有订阅
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.subMu.Lock()
+	if p.subCancel != nil {
+		p.subCancel()
+	}
+	p.subCancel = cancel
+	p.subMu.Unlock()
+
+	go func() {
+		defer cancel()
+		err := p.server.core.Subscribe(ctx, &subReq, func(res *SubscribeRes) error {
+			return p.writeResult(req, res)
+		})
+		if err != nil && ctx.Err() == nil {
+			p.writeResponse(&wsResponse{Id: req.Id, Method: req.Method, Error: err.Error()})
+		}
+	}()
+}
+
+func (p *wsConn) writeResult(req *wsRequest, res proto.Message) error {
+	data, err := wsMarshalOptions.Marshal(res)
+	if err != nil {
+		Log.Error("Marshal: %v", err)
+		return p.writeResponse(&wsResponse{Id: req.Id, Method: req.Method, Error: "internal error"})
+	}
+	return p.writeResponse(&wsResponse{Id: req.Id, Method: req.Method, Result: data})
+}
+
+func (p *wsConn) writeResponse(res *wsResponse) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("Marshal: %w", err)
+	}
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	p.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	err = p.conn.WriteMessage(websocket.TextMessage, data)
+	if err != nil {
+		return fmt.Errorf("WriteMessage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"net/http/httptest"
+	"social_server/src/app/service/core"
+	. "social_server/src/utils/log"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWsInflightLimit 一个连接上同时处理的请求超过上限时，多出的请求返回错误
+func TestWsInflightLimit(t *testing.T) {
+	setupLoggerOnce.Do(SetupLogger)
+	t.Setenv("STORAGE_BACKEND", "memory")
+	t.Setenv("CACHE_BACKEND", "memory")
+	srv := httptest.NewServer(newWsServer(core.NewCore(), nil))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+wsPath, nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	call := func(id int, method string, params string) {
+		t.Helper()
+		err := conn.WriteJSON(wsRequest{Id: json.RawMessage(fmt.Sprint(id)), Method: method, Params: json.RawMessage(params)})
+		if err != nil {
+			t.Fatalf("WriteJSON: %v", err)
+		}
+	}
+	read := func() wsResponse {
+		t.Helper()
+		var res wsResponse
+		err := conn.ReadJSON(&res)
+		if err != nil {
+			t.Fatalf("ReadJSON: %v", err)
+		}
+		return res
+	}
+
+	call(1, "UmRegister", `{"username": "alice", "password": "password123", "email": "alice@example.com"}`)
+	if res := read(); res.Error != "" {
+		t.Fatalf("UmRegister: %s", res.Error)
+	}
+	call(2, "SessUserLogin", `{"username": "alice", "password": "password123"}`)
+	res := read()
+	var login struct {
+		SessId string `json:"sessId"`
+	}
+	if res.Error != "" || json.Unmarshal(res.Result, &login) != nil || login.SessId == "" {
+		t.Fatalf("SessUserLogin: %s %s", res.Error, res.Result)
+	}
+
+	// 长轮询没有新消息时一直等待，占满处理中的请求数
+	params := fmt.Sprintf(`{"sessId": %q}`, login.SessId)
+	for i := 0; i < wsMaxInflight; i++ {
+		call(100+i, "GetUpdateList", params)
+	}
+	call(1000, "GetUpdateList", params)
+	res = read()
+	if string(res.Id) != "1000" || res.Error != "too many concurrent requests" {
+		t.Fatalf("got response %s %q, want request 1000 rejected", res.Id, res.Error)
+	}
+}
@@ -3,160 +3,156 @@ package chat
 import (
 	"context"
 	"fmt"
-	"github.com/go-redis/redis/v8"
-	"log"
-	"os"
-	"social_server/src/app/common/proj_err"
 	"social_server/src/app/common/types"
 	"social_server/src/app/data"
 	gen_grpc "social_server/src/gen/grpc"
 	. "social_server/src/utils/log"
-	"strconv"
-	"sync"
 	"time"
 )
 
-type UserSync struct {
-	condCh     chan struct{}
-	channelName string
-}
-
 type Chat struct {
-	storage *data.DB
-	cache *data.Cache
-	userSyncs map[uint64]*UserSync
-	rwMu      sync.RWMutex
-	redisClient *redis.Client
+	storage data.Storage
+	cache data.CacheStorage
+	notifier Notifier
 }
 
-func NewChat(storage *data.DB, cache *data.Cache) *Chat {
-	redisHost := os.Getenv("REDIS_HOST")
-	redisPort := os.Getenv("REDIS_PORT")
-	redisPassword := os.Getenv("REDIS_PASSWORD")
-	redisDB := os.Getenv("REDIS_DB")
-
-	if redisPort == "" {
-		redisPort = "6379"
-	}
-	redisDBInt := 0
-	if redisDB != "" {
-		var err error
-		redisDBInt, err = strconv.Atoi(redisDB)
-		if err != nil {
-			log.Fatalf("Invalid REDIS_DB value: %v", err)
-		}
-	}
-
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", redisHost, redisPort),
-		Password: redisPassword,
-		DB:       redisDBInt,
-	})
-
-	// 尝试连接并发送 PING 命令
-	var ctx = context.Background()
-	err := redisClient.Ping(ctx).Err()
-	if err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
-	} else {
-		Log.Info("Connected to Redis successfully!")
-	}
-
+func NewChat(storage data.Storage, cache data.CacheStorage, notifier Notifier) *Chat {
 	return &Chat{
 		storage: storage,
 		cache: cache,
-		//userChans: make(map[uint64]chan struct{}),
-		userSyncs: make(map[uint64]*UserSync),
-		redisClient: redisClient,
+		notifier: notifier,
 	}
 }
 
-func (p *Chat) getUserSync(uid uint64) (*UserSync) {
-	p.rwMu.RLock()
-	us, exists := p.userSyncs[uid]
-	p.rwMu.RUnlock()
-	if exists {
-		return us
-	}
-
-	p.rwMu.Lock()
-	defer p.rwMu.Unlock()
-
-	// 双重检查
-	us, exists = p.userSyncs[uid]
-	if exists {
-		return us
+// getMsgPage 返回 seqId 之后的最多 maxCount 条消息。
+// 先读收件箱尾部缓存，更早的范围或未命中时回源数据库
+func (p *Chat) getMsgPage(ctx context.Context, uid uint64, seqId uint64, maxCount int) (msgList []types.ChatMsgOfConv, hasMore bool, err error) {
+	// 读扩散群的新消息不在缓存中，先同步到收件箱
+	err = p.storage.ChatSyncInbox(ctx, uid)
+	if err != nil {
+		return nil, false, fmt.Errorf("ChatSyncInbox: %w", err)
 	}
 
-	channelName := fmt.Sprintf("user_channel_%d", uid)
-	us = &UserSync{}
-	us = &UserSync{
-		condCh:     make(chan struct{}),
-		channelName: channelName,
+	// 多取一条判断是否还有更多
+	msgList, fillVersion, err := p.cache.GetChatMsgList(ctx, uid, seqId, maxCount+1)
+	if err != nil {
+		if _, ok := err.(*data.CacheNotFoundError); !ok {
+			Log.Warn("GetChatMsgList cache error: %v", err)
+		}
+		if fillVersion != 0 {
+			// 回源结果要写回缓存，从主库读取，否则从库延迟可能让缓存缺少已提交的消息
+			p.storage.MarkUserWritten(uid)
+		}
+		msgList, err = p.storage.ChatGetMsgList(ctx, uid, seqId, maxCount+1)
+		if err != nil {
+			return nil, false, err
+		}
+		if fillVersion != 0 && len(msgList) <= maxCount {
+			// 已读到末尾，seqId 之后的消息完整，写回缓存
+			err = p.cache.CacheChatMsgList(ctx, uid, seqId, fillVersion, msgList)
+			if err != nil {
+				Log.Warn("CacheChatMsgList error: %v", err)
+			}
+		}
 	}
-	p.userSyncs[uid] = us
-
-	return us
-}
-
-func (p *Chat) waitForNewMessage(uid uint64) error {
-	us := p.getUserSync(uid)
-	pubsub := p.redisClient.Subscribe(context.Background(), us.channelName)
-	defer pubsub.Close()
-
-	ch := pubsub.Channel()
-	select {
-	case <-ch:
-		// 收到新消息通知
-		return nil
-	case <-time.After(58 * time.Second):
-		// 超时退出
-		return fmt.Errorf("%w", proj_err.ErrTimeout)
+	if len(msgList) > maxCount {
+		return msgList[:maxCount], true, nil
 	}
+	return msgList, false, nil
 }
 
-func (p *Chat) GetChatMsgList(uid uint64, seqId uint64) (msgList []types.ChatMsgOfConv, err error) {
+func (p *Chat) GetChatMsgList(ctx context.Context, uid uint64, seqId uint64, maxCount int) (msgList []types.ChatMsgOfConv, hasMore bool, err error) {
+	// 先登记等待再读库，读库与等待之间到达的通知不会丢失
+	watcher := p.notifier.Watch(uid)
+	defer watcher.Close()
+	notifyCh := watcher.C()
 
-	msgList, err = p.storage.ChatGetMsgList(uid, seqId)
+	msgList, hasMore, err = p.getMsgPage(ctx, uid, seqId, maxCount)
 	if err == nil {
 		if len(msgList) > 0 {
-			return msgList, nil
+			return msgList, hasMore, nil
 		}
 	} else {
 		if err.Error() != "no new msg" {
-			return nil, fmt.Errorf("ChatGetMsgList: %w", err)
+			return nil, false, fmt.Errorf("ChatGetMsgList: %w", err)
 		}
 	}
 
-	err = p.waitForNewMessage(uid)
+	err = p.waitForNewMessage(ctx, notifyCh)
 	if err != nil {
-		return nil, fmt.Errorf("waitForNewMessage: %w", err)
+		return nil, false, fmt.Errorf("waitForNewMessage: %w", err)
 	}
+	// 新消息可能刚由其他节点写入，从主库读取
+	p.storage.MarkUserWritten(uid)
 
-	msgList, err = p.storage.ChatGetMsgList(uid, seqId)
+	msgList, hasMore, err = p.getMsgPage(ctx, uid, seqId, maxCount)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return msgList, nil
+	return msgList, hasMore, nil
 }
 
-func (p *Chat) NotifyAUserCond(uid uint64) {
-	us := p.getUserSync(uid)
-	p.redisClient.Publish(context.Background(), us.channelName, "new message")
+// SubscribeMsgList 持续推送 seqId 之后的新消息，直到 ctx 结束或回调返回错误。
+// 先登记等待再读库，保证读库与等待之间到达的通知不会丢失。每次最多推送 maxCount 条，积压的消息连续分批推送
+func (p *Chat) SubscribeMsgList(ctx context.Context, uid uint64, seqId uint64, maxCount int, heartbeatInterval time.Duration,
+	onMsgList func(msgList []types.ChatMsgOfConv, hasMore bool) error, onHeartbeat func(seqId uint64) error) error {
+	watcher := p.notifier.Watch(uid)
+	defer watcher.Close()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		notifyCh := watcher.C()
+		msgList, hasMore, err := p.getMsgPage(ctx, uid, seqId, maxCount)
+		if err != nil && err.Error() != "no new msg" {
+			return fmt.Errorf("ChatGetMsgList: %w", err)
+		}
+		if len(msgList) > 0 {
+			err = onMsgList(msgList, hasMore)
+			if err != nil {
+				return err
+			}
+			seqId = msgList[len(msgList)-1].SeqId
+			ticker.Reset(heartbeatInterval)
+		}
+		if hasMore {
+			// 继续推送积压的消息
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			// 客户端断开
+			return nil
+		case <-notifyCh:
+			// 新消息可能刚由其他节点写入，从主库读取
+			p.storage.MarkUserWritten(uid)
+		case <-ticker.C:
+			// 心跳。顺便重新读库，即使通知丢失也不会一直收不到消息
+			err = onHeartbeat(seqId)
+			if err != nil {
+				return err
+			}
+		}
+	}
 }
 
-func (p *Chat) SendMsg(convMsg types.ChatMsgOfConv) (err error) {
+func (p *Chat) SendMsg(ctx context.Context, convMsg types.ChatMsgOfConv) (err error) {
 	// 判断消息类型
 	switch convMsg.Msg.MsgType {
 	case gen_grpc.ChatMsgType_emChatMsgType_MarkRead:
 		if convMsg.ReceiverId.PeerIdType == types.EmPeerIdType_Uid {
-			err = p.storage.ChatMarkRead(convMsg.Msg.SenderUid, convMsg.ReceiverId.Uid, convMsg.Msg.ReadMsgId)
+			err = p.storage.ChatMarkRead(ctx, convMsg.Msg.SenderUid, convMsg.ReceiverId.Uid, convMsg.Msg.ReadMsgId)
 			if err != nil {
 				return fmt.Errorf("ChatMarkRead: %w", err)
 			}
 		} else {
-			err = p.storage.ChatReadGroupMsg(convMsg.Msg.SenderUid, convMsg.ReceiverId.GroupId, convMsg.Msg.ReadMsgId)
+			err = p.storage.ChatReadGroupMsg(ctx, convMsg.Msg.SenderUid, convMsg.ReceiverId.GroupId, convMsg.Msg.ReadMsgId)
 			if err != nil {
 				return fmt.Errorf("ChatReadGroupMsg: %w", err)
 			}
@@ -166,7 +162,7 @@ func (p *Chat) SendMsg(convMsg types.ChatMsgOfConv) (err error) {
 	}
 
 	// 更新数据库
-	err = p.storage.ChatSendMsg(convMsg)
+	err = p.storage.ChatSendMsg(ctx, convMsg)
 	if err != nil {
 		return fmt.Errorf("ChatSendMsg: %w", err)
 	}
@@ -177,8 +173,8 @@ func (p *Chat) SendMsg(convMsg types.ChatMsgOfConv) (err error) {
 		p.NotifyAUserCond(convMsg.Msg.SenderUid)
 
 	} else {
-		// 获取群员列表
-		memberList, err := p.storage.GroupGetMemList(convMsg.ReceiverId.GroupId)
+		// 获取群员列表。消息已写入，请求取消也要通知到群员
+		memberList, err := p.storage.GroupGetMemList(context.Background(), convMsg.ReceiverId.GroupId)
 		if err != nil {
 			return fmt.Errorf("GroupGetMemList: %w", err)
 		}
@@ -191,8 +187,8 @@ func (p *Chat) SendMsg(convMsg types.ChatMsgOfConv) (err error) {
 	return nil
 }
 
-func (p *Chat) SendMsgToUser(uid uint64, convMsg types.ChatMsgOfConv) (err error) {
-	err = p.storage.ChatSendMsgToUser(uid, convMsg)
+func (p *Chat) SendMsgToUser(ctx context.Context, uid uint64, convMsg types.ChatMsgOfConv) (err error) {
+	err = p.storage.ChatSendMsgToUser(ctx, uid, convMsg)
 	if err != nil {
 		return fmt.Errorf("ChatSendMsgToUser: %w", err)
 	}
@@ -200,13 +196,13 @@ func (p *Chat) SendMsgToUser(uid uint64, convMsg types.ChatMsgOfConv) (err error
 	return nil
 }
 
-func (p *Chat) SendMsgToAdmins(convMsg types.ChatMsgOfConv) (err error) {
-	err = p.storage.ChatSendMsgToAdmins(convMsg)
+func (p *Chat) SendMsgToAdmins(ctx context.Context, convMsg types.ChatMsgOfConv) (err error) {
+	err = p.storage.ChatSendMsgToAdmins(ctx, convMsg)
 	if err != nil {
 		return fmt.Errorf("ChatSendMsgToAdmins: %w", err)
 	}
-	// 获取管理员列表
-	admins, err := p.storage.GroupGetAdminList(convMsg.ReceiverId.GroupId)
+	// 获取管理员列表。消息已写入，请求取消也要通知到管理员
+	admins, err := p.storage.GroupGetAdminList(context.Background(), convMsg.ReceiverId.GroupId)
 	if err != nil {
 		return fmt.Errorf("GroupGetAdminList: %w", err)
 	}
@@ -216,6 +212,19 @@ func (p *Chat) SendMsgToAdmins(convMsg types.ChatMsgOfConv) (err error) {
 	return nil
 }
 
-func (p *Chat) AllocateGroupSeqId(groupId uint64) (seqId uint64, err error) {
-	return p.storage.AllocateGroupSeqId(groupId)
+// GetHistory 返回会话中 beforeSeqId 之前的最近 limit 条消息，按 seqId 升序
+func (p *Chat) GetHistory(ctx context.Context, uid uint64, peerId types.PeerId, beforeSeqId uint64, limit int) (msgList []types.ChatMsgOfConv, hasMore bool, err error) {
+	// 多取一条判断是否还有更早的消息
+	msgList, err = p.storage.ChatGetHistory(ctx, uid, peerId, beforeSeqId, limit+1)
+	if err != nil {
+		return nil, false, fmt.Errorf("ChatGetHistory: %w", err)
+	}
+	if len(msgList) > limit {
+		return msgList[1:], true, nil
+	}
+	return msgList, false, nil
+}
+
+func (p *Chat) AllocateGroupSeqId(ctx context.Context, groupId uint64) (seqId uint64, err error) {
+	return p.storage.AllocateGroupSeqId(ctx, groupId)
 }
\ No newline at end of file
@@ -0,0 +1,51 @@
+package chat
+
+import (
+	"context"
+	"social_server/src/app/common/types"
+	"social_server/src/app/data"
+	"social_server/src/gen/grpc"
+	"testing"
+)
+
+// TestGetHistoryHasMore 还有更早的消息时 hasMore 为 true，最后一页为 false
+func TestGetHistoryHasMore(t *testing.T) {
+	storage := data.NewMemStorage()
+	p := NewChat(storage, data.NewMemCache(), NewLocalNotifier())
+	ctx := context.Background()
+
+	const alice, bob, total = 1, 2, 5
+	for i := 0; i < total; i++ {
+		err := storage.ChatSendMsg(ctx, types.ChatMsgOfConv{
+			ReceiverId: types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: bob},
+			Msg:        types.ChatMsg{SenderUid: alice, MsgType: gen_grpc.ChatMsgType_emChatMsgType_Text, MsgContent: "m"},
+		})
+		if err != nil {
+			t.Fatalf("ChatSendMsg: %v", err)
+		}
+	}
+
+	peerId := types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: bob}
+	var beforeSeqId uint64
+	var got int
+	for _, want := range []struct {
+		count   int
+		hasMore bool
+	}{{2, true}, {2, true}, {1, false}} {
+		msgList, hasMore, err := p.GetHistory(ctx, alice, peerId, beforeSeqId, 2)
+		if err != nil {
+			t.Fatalf("GetHistory: %v", err)
+		}
+		if len(msgList) != want.count || hasMore != want.hasMore {
+			t.Fatalf("page after %d messages: %d messages, hasMore %v, want %d, %v", got, len(msgList), hasMore, want.count, want.hasMore)
+		}
+		got += len(msgList)
+		beforeSeqId = msgList[0].SeqId
+	}
+
+	// 条数恰好等于 limit 时没有更多
+	msgList, hasMore, err := p.GetHistory(ctx, alice, peerId, 0, total)
+	if err != nil || len(msgList) != total || hasMore {
+		t.Fatalf("GetHistory with limit %d: %d messages, hasMore %v, err %v", total, len(msgList), hasMore, err)
+	}
+}
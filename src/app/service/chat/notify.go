@@ -0,0 +1,139 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"social_server/src/app/common/proj_err"
+	"social_server/src/app/data"
+	"sync"
+	"time"
+)
+
+// Notifier 新消息通知。等待方在读库之前 Watch，消息写入后 Notify 唤醒所有等待该用户的请求
+type Notifier interface {
+	// Watch 登记 uid 的等待方，用完须调用 Close
+	Watch(uid uint64) *UserWatcher
+	// Notify 在消息写入后调用，不随请求取消
+	Notify(uid uint64)
+}
+
+// NewNotifier 根据 CACHE_BACKEND 创建通知：redis 时经 Redis 通知所有节点，memory 时只在进程内通知，用于单节点部署
+func NewNotifier() (Notifier, error) {
+	switch backend := data.CacheBackend(); backend {
+	case data.CacheBackendRedis:
+		notifier, err := NewRedisNotifier()
+		if err != nil {
+			return nil, err
+		}
+		return notifier, nil
+	case data.CacheBackendMemory:
+		return NewLocalNotifier(), nil
+	default:
+		return nil, fmt.Errorf("unknown CACHE_BACKEND: %s", backend)
+	}
+}
+
+type UserSync struct {
+	// waiters 等待中的请求数，为 0 时从 userSyncs 中删除
+	waiters int
+	// condCh 收到通知时关闭并换新，唤醒所有等待方
+	condCh chan struct{}
+}
+
+// LocalNotifier 进程内的通知广播，只唤醒本节点上的等待方
+type LocalNotifier struct {
+	userSyncs map[uint64]*UserSync
+	rwMu      sync.RWMutex
+}
+
+func NewLocalNotifier() *LocalNotifier {
+	return &LocalNotifier{
+		userSyncs: make(map[uint64]*UserSync),
+	}
+}
+
+// UserWatcher 一个 uid 的等待方
+type UserWatcher struct {
+	notifier *LocalNotifier
+	uid      uint64
+	us       *UserSync
+}
+
+func (p *LocalNotifier) Watch(uid uint64) *UserWatcher {
+	p.rwMu.Lock()
+	defer p.rwMu.Unlock()
+
+	us, exists := p.userSyncs[uid]
+	if !exists {
+		us = &UserSync{
+			condCh: make(chan struct{}),
+		}
+		p.userSyncs[uid] = us
+	}
+	us.waiters++
+	return &UserWatcher{
+		notifier: p,
+		uid:      uid,
+		us:       us,
+	}
+}
+
+// Notify 唤醒本节点上等待 uid 的请求，没有等待方时什么也不做
+func (p *LocalNotifier) Notify(uid uint64) {
+	p.rwMu.Lock()
+	defer p.rwMu.Unlock()
+
+	us, exists := p.userSyncs[uid]
+	if exists {
+		close(us.condCh)
+		us.condCh = make(chan struct{})
+	}
+}
+
+// wakeAll 唤醒本节点上所有等待的请求
+func (p *LocalNotifier) wakeAll() {
+	p.rwMu.Lock()
+	defer p.rwMu.Unlock()
+
+	for _, us := range p.userSyncs {
+		close(us.condCh)
+		us.condCh = make(chan struct{})
+	}
+}
+
+// C 返回当前的通知通道。须在读库之前取得，之后到达的通知都会关闭它
+func (w *UserWatcher) C() <-chan struct{} {
+	w.notifier.rwMu.RLock()
+	defer w.notifier.rwMu.RUnlock()
+	return w.us.condCh
+}
+
+func (w *UserWatcher) Close() {
+	p := w.notifier
+	p.rwMu.Lock()
+	defer p.rwMu.Unlock()
+
+	w.us.waiters--
+	if w.us.waiters == 0 {
+		delete(p.userSyncs, w.uid)
+	}
+}
+
+func (p *Chat) waitForNewMessage(ctx context.Context, notifyCh <-chan struct{}) error {
+	select {
+	case <-notifyCh:
+		// 收到新消息通知
+		return nil
+	case <-ctx.Done():
+		// 客户端断开或超时
+		return ctx.Err()
+	case <-time.After(58 * time.Second):
+		// 超时退出
+		return fmt.Errorf("%w", proj_err.ErrTimeout)
+	}
+}
+
+// NotifyAUserCond 通知在消息写入后发出，不随请求取消，否则已写入的消息要等到下次心跳才被拉取
+func (p *Chat) NotifyAUserCond(uid uint64) {
+	p.notifier.Notify(uid)
+}
@@ -0,0 +1,208 @@
+package chat
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"social_server/src/app/common/types"
+	"social_server/src/app/data"
+	. "social_server/src/utils/log"
+	"sync"
+	"testing"
+	"time"
+)
+
+// notifyHookStorage 在读库返回之后、进入等待之前执行 afterRead
+type notifyHookStorage struct {
+	data.Storage
+	once      sync.Once
+	afterRead func()
+}
+
+func (p *notifyHookStorage) ChatGetMsgList(ctx context.Context, uid uint64, seqId uint64, limit int) ([]types.ChatMsgOfConv, error) {
+	msgList, err := p.Storage.ChatGetMsgList(ctx, uid, seqId, limit)
+	if err == nil && len(msgList) == 0 && p.afterRead != nil {
+		p.once.Do(p.afterRead)
+	}
+	return msgList, err
+}
+
+var setupLoggerOnce sync.Once
+
+// newRedisTestChat 创建连接到 mr 的节点，同一个 mr 上的多个节点模拟集群部署
+func newRedisTestChat(t *testing.T, mr *miniredis.Miniredis, nodeId string, storage data.Storage) *Chat {
+	t.Helper()
+	setupLoggerOnce.Do(SetupLogger)
+	t.Setenv("NODE_ID", nodeId)
+	t.Setenv("REDIS_HOST", mr.Host())
+	t.Setenv("REDIS_PORT", mr.Port())
+	t.Setenv("REDIS_PASSWORD", "")
+	t.Setenv("REDIS_DB", "")
+	notifier, err := NewRedisNotifier()
+	if err != nil {
+		t.Fatalf("NewRedisNotifier: %v", err)
+	}
+	t.Cleanup(func() {
+		notifier.redisClient.Close()
+	})
+	return NewChat(storage, data.NewMemCache(), notifier)
+}
+
+// testNotifiers 分别用两种通知创建单个节点
+var testNotifiers = []struct {
+	name    string
+	newChat func(t *testing.T, storage data.Storage) *Chat
+}{
+	{"local", func(t *testing.T, storage data.Storage) *Chat {
+		return NewChat(storage, data.NewMemCache(), NewLocalNotifier())
+	}},
+	{"redis", func(t *testing.T, storage data.Storage) *Chat {
+		return newRedisTestChat(t, miniredis.RunT(t), "node-a", storage)
+	}},
+}
+
+// localNotifier 返回节点上登记等待方的进程内通知
+func localNotifier(p *Chat) *LocalNotifier {
+	switch n := p.notifier.(type) {
+	case *LocalNotifier:
+		return n
+	case *RedisNotifier:
+		return n.local
+	}
+	panic("unknown notifier")
+}
+
+func waiterCount(p *Chat) int {
+	n := localNotifier(p)
+	n.rwMu.RLock()
+	defer n.rwMu.RUnlock()
+	return len(n.userSyncs)
+}
+
+// waitForWaiters 等 uid 的 n 个请求都登记等待
+func waitForWaiters(t *testing.T, ctx context.Context, p *Chat, uid uint64, n int) {
+	t.Helper()
+	notifier := localNotifier(p)
+	for {
+		notifier.rwMu.RLock()
+		us := notifier.userSyncs[uid]
+		ready := us != nil && us.waiters == n
+		notifier.rwMu.RUnlock()
+		if ready {
+			return
+		}
+		if ctx.Err() != nil {
+			t.Fatal("waiters not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func testMsg(uid uint64) types.ChatMsgOfConv {
+	return types.ChatMsgOfConv{
+		ReceiverId: types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: uid},
+		Msg:        types.ChatMsg{SenderUid: uid},
+	}
+}
+
+// 读库发现没有消息之后、进入等待之前到达的通知不能丢失
+func TestGetChatMsgListNoLostWakeup(t *testing.T) {
+	for _, tn := range testNotifiers {
+		t.Run(tn.name, func(t *testing.T) {
+			testGetChatMsgListNoLostWakeup(t, tn.newChat)
+		})
+	}
+}
+
+func testGetChatMsgListNoLostWakeup(t *testing.T, newChat func(t *testing.T, storage data.Storage) *Chat) {
+	const uid = 1001
+	storage := &notifyHookStorage{Storage: data.NewMemStorage()}
+	p := newChat(t, storage)
+
+	storage.afterRead = func() {
+		notifier := localNotifier(p)
+		notifier.rwMu.RLock()
+		condCh := notifier.userSyncs[uid].condCh
+		notifier.rwMu.RUnlock()
+
+		err := storage.Storage.ChatSendMsgToUser(context.Background(), uid, testMsg(uid))
+		if err != nil {
+			t.Errorf("ChatSendMsgToUser: %v", err)
+		}
+		p.NotifyAUserCond(uid)
+
+		// 等通知唤醒本节点的等待方，再让请求进入等待
+		select {
+		case <-condCh:
+		case <-time.After(5 * time.Second):
+			t.Error("notification not dispatched")
+		}
+	}
+
+	// 通知丢失时要等到 58 秒超时，这里 5 秒内必须返回
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	msgList, _, err := p.GetChatMsgList(ctx, uid, 0, 10)
+	if err != nil {
+		t.Fatalf("GetChatMsgList: %v", err)
+	}
+	if len(msgList) != 1 {
+		t.Fatalf("got %d msgs, want 1", len(msgList))
+	}
+	if n := waiterCount(p); n != 0 {
+		t.Fatalf("%d user syncs left after the request returned", n)
+	}
+}
+
+// 一次通知唤醒同一用户的所有等待方，请求结束后清理等待状态
+func TestNotifyWakesAllWaiters(t *testing.T) {
+	for _, tn := range testNotifiers {
+		t.Run(tn.name, func(t *testing.T) {
+			testNotifyWakesAllWaiters(t, tn.newChat)
+		})
+	}
+}
+
+func testNotifyWakesAllWaiters(t *testing.T, newChat func(t *testing.T, storage data.Storage) *Chat) {
+	const (
+		uid     = 2002
+		waiters = 50
+	)
+	storage := data.NewMemStorage()
+	p := newChat(t, storage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msgList, _, err := p.GetChatMsgList(ctx, uid, 0, 10)
+			if err == nil && len(msgList) == 0 {
+				err = context.DeadlineExceeded
+			}
+			errCh <- err
+		}()
+	}
+
+	waitForWaiters(t, ctx, p, uid, waiters)
+
+	err := storage.ChatSendMsgToUser(ctx, uid, testMsg(uid))
+	if err != nil {
+		t.Fatalf("ChatSendMsgToUser: %v", err)
+	}
+	p.NotifyAUserCond(uid)
+
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		if err != nil {
+			t.Fatalf("GetChatMsgList: %v", err)
+		}
+	}
+	if n := waiterCount(p); n != 0 {
+		t.Fatalf("%d user syncs left after all requests returned", n)
+	}
+}
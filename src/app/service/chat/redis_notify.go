@@ -0,0 +1,332 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"os"
+	. "social_server/src/utils/log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const notifyStreamKey = "user_notify"
+
+// notifyNodesKey 所有节点 id 的集合，notifyNodeKey 为各节点的存活标记
+const notifyNodesKey = "user_notify:nodes"
+
+func notifyNodeKey(nodeId string) string {
+	return fmt.Sprintf("user_notify:node:%s", nodeId)
+}
+
+const (
+	// notifyStreamMaxLen 流的近似最大长度
+	notifyStreamMaxLen = 100000
+	// notifyReadBlock 读取流时最长阻塞时间
+	notifyReadBlock = 30 * time.Second
+	// notifyRetryInterval 写入失败的通知的重试间隔
+	notifyRetryInterval = time.Second
+	// notifyNodeTTL 节点存活标记的有效期，过期的节点的消费者组由其他节点删除
+	notifyNodeTTL = time.Minute
+	// notifyNodeRefresh 刷新存活标记、清理过期节点的间隔
+	notifyNodeRefresh = 20 * time.Second
+)
+
+// notifyNodeId 读取 NODE_ID，默认为主机名和进程号
+func notifyNodeId() string {
+	nodeId := os.Getenv("NODE_ID")
+	if nodeId != "" {
+		return nodeId
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "node"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// RedisNotifier 多节点部署的新消息通知：写入消息后向 Redis Stream user_notify 追加一条记录（uid），
+// 每个节点用自己的消费者组读取整个流，唤醒本节点上等待该用户的请求。
+// 流中的记录不随连接断开丢失，节点重连后从消费者组记录的位置继续读取；
+// 写入流失败的通知在后台重试，不会等到长轮询超时才被拉取
+type RedisNotifier struct {
+	local       *LocalNotifier
+	redisClient *redis.Client
+	// nodeId 本节点在通知流中的消费者组名
+	nodeId string
+	// notifyPending 写入通知流失败、待重试的 uid
+	notifyPending map[uint64]struct{}
+	notifyMu      sync.Mutex
+}
+
+// NewRedisNotifier 连接 Redis 并创建本节点的消费者组
+func NewRedisNotifier() (*RedisNotifier, error) {
+	redisHost := os.Getenv("REDIS_HOST")
+	redisPort := os.Getenv("REDIS_PORT")
+	redisPassword := os.Getenv("REDIS_PASSWORD")
+	redisDB := os.Getenv("REDIS_DB")
+
+	if redisPort == "" {
+		redisPort = "6379"
+	}
+	redisDBInt := 0
+	if redisDB != "" {
+		var err error
+		redisDBInt, err = strconv.Atoi(redisDB)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_DB value: %w", err)
+		}
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", redisHost, redisPort),
+		Password: redisPassword,
+		DB:       redisDBInt,
+	})
+
+	// 尝试连接并发送 PING 命令
+	ctx := context.Background()
+	err := redisClient.Ping(ctx).Err()
+	if err != nil {
+		redisClient.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	Log.Info("Connected to Redis successfully!")
+
+	p := &RedisNotifier{
+		local:         NewLocalNotifier(),
+		redisClient:   redisClient,
+		nodeId:        notifyNodeId(),
+		notifyPending: make(map[uint64]struct{}),
+	}
+	err = p.startNotifyListener(ctx)
+	if err != nil {
+		redisClient.Close()
+		return nil, fmt.Errorf("startNotifyListener: %w", err)
+	}
+	return p, nil
+}
+
+func (p *RedisNotifier) Watch(uid uint64) *UserWatcher {
+	return p.local.Watch(uid)
+}
+
+// startNotifyListener 创建本节点的消费者组，之后在后台读取通知流、重试失败的通知
+func (p *RedisNotifier) startNotifyListener(ctx context.Context) error {
+	err := p.refreshNotifyNode(ctx)
+	if err != nil {
+		return fmt.Errorf("refreshNotifyNode: %w", err)
+	}
+	err = p.createNotifyGroup(ctx)
+	if err != nil {
+		return fmt.Errorf("createNotifyGroup: %w", err)
+	}
+	go p.dispatchNotify()
+	go p.maintainNotify()
+	return nil
+}
+
+// createNotifyGroup 重新创建本节点的消费者组，从流的末尾开始读取。
+// 同名节点重启前未读的通知已经没有等待方，直接丢弃
+func (p *RedisNotifier) createNotifyGroup(ctx context.Context) error {
+	err := p.redisClient.XGroupCreateMkStream(ctx, notifyStreamKey, p.nodeId, "$").Err()
+	if err == nil {
+		return nil
+	}
+	if !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("XGroupCreateMkStream: %w", err)
+	}
+	err = p.redisClient.XGroupDestroy(ctx, notifyStreamKey, p.nodeId).Err()
+	if err != nil {
+		return fmt.Errorf("XGroupDestroy: %w", err)
+	}
+	err = p.redisClient.XGroupCreateMkStream(ctx, notifyStreamKey, p.nodeId, "$").Err()
+	if err != nil {
+		return fmt.Errorf("XGroupCreateMkStream: %w", err)
+	}
+	return nil
+}
+
+func (p *RedisNotifier) dispatchNotify() {
+	ctx := context.Background()
+	readFailed := false
+	for {
+		streams, err := p.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    p.nodeId,
+			Consumer: p.nodeId,
+			Streams:  []string{notifyStreamKey, ">"},
+			Count:    1000,
+			Block:    notifyReadBlock,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			if errors.Is(err, redis.ErrClosed) {
+				return
+			}
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				// Redis 重启丢失数据，或本节点长时间失联被当作过期节点清理。期间的通知已丢失
+				Log.Warn("user notify group %s lost, recreating", p.nodeId)
+				err = p.recoverNotifyGroup(ctx)
+				if err == nil {
+					continue
+				}
+			}
+			Log.Error("read user notify error: %v", err)
+			readFailed = true
+			time.Sleep(time.Second)
+			continue
+		}
+		if readFailed {
+			// 断开期间的通知仍在流中，但断开太久时可能已被裁剪，让所有等待方重新读库
+			readFailed = false
+			p.local.wakeAll()
+		}
+
+		for _, stream := range streams {
+			ids := make([]string, 0, len(stream.Messages))
+			for _, msg := range stream.Messages {
+				ids = append(ids, msg.ID)
+				uidStr, _ := msg.Values["uid"].(string)
+				uid, err := strconv.ParseUint(uidStr, 10, 64)
+				if err != nil {
+					Log.Warn("invalid user notify %s: %v", msg.ID, msg.Values)
+					continue
+				}
+				p.local.Notify(uid)
+			}
+			err = p.redisClient.XAck(ctx, notifyStreamKey, p.nodeId, ids...).Err()
+			if err != nil {
+				Log.Warn("ack user notify error: %v", err)
+			}
+		}
+	}
+}
+
+func (p *RedisNotifier) recoverNotifyGroup(ctx context.Context) error {
+	err := p.refreshNotifyNode(ctx)
+	if err != nil {
+		return fmt.Errorf("refreshNotifyNode: %w", err)
+	}
+	err = p.createNotifyGroup(ctx)
+	if err != nil {
+		return fmt.Errorf("createNotifyGroup: %w", err)
+	}
+	// 丢失的通知无从得知，让所有等待方重新读库
+	p.local.wakeAll()
+	return nil
+}
+
+// refreshNotifyNode 登记本节点并刷新存活标记
+func (p *RedisNotifier) refreshNotifyNode(ctx context.Context) error {
+	_, err := p.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, notifyNodesKey, p.nodeId)
+		pipe.Set(ctx, notifyNodeKey(p.nodeId), time.Now().Unix(), notifyNodeTTL)
+		return nil
+	})
+	return err
+}
+
+// cleanNotifyNodes 删除存活标记已过期的节点的消费者组
+func (p *RedisNotifier) cleanNotifyNodes(ctx context.Context) error {
+	nodeIds, err := p.redisClient.SMembers(ctx, notifyNodesKey).Result()
+	if err != nil {
+		return fmt.Errorf("SMembers: %w", err)
+	}
+	for _, nodeId := range nodeIds {
+		if nodeId == p.nodeId {
+			continue
+		}
+		n, err := p.redisClient.Exists(ctx, notifyNodeKey(nodeId)).Result()
+		if err != nil {
+			return fmt.Errorf("Exists: %w", err)
+		}
+		if n > 0 {
+			continue
+		}
+		err = p.redisClient.XGroupDestroy(ctx, notifyStreamKey, nodeId).Err()
+		if err != nil {
+			return fmt.Errorf("XGroupDestroy: %w", err)
+		}
+		err = p.redisClient.SRem(ctx, notifyNodesKey, nodeId).Err()
+		if err != nil {
+			return fmt.Errorf("SRem: %w", err)
+		}
+		Log.Info("removed expired user notify node %s", nodeId)
+	}
+	return nil
+}
+
+// maintainNotify 重试写入失败的通知，定期刷新存活标记并清理过期节点
+func (p *RedisNotifier) maintainNotify() {
+	ctx := context.Background()
+	retryTicker := time.NewTicker(notifyRetryInterval)
+	defer retryTicker.Stop()
+	nodeTicker := time.NewTicker(notifyNodeRefresh)
+	defer nodeTicker.Stop()
+
+	for {
+		select {
+		case <-retryTicker.C:
+			p.retryNotify(ctx)
+		case <-nodeTicker.C:
+			err := p.refreshNotifyNode(ctx)
+			if errors.Is(err, redis.ErrClosed) {
+				return
+			}
+			if err != nil {
+				Log.Error("refresh user notify node error: %v", err)
+				continue
+			}
+			err = p.cleanNotifyNodes(ctx)
+			if err != nil {
+				Log.Error("clean user notify nodes error: %v", err)
+			}
+		}
+	}
+}
+
+func (p *RedisNotifier) retryNotify(ctx context.Context) {
+	p.notifyMu.Lock()
+	pending := p.notifyPending
+	p.notifyPending = make(map[uint64]struct{})
+	p.notifyMu.Unlock()
+
+	for uid := range pending {
+		err := p.addNotify(ctx, uid)
+		if err != nil {
+			// Redis 仍不可用，剩下的下次再试
+			p.notifyMu.Lock()
+			for rest := range pending {
+				p.notifyPending[rest] = struct{}{}
+			}
+			p.notifyMu.Unlock()
+			return
+		}
+		delete(pending, uid)
+	}
+}
+
+func (p *RedisNotifier) addNotify(ctx context.Context, uid uint64) error {
+	return p.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: notifyStreamKey,
+		MaxLen: notifyStreamMaxLen,
+		Approx: true,
+		Values: []string{"uid", strconv.FormatUint(uid, 10)},
+	}).Err()
+}
+
+// Notify 唤醒本节点的等待方，并写入通知流通知其他节点。写入失败时在后台重试
+func (p *RedisNotifier) Notify(uid uint64) {
+	// 本节点的等待方直接唤醒，不经过 Redis
+	p.local.Notify(uid)
+
+	err := p.addNotify(context.Background(), uid)
+	if err != nil {
+		Log.Error("notify user %d error: %v", uid, err)
+		p.notifyMu.Lock()
+		p.notifyPending[uid] = struct{}{}
+		p.notifyMu.Unlock()
+	}
+}
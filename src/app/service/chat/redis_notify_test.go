@@ -0,0 +1,65 @@
+package chat
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"social_server/src/app/common/types"
+	"social_server/src/app/data"
+	"testing"
+	"time"
+)
+
+// 通知写入 Redis 失败时在后台重试，其他节点上的等待方仍能及时收到
+func TestNotifyAcrossNodesRetried(t *testing.T) {
+	const uid = 3003
+	mr := miniredis.RunT(t)
+	storage := data.NewMemStorage()
+	nodeA := newRedisTestChat(t, mr, "node-a", storage)
+	nodeB := newRedisTestChat(t, mr, "node-b", storage)
+
+	// 通知丢失时要等到 58 秒超时，这里 10 秒内必须返回
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	type result struct {
+		msgList []types.ChatMsgOfConv
+		err     error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		msgList, _, err := nodeA.GetChatMsgList(ctx, uid, 0, 10)
+		resultCh <- result{msgList, err}
+	}()
+	waitForWaiters(t, ctx, nodeA, uid, 1)
+
+	err := storage.ChatSendMsgToUser(ctx, uid, testMsg(uid))
+	if err != nil {
+		t.Fatalf("ChatSendMsgToUser: %v", err)
+	}
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	nodeB.NotifyAUserCond(uid)
+	notifierB := nodeB.notifier.(*RedisNotifier)
+	notifierB.notifyMu.Lock()
+	_, pending := notifierB.notifyPending[uid]
+	notifierB.notifyMu.Unlock()
+	if !pending {
+		t.Fatal("failed notify not queued for retry")
+	}
+	time.Sleep(200 * time.Millisecond)
+	mr.SetError("")
+
+	r := <-resultCh
+	if r.err != nil {
+		t.Fatalf("GetChatMsgList: %v", r.err)
+	}
+	if len(r.msgList) != 1 {
+		t.Fatalf("got %d msgs, want 1", len(r.msgList))
+	}
+	n, err := notifierB.redisClient.XLen(context.Background(), notifyStreamKey).Result()
+	if err != nil {
+		t.Fatalf("XLen: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("notify stream has %d entries, want 1", n)
+	}
+}
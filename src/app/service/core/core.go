@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"github.com/joho/godotenv"
 	"log"
@@ -20,11 +21,29 @@ import (
 type Core struct {
 	userMgmt *user_mgmt.UserMgmt
 	sessMgmt *sess_mgmt.SessMgmt
+	loginGuard *sess_mgmt.LoginGuard
 	chat     *Chat
 	sessTimoutS uint64
+	subscribeHeartbeatS uint64
 }
 
-func NewCore() *Core {
+const (
+	subscribeMinHeartbeatS uint64 = 5
+	subscribeMaxHeartbeatS uint64 = 120
+
+	// 每次拉取或推送的消息数上限，避免离线很久的设备一次收到过大的响应
+	updateListMaxCount uint32 = 500
+
+	chatHistoryDefaultLimit uint32 = 20
+	chatHistoryMaxLimit     uint32 = 100
+
+	// 客户端提供的设备信息只用于显示，超长的截断
+	sessDeviceNameMaxLen = 64
+	sessPlatformMaxLen   = 32
+)
+
+// LoadEnv 加载 .env 文件中的环境变量
+func LoadEnv() {
 	// 获取 ENV_PATH 环境变量的值
 	envPath := os.Getenv("ENV_PATH")
 
@@ -42,31 +61,73 @@ func NewCore() *Core {
 			log.Println("Error loading default .env file")
 		}
 	}
+}
+
+func NewCore() *Core {
+	LoadEnv()
 
-	storage := data.NewStorage()
-	storage.Init()
-	cache := data.NewCache()
+	storage, cache, err := data.NewStorageBackend()
+	if err != nil {
+		log.Fatalf("NewStorageBackend: %v", err)
+	}
+
+	notifier, err := NewNotifier()
+	if err != nil {
+		log.Fatalf("NewNotifier: %v", err)
+	}
+
+	sessMgmt, err := sess_mgmt.NewSessMgmt(storage, cache)
+	if err != nil {
+		log.Fatalf("NewSessMgmt: %v", err)
+	}
+
+	loginGuard, err := sess_mgmt.NewLoginGuard(cache)
+	if err != nil {
+		log.Fatalf("NewLoginGuard: %v", err)
+	}
 
 	p := &Core{
 		userMgmt: user_mgmt.NewUserMgmt(storage, cache),
-		sessMgmt: sess_mgmt.NewSessMgmt(storage, cache),
-		chat:     NewChat(storage, cache),
+		sessMgmt: sessMgmt,
+		loginGuard: loginGuard,
+		chat:     NewChat(storage, cache, notifier),
 		sessTimoutS: 60 * 60 * 2, // 2小时
+		subscribeHeartbeatS: 25,
+	}
+	if sessMgmt.TokenMode() {
+		// 会话作为刷新令牌，有效期与刷新令牌相同
+		p.sessTimoutS = sessMgmt.RefreshTokenTtlS()
 	}
 
 	return p
 }
 
-func (p *Core) SessUserLogin(req *gen_grpc.SessUserLoginReq) (*gen_grpc.SessUserLoginRes, error) {
+func (p *Core) SessUserLogin(ctx context.Context, req *gen_grpc.SessUserLoginReq) (*gen_grpc.SessUserLoginRes, error) {
 	var err error
 	var res gen_grpc.SessUserLoginRes
 
+	// 登录失败次数过多时拒绝
+	ip := clientIpFromContext(ctx)
+	var lockedS uint64
+	lockedS, err = p.loginGuard.Check(ctx, req.GetUsername(), ip)
+	if err != nil {
+		Log.Error("LoginGuard.Check: %s", err.Error())
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+		return &res, nil
+	}
+	if lockedS > 0 {
+		Log.Warn("Login locked, username: %s, ip: %s", req.GetUsername(), ip)
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_LoginLocked
+		res.RetryAfterS = lockedS
+		return &res, nil
+	}
+
 	// 校验用户
 	var uaParam types.UmUserAuthenticateParam
 	uaParam.Username = req.GetUsername()
-	uaParam.Passphase = utils.CalPassHash(req.GetPassword())
+	uaParam.Passphase = req.GetPassword()
 	var pass bool
-	pass, err = p.userMgmt.UserAuthenticate(&uaParam)
+	pass, err = p.userMgmt.UserAuthenticate(ctx, &uaParam)
 	if err != nil {
 		Log.Error("UserAuthenticate: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -74,13 +135,26 @@ func (p *Core) SessUserLogin(req *gen_grpc.SessUserLoginReq) (*gen_grpc.SessUser
 	}
 	if !pass {
 		Log.Error("User auth failed")
+		lockedS, err = p.loginGuard.Fail(ctx, req.GetUsername(), ip)
+		if err != nil {
+			Log.Error("LoginGuard.Fail: %s", err.Error())
+		}
+		if lockedS > 0 {
+			res.ErrCode = gen_grpc.ErrCode_emErrCode_LoginLocked
+			res.RetryAfterS = lockedS
+			return &res, nil
+		}
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UserFailedToAuth
 		return &res, nil
 	}
+	err = p.loginGuard.Succeed(ctx, req.GetUsername())
+	if err != nil {
+		Log.Error("LoginGuard.Succeed: %s", err.Error())
+	}
 
 	// 获取用户信息
 	var userInfo *types.UmUserInfo
-	userInfo, err = p.userMgmt.UserGetInfoByUsername(req.GetUsername())
+	userInfo, err = p.userMgmt.UserGetInfoByUsername(ctx, req.GetUsername())
 	if err != nil {
 		Log.Error("UserGetInfoByUsername: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -89,7 +163,12 @@ func (p *Core) SessUserLogin(req *gen_grpc.SessUserLoginReq) (*gen_grpc.SessUser
 
 	// 创建新会话
 	var sessId types.SessId
-	sessId, err = p.sessMgmt.CreateSess(userInfo.Username, userInfo.Uid, p.sessTimoutS)
+	device := types.SessDevice{
+		DeviceName: truncateRunes(req.GetDeviceName(), sessDeviceNameMaxLen),
+		Platform:   truncateRunes(req.GetPlatform(), sessPlatformMaxLen),
+		Ip:         ip,
+	}
+	sessId, err = p.sessMgmt.CreateSess(ctx, userInfo.Username, userInfo.Uid, device, p.sessTimoutS)
 	if err != nil {
 		Log.Error("CreateSess: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -97,29 +176,140 @@ func (p *Core) SessUserLogin(req *gen_grpc.SessUserLoginReq) (*gen_grpc.SessUser
 	}
 	Log.Debug("SessId: %s", res.SessId)
 
-	res.SessId = string(sessId)
+	if p.sessMgmt.TokenMode() {
+		// 签发访问令牌，会话 id 只作为刷新令牌返回
+		var expiresAt uint64
+		res.AccessToken, expiresAt, err = p.sessMgmt.IssueAccessToken(&types.SessCtx{
+			Uid:      userInfo.Uid,
+			Username: userInfo.Username,
+			SessKey:  sess_mgmt.SessKey(sessId),
+		})
+		if err != nil {
+			Log.Error("IssueAccessToken: %s", err.Error())
+			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+			return &res, nil
+		}
+		res.AccessTokenExpiresTsMs = expiresAt * 1000
+		res.RefreshToken = string(sessId)
+	} else {
+		res.SessId = string(sessId)
+	}
 	res.Uid = userInfo.Uid
 	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
 
 	return &res, nil
 }
 
-func (p *Core) SessUserLogout(req *gen_grpc.SessUserLogoutReq) (*gen_grpc.SessUserLogoutRes, error) {
+func (p *Core) SessRefresh(ctx context.Context, req *gen_grpc.SessRefreshReq) (*gen_grpc.SessRefreshRes, error) {
+	var err error
+	var res gen_grpc.SessRefreshRes
+
+	if !p.sessMgmt.TokenMode() {
+		Log.Error("SessRefresh: access tokens are not enabled")
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+		return &res, nil
+	}
+
+	// 刷新令牌即会话 id
+	var sessCtx *types.SessCtx
+	sessCtx, res.ErrCode = p.ResolveRefreshToken(ctx, req.GetRefreshToken())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return &res, nil
+	}
+
+	// 会话续期
+	err = p.sessMgmt.RenewSessCtx(ctx, sessCtx, p.sessTimoutS)
+	if err != nil {
+		Log.Warn("RenewSessCtx: %v", err)
+	}
+
+	// 签发新的访问令牌
+	var expiresAt uint64
+	res.AccessToken, expiresAt, err = p.sessMgmt.IssueAccessToken(sessCtx)
+	if err != nil {
+		Log.Error("IssueAccessToken: %s", err.Error())
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+		return &res, nil
+	}
+	res.AccessTokenExpiresTsMs = expiresAt * 1000
+
+	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
+	return &res, nil
+}
+
+func (p *Core) SessUserLogout(ctx context.Context, req *gen_grpc.SessUserLogoutReq) (*gen_grpc.SessUserLogoutRes, error) {
 	var err error
 	var res gen_grpc.SessUserLogoutRes
 
 	// 获取会话
-	_, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
+	var sessCtx *types.SessCtx
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return &res, nil
+	}
+
+	// 销毁会话
+	err = p.sessMgmt.DeleteSessCtx(ctx, sessCtx)
+	if err != nil {
+		Log.Error("DeleteSessCtx: %s", err.Error())
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+		return &res, nil
+	}
+
+	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
+	return &res, nil
+}
+
+func (p *Core) SessGetList(ctx context.Context, req *gen_grpc.SessGetListReq) (*gen_grpc.SessGetListRes, error) {
+	var err error
+	var res gen_grpc.SessGetListRes
+
+	// 获取会话
+	var sessCtx *types.SessCtx
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return &res, nil
+	}
+
+	// 获取用户的所有会话
+	var sessList []*types.SessCtx
+	sessList, err = p.sessMgmt.GetUserSessList(ctx, sessCtx.Uid)
 	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
+		Log.Error("GetUserSessList: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
 		return &res, nil
 	}
+	for _, aSess := range sessList {
+		res.SessList = append(res.SessList, &gen_grpc.SessInfo{
+			SessKey:      aSess.SessKey,
+			DeviceName:   aSess.DeviceName,
+			Platform:     aSess.Platform,
+			Ip:           aSess.Ip,
+			CreateTsMs:   aSess.CreatedAt * 1000,
+			LastSeenTsMs: aSess.LastSeenAt * 1000,
+			IsCurrent:    aSess.SessKey == sessCtx.SessKey,
+		})
+	}
 
-	// 销毁会话
-	err = p.sessMgmt.DeleteSess(types.SessId(req.GetSessId()))
+	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
+	return &res, nil
+}
+
+func (p *Core) SessRevoke(ctx context.Context, req *gen_grpc.SessRevokeReq) (*gen_grpc.SessRevokeRes, error) {
+	var err error
+	var res gen_grpc.SessRevokeRes
+
+	// 获取会话
+	var sessCtx *types.SessCtx
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return &res, nil
+	}
+
+	// 只能下线自己的会话，已不存在的会话视为已下线
+	_, err = p.sessMgmt.DeleteUserSessByKey(ctx, sessCtx.Uid, req.GetSessKey())
 	if err != nil {
-		Log.Error("DeleteSess: %s", err.Error())
+		Log.Error("DeleteUserSessByKey: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
 		return &res, nil
 	}
@@ -128,7 +318,30 @@ func (p *Core) SessUserLogout(req *gen_grpc.SessUserLogoutReq) (*gen_grpc.SessUs
 	return &res, nil
 }
 
-func (p *Core) UmRegister(req *gen_grpc.UmRegisterReq) (*gen_grpc.UmRegisterRes, error) {
+func (p *Core) SessRevokeOthers(ctx context.Context, req *gen_grpc.SessRevokeOthersReq) (*gen_grpc.SessRevokeOthersRes, error) {
+	var err error
+	var res gen_grpc.SessRevokeOthersRes
+
+	// 获取会话
+	var sessCtx *types.SessCtx
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return &res, nil
+	}
+
+	// 下线其他会话
+	err = p.sessMgmt.DeleteOtherUserSess(ctx, sessCtx.Uid, sessCtx.SessKey)
+	if err != nil {
+		Log.Error("DeleteOtherUserSess: %s", err.Error())
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+		return &res, nil
+	}
+
+	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
+	return &res, nil
+}
+
+func (p *Core) UmRegister(ctx context.Context, req *gen_grpc.UmRegisterReq) (*gen_grpc.UmRegisterRes, error) {
 	var err error
 	var res gen_grpc.UmRegisterRes
 
@@ -146,7 +359,7 @@ func (p *Core) UmRegister(req *gen_grpc.UmRegisterReq) (*gen_grpc.UmRegisterRes,
 
 	// 检查用户名是否已存在
 	var isExist bool
-	isExist, err = p.userMgmt.IsUsernameExisted(req.GetUsername())
+	isExist, err = p.userMgmt.IsUsernameExisted(ctx, req.GetUsername())
 	if err != nil {
 		Log.Error("UserIsUsernameExisted: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -161,11 +374,16 @@ func (p *Core) UmRegister(req *gen_grpc.UmRegisterReq) (*gen_grpc.UmRegisterRes,
 	// 创建用户
 	var regParam types.UmRegisterParam
 	regParam.Username = req.GetUsername()
-	regParam.Passwd = utils.CalPassHash(req.GetPassword())
+	regParam.Passwd, err = utils.HashPassword(req.GetPassword())
+	if err != nil {
+		Log.Error("HashPassword: %s", err.Error())
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+		return &res, nil
+	}
 	regParam.Nickname = req.GetNickname()
 	regParam.Email = req.GetEmail()
 	regParam.Avatar = req.GetAvatar()
-	err = p.userMgmt.Register(&regParam)
+	err = p.userMgmt.Register(ctx, &regParam)
 	if err != nil {
 		Log.Error("Register: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -176,23 +394,21 @@ func (p *Core) UmRegister(req *gen_grpc.UmRegisterReq) (*gen_grpc.UmRegisterRes,
 	return &res, nil
 }
 
-func (p *Core) UmUnregister(req *gen_grpc.UmUnregisterReq) (*gen_grpc.UmUnregisterRes, error) {
+func (p *Core) UmUnregister(ctx context.Context, req *gen_grpc.UmUnregisterReq) (*gen_grpc.UmUnregisterRes, error) {
 	var err error
 	var res gen_grpc.UmUnregisterRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 注销用户
 	var unRegParam types.UmUnregisterParam
 	unRegParam.Uid = sessCtx.Uid
-	err = p.userMgmt.Unregister(&unRegParam)
+	err = p.userMgmt.Unregister(ctx, &unRegParam)
 	if err != nil {
 		Log.Error("Unregister: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -200,7 +416,7 @@ func (p *Core) UmUnregister(req *gen_grpc.UmUnregisterReq) (*gen_grpc.UmUnregist
 	}
 
 	// 销毁用户会话
-	err = p.sessMgmt.DeleteUserSess(sessCtx.Uid)
+	err = p.sessMgmt.DeleteUserSess(ctx, sessCtx.Uid)
 	if err != nil {
 		Log.Error("DeleteUserSess: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -211,23 +427,18 @@ func (p *Core) UmUnregister(req *gen_grpc.UmUnregisterReq) (*gen_grpc.UmUnregist
 	return &res, nil
 }
 
-func (p *Core) UmUserUpdateInfo(req *gen_grpc.UmUserUpdateInfoReq) (*gen_grpc.UmUserUpdateInfoRes, error) {
+func (p *Core) UmUserUpdateInfo(ctx context.Context, req *gen_grpc.UmUserUpdateInfoReq) (*gen_grpc.UmUserUpdateInfoRes, error) {
 	var err error
 	var res gen_grpc.UmUserUpdateInfoRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
-	var password = ""
-	if req.GetPassword() != "" {
-		password = utils.CalPassHash(req.GetPassword())
-	}
+	var password = req.GetPassword()
 	var newPassword = ""
 	if req.GetNewPassword() != "" {
 		if !p.userMgmt.ValidatePassword(req.GetNewPassword()) {
@@ -235,37 +446,55 @@ func (p *Core) UmUserUpdateInfo(req *gen_grpc.UmUserUpdateInfoReq) (*gen_grpc.Um
 			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
 			return &res, nil
 		}
-		newPassword = utils.CalPassHash(req.GetNewPassword())
+		newPassword, err = utils.HashPassword(req.GetNewPassword())
+		if err != nil {
+			Log.Error("HashPassword: %s", err.Error())
+			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+			return &res, nil
+		}
 	}
 	// 更新用户信息
-	err = p.userMgmt.UserUpdateInfo(sessCtx.Uid, req.GetNickname(),
+	err = p.userMgmt.UserUpdateInfo(ctx, sessCtx.Uid, req.GetNickname(),
 		req.GetEmail(), req.GetAvatar(), password, newPassword)
+	if errors.Is(err, proj_err.ErrWrongPassword) {
+		Log.Warn("UserUpdateInfo: %s", err.Error())
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UserFailedToAuth
+		return &res, nil
+	}
 	if err != nil {
 		Log.Error("UserUpdateInfo: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
 		return &res, nil
 	}
 
+	// 修改密码后下线其他设备。失败时密码已经修改，返回错误让客户端再调用 SessRevokeOthers
+	if newPassword != "" {
+		err = p.sessMgmt.DeleteOtherUserSess(ctx, sessCtx.Uid, sessCtx.SessKey)
+		if err != nil {
+			Log.Error("DeleteOtherUserSess: %s", err.Error())
+			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+			return &res, nil
+		}
+	}
+
 	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
 	return &res, nil
 }
 
-func (p *Core) UmContactGetList(req *gen_grpc.UmContactGetListReq) (*gen_grpc.UmContactGetListRes, error) {
+func (p *Core) UmContactGetList(ctx context.Context, req *gen_grpc.UmContactGetListReq) (*gen_grpc.UmContactGetListRes, error) {
 	var err error
 	var res gen_grpc.UmContactGetListRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 获取好友列表
 	var contactUidList []uint64
-	contactUidList, err = p.userMgmt.ContactGetList(sessCtx.Uid)
+	contactUidList, err = p.userMgmt.ContactGetList(ctx, sessCtx.Uid)
 	if err != nil {
 		Log.Error("ContactGetList: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -275,14 +504,14 @@ func (p *Core) UmContactGetList(req *gen_grpc.UmContactGetListReq) (*gen_grpc.Um
 	for _, uid := range contactUidList {
 		// 获取联系人信息
 		var userInfo *types.UmUserInfo
-		userInfo, err = p.userMgmt.ContactGetInfo(uid)
+		userInfo, err = p.userMgmt.ContactGetInfo(ctx, uid)
 		if err != nil {
 			Log.Error("ContactGetInfo: %s", err.Error())
 			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
 			return &res, nil
 		}
 
-		isMutualContact, remarkName, err := p.userMgmt.ContactGetRelation(sessCtx.Uid, uid)
+		isMutualContact, remarkName, err := p.userMgmt.ContactGetRelation(ctx, sessCtx.Uid, uid)
 		if err != nil {
 			Log.Error("ContactGetRelation: %s", err.Error())
 			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -304,29 +533,27 @@ func (p *Core) UmContactGetList(req *gen_grpc.UmContactGetListReq) (*gen_grpc.Um
 	return &res, nil
 }
 
-func (p *Core) UmContactGetInfo(req *gen_grpc.UmContactGetInfoReq) (*gen_grpc.UmContactGetInfoRes, error) {
+func (p *Core) UmContactGetInfo(ctx context.Context, req *gen_grpc.UmContactGetInfoReq) (*gen_grpc.UmContactGetInfoRes, error) {
 	var err error
 	var res gen_grpc.UmContactGetInfoRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 查找用户
 	var userInfo *types.UmUserInfo
-	userInfo, err = p.userMgmt.ContactGetInfo(req.GetUserId())
+	userInfo, err = p.userMgmt.ContactGetInfo(ctx, req.GetUserId())
 	if err != nil {
 		Log.Error("ContactFind: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
 		return &res, nil
 	}
 
-	isMutualContact, remarkName, err := p.userMgmt.ContactGetRelation(sessCtx.Uid, req.GetUserId())
+	isMutualContact, remarkName, err := p.userMgmt.ContactGetRelation(ctx, sessCtx.Uid, req.GetUserId())
 	if err != nil {
 		Log.Error("ContactGetRelation: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -348,29 +575,27 @@ func (p *Core) UmContactGetInfo(req *gen_grpc.UmContactGetInfoReq) (*gen_grpc.Um
 	return &res, nil
 }
 
-func (p *Core) UmContactFind(req *gen_grpc.UmContactFindReq) (*gen_grpc.UmContactFindRes, error) {
+func (p *Core) UmContactFind(ctx context.Context, req *gen_grpc.UmContactFindReq) (*gen_grpc.UmContactFindRes, error) {
 	var err error
 	var res gen_grpc.UmContactFindRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 查找用户
 	var userInfo *types.UmUserInfo
-	userInfo, err = p.userMgmt.ContactFind(req.GetUsername())
+	userInfo, err = p.userMgmt.ContactFind(ctx, req.GetUsername())
 	if err != nil {
 		Log.Error("ContactFind: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
 		return &res, nil
 	}
 
-	isMutualContact, remarkName, err := p.userMgmt.ContactGetRelation(sessCtx.Uid, userInfo.Uid)
+	isMutualContact, remarkName, err := p.userMgmt.ContactGetRelation(ctx, sessCtx.Uid, userInfo.Uid)
 	if err != nil {
 		Log.Error("ContactGetRelation: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -392,21 +617,19 @@ func (p *Core) UmContactFind(req *gen_grpc.UmContactFindReq) (*gen_grpc.UmContac
 	return &res, nil
 }
 
-func (p *Core) UmContactAddRequest(req *gen_grpc.UmContactAddRequestReq) (*gen_grpc.UmContactAddRequestRes, error) {
+func (p *Core) UmContactAddRequest(ctx context.Context, req *gen_grpc.UmContactAddRequestReq) (*gen_grpc.UmContactAddRequestRes, error) {
 	var err error
 	var res gen_grpc.UmContactAddRequestRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 判断是否已为好友
-	isMutualContact, _, err := p.userMgmt.ContactGetRelation(sessCtx.Uid, req.GetContactUid())
+	isMutualContact, _, err := p.userMgmt.ContactGetRelation(ctx, sessCtx.Uid, req.GetContactUid())
 	if err != nil {
 		Log.Error("ContactGetRelation: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -423,7 +646,7 @@ func (p *Core) UmContactAddRequest(req *gen_grpc.UmContactAddRequestReq) (*gen_g
 	msg.ReceiverId.Uid = req.GetContactUid()
 	msg.Msg.SenderUid = sessCtx.Uid
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_ContactAddReq
-	err = p.chat.SendMsg(msg)
+	err = p.chat.SendMsg(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -434,21 +657,19 @@ func (p *Core) UmContactAddRequest(req *gen_grpc.UmContactAddRequestReq) (*gen_g
 	return &res, nil
 }
 
-func (p *Core) UmContactAccept(req *gen_grpc.UmContactAcceptReq) (*gen_grpc.UmContactAcceptRes, error) {
+func (p *Core) UmContactAccept(ctx context.Context, req *gen_grpc.UmContactAcceptReq) (*gen_grpc.UmContactAcceptRes, error) {
 	var err error
 	var res gen_grpc.UmContactAcceptRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 同意好友请求
-	err = p.userMgmt.ContactAccept(sessCtx.Uid, req.GetContactUid())
+	err = p.userMgmt.ContactAccept(ctx, sessCtx.Uid, req.GetContactUid())
 	if err != nil {
 		Log.Error("ContactAccept: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -462,7 +683,7 @@ func (p *Core) UmContactAccept(req *gen_grpc.UmContactAcceptReq) (*gen_grpc.UmCo
 	msg.Msg.SenderUid = sessCtx.Uid
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_ContactAdded
 	msg.Msg.MsgContent = "我通过了你的朋友验证请求，现在我们可以开始聊天了"
-	err = p.chat.SendMsg(msg)
+	err = p.chat.SendMsg(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -473,21 +694,19 @@ func (p *Core) UmContactAccept(req *gen_grpc.UmContactAcceptReq) (*gen_grpc.UmCo
 	return &res, nil
 }
 
-func (p *Core) UmContactReject(req *gen_grpc.UmContactRejectReq) (*gen_grpc.UmContactRejectRes, error) {
+func (p *Core) UmContactReject(ctx context.Context, req *gen_grpc.UmContactRejectReq) (*gen_grpc.UmContactRejectRes, error) {
 	var err error
 	var res gen_grpc.UmContactRejectRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 拒绝好友请求
-	err = p.userMgmt.ContactReject(sessCtx.Uid, req.GetContactUid())
+	err = p.userMgmt.ContactReject(ctx, sessCtx.Uid, req.GetContactUid())
 	if err != nil {
 		Log.Error("ContactReject: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -500,7 +719,7 @@ func (p *Core) UmContactReject(req *gen_grpc.UmContactRejectReq) (*gen_grpc.UmCo
 	msg.ReceiverId.Uid = req.GetContactUid()
 	msg.Msg.SenderUid = sessCtx.Uid
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_ContactRejected
-	err = p.chat.SendMsg(msg)
+	err = p.chat.SendMsg(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -511,21 +730,19 @@ func (p *Core) UmContactReject(req *gen_grpc.UmContactRejectReq) (*gen_grpc.UmCo
 	return &res, nil
 }
 
-func (p *Core) UmContactDel(req *gen_grpc.UmContactDelReq) (*gen_grpc.UmContactDelRes, error) {
+func (p *Core) UmContactDel(ctx context.Context, req *gen_grpc.UmContactDelReq) (*gen_grpc.UmContactDelRes, error) {
 	var err error
 	var res gen_grpc.UmContactDelRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 删除好友
-	err = p.userMgmt.ContactDel(sessCtx.Uid, req.GetContactUid())
+	err = p.userMgmt.ContactDel(ctx, sessCtx.Uid, req.GetContactUid())
 	if err != nil {
 		Log.Error("ContactDel: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -538,7 +755,7 @@ func (p *Core) UmContactDel(req *gen_grpc.UmContactDelReq) (*gen_grpc.UmContactD
 	msg.ReceiverId.Uid = req.GetContactUid()
 	msg.Msg.SenderUid = sessCtx.Uid
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_ContactDeleted
-	err = p.chat.SendMsg(msg)
+	err = p.chat.SendMsg(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -549,22 +766,20 @@ func (p *Core) UmContactDel(req *gen_grpc.UmContactDelReq) (*gen_grpc.UmContactD
 	return &res, nil
 }
 
-func (p *Core) UmGroupGetList(req *gen_grpc.UmGroupGetListReq) (*gen_grpc.UmGroupGetListRes, error) {
+func (p *Core) UmGroupGetList(ctx context.Context, req *gen_grpc.UmGroupGetListReq) (*gen_grpc.UmGroupGetListRes, error) {
 	var err error
 	var res	gen_grpc.UmGroupGetListRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 获取群聊列表
 	var groupIdList []uint64
-	groupIdList, err = p.userMgmt.GroupGetList(sessCtx.Uid)
+	groupIdList, err = p.userMgmt.GroupGetList(ctx, sessCtx.Uid)
 	if err != nil {
 		Log.Error("GroupGetList: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -574,7 +789,7 @@ func (p *Core) UmGroupGetList(req *gen_grpc.UmGroupGetListReq) (*gen_grpc.UmGrou
 	for _, ConvId := range groupIdList {
 		// 获取群聊信息
 		var groupInfo *types.UmGroupInfo
-		groupInfo, err = p.userMgmt.GroupGetInfo(ConvId)
+		groupInfo, err = p.userMgmt.GroupGetInfo(ctx, ConvId)
 		if err != nil {
 			Log.Error("GroupGetInfo: %s, ConvId: %v", err.Error(), ConvId)
 			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -594,21 +809,19 @@ func (p *Core) UmGroupGetList(req *gen_grpc.UmGroupGetListReq) (*gen_grpc.UmGrou
 	return &res, nil
 }
 
-func (p *Core) UmGroupGetInfo(req *gen_grpc.UmGroupGetInfoReq) (*gen_grpc.UmGroupGetInfoRes, error) {
+func (p *Core) UmGroupGetInfo(ctx context.Context, req *gen_grpc.UmGroupGetInfoReq) (*gen_grpc.UmGroupGetInfoRes, error) {
 	var err error
 	var res gen_grpc.UmGroupGetInfoRes
 
 	// 获取会话
-	_, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	_, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 获取群组信息
 	var groupInfo *types.UmGroupInfo
-	groupInfo, err = p.userMgmt.GroupGetInfo(req.GetGroupId())
+	groupInfo, err = p.userMgmt.GroupGetInfo(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("GroupGetInfo: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -629,21 +842,19 @@ func (p *Core) UmGroupGetInfo(req *gen_grpc.UmGroupGetInfoReq) (*gen_grpc.UmGrou
 }
 
 // UmGroupUpdateInfo
-func (p *Core) UmGroupUpdateInfo(req *gen_grpc.UmGroupUpdateInfoReq) (*gen_grpc.UmGroupUpdateInfoRes, error) {
+func (p *Core) UmGroupUpdateInfo(ctx context.Context, req *gen_grpc.UmGroupUpdateInfoReq) (*gen_grpc.UmGroupUpdateInfoRes, error) {
 	var err error
 	var res gen_grpc.UmGroupUpdateInfoRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 判断是否是群主
-	isOwner, err := p.userMgmt.GroupIsOwner(req.GetGroupId(), sessCtx.Uid)
+	isOwner, err := p.userMgmt.GroupIsOwner(ctx, req.GetGroupId(), sessCtx.Uid)
 	if err != nil {
 		Log.Error("GroupIsOwner: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -655,7 +866,7 @@ func (p *Core) UmGroupUpdateInfo(req *gen_grpc.UmGroupUpdateInfoReq) (*gen_grpc.
 	}
 
 	// 更新群信息
-	err = p.userMgmt.GroupUpdateInfo(req.GetGroupId(), req.GetGroupName(), req.GetAvatar())
+	err = p.userMgmt.GroupUpdateInfo(ctx, req.GetGroupId(), req.GetGroupName(), req.GetAvatar())
 	if err != nil {
 		Log.Error("GroupUpdateInfo: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -666,21 +877,19 @@ func (p *Core) UmGroupUpdateInfo(req *gen_grpc.UmGroupUpdateInfoReq) (*gen_grpc.
 	return &res, nil
 }
 
-func (p *Core) UmGroupFind(req *gen_grpc.UmGroupFindReq) (*gen_grpc.UmGroupFindRes, error) {
+func (p *Core) UmGroupFind(ctx context.Context, req *gen_grpc.UmGroupFindReq) (*gen_grpc.UmGroupFindRes, error) {
 	var err error
 	var res gen_grpc.UmGroupFindRes
 
 	// 获取会话
-	_, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	_, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 查找群组
 	var groupInfo *types.UmGroupInfo
-	groupInfo, err = p.userMgmt.GroupFind(req.GetGroupId())
+	groupInfo, err = p.userMgmt.GroupFind(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("GroupFind: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -698,22 +907,20 @@ func (p *Core) UmGroupFind(req *gen_grpc.UmGroupFindReq) (*gen_grpc.UmGroupFindR
 	return &res, nil
 }
 
-func (p *Core) UmGroupCreate(req *gen_grpc.UmGroupCreateReq) (*gen_grpc.UmGroupCreateRes, error) {
+func (p *Core) UmGroupCreate(ctx context.Context, req *gen_grpc.UmGroupCreateReq) (*gen_grpc.UmGroupCreateRes, error) {
 	var err error
 	var res	gen_grpc.UmGroupCreateRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 创建群聊
 	var groupId uint64
-	groupId, err = p.userMgmt.GroupCreate(sessCtx.Uid, req.GroupName)
+	groupId, err = p.userMgmt.GroupCreate(ctx, sessCtx.Uid, req.GroupName)
 	if err != nil {
 		Log.Error("GroupCreate: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -726,7 +933,7 @@ func (p *Core) UmGroupCreate(req *gen_grpc.UmGroupCreateReq) (*gen_grpc.UmGroupC
 	msg.ReceiverId.GroupId = groupId
 	msg.Msg.SenderUid = sessCtx.Uid
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_GroupCreated
-	err = p.chat.SendMsg(msg)
+	err = p.chat.SendMsg(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -740,21 +947,19 @@ func (p *Core) UmGroupCreate(req *gen_grpc.UmGroupCreateReq) (*gen_grpc.UmGroupC
 }
 
 // TODO：先标记、再通知、再删除
-func (p *Core) UmGroupDelete(req *gen_grpc.UmGroupDeleteReq) (*gen_grpc.UmGroupDeleteRes, error) {
+func (p *Core) UmGroupDelete(ctx context.Context, req *gen_grpc.UmGroupDeleteReq) (*gen_grpc.UmGroupDeleteRes, error) {
 	var err error
 	var res	gen_grpc.UmGroupDeleteRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 分配群聊消息序列号
-	seqId, err := p.chat.AllocateGroupSeqId(req.GetGroupId())
+	seqId, err := p.chat.AllocateGroupSeqId(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("AllocateGroupSeqId: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -763,7 +968,7 @@ func (p *Core) UmGroupDelete(req *gen_grpc.UmGroupDeleteReq) (*gen_grpc.UmGroupD
 
 	// 获取成员列表
 	var memList []uint64
-	memList, err = p.userMgmt.GroupGetMemList(req.GetGroupId())
+	memList, err = p.userMgmt.GroupGetMemList(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("GroupGetMemList: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -771,7 +976,7 @@ func (p *Core) UmGroupDelete(req *gen_grpc.UmGroupDeleteReq) (*gen_grpc.UmGroupD
 	}
 
 	// 解散群聊
-	err = p.userMgmt.GroupDelete(sessCtx.Uid, req.GetGroupId())
+	err = p.userMgmt.GroupDelete(ctx, sessCtx.Uid, req.GetGroupId())
 	if err != nil {
 		Log.Error("GroupDelete: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -786,7 +991,7 @@ func (p *Core) UmGroupDelete(req *gen_grpc.UmGroupDeleteReq) (*gen_grpc.UmGroupD
 		msg.Msg.SenderUid = sessCtx.Uid
 		msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_GroupDeleted
 		msg.ConvMsgId = seqId
-		err = p.chat.SendMsgToUser(uid, msg)
+		err = p.chat.SendMsgToUser(ctx, uid, msg)
 		if err != nil {
 			Log.Error("SendMsg: %s", err.Error())
 		}
@@ -796,21 +1001,19 @@ func (p *Core) UmGroupDelete(req *gen_grpc.UmGroupDeleteReq) (*gen_grpc.UmGroupD
 	return &res, nil
 }
 
-func (p *Core) UmGroupGetMemList(req *gen_grpc.UmGroupGetMemListReq) (*gen_grpc.UmGroupGetMemListRes, error) {
+func (p *Core) UmGroupGetMemList(ctx context.Context, req *gen_grpc.UmGroupGetMemListReq) (*gen_grpc.UmGroupGetMemListRes, error) {
 	var err error
 	var res gen_grpc.UmGroupGetMemListRes
 
 	// 获取会话
-	_, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	_, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 获取群成员列表
 	var UidList []uint64
-	UidList, err = p.userMgmt.GroupGetMemList(req.GetGroupId())
+	UidList, err = p.userMgmt.GroupGetMemList(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("GroupGetMemList: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -822,21 +1025,19 @@ func (p *Core) UmGroupGetMemList(req *gen_grpc.UmGroupGetMemListReq) (*gen_grpc.
 	return &res, nil
 }
 
-func (p *Core) UmGroupJoinRequest(req *gen_grpc.UmGroupJoinRequestReq) (*gen_grpc.UmGroupJoinRequestRes, error) {
+func (p *Core) UmGroupJoinRequest(ctx context.Context, req *gen_grpc.UmGroupJoinRequestReq) (*gen_grpc.UmGroupJoinRequestRes, error) {
 	var err error
 	var res gen_grpc.UmGroupJoinRequestRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 判断是否已是群员
-	inGroup, err := p.userMgmt.GroupIsMem(req.GetGroupId(), sessCtx.Uid)
+	inGroup, err := p.userMgmt.GroupIsMem(ctx, req.GetGroupId(), sessCtx.Uid)
 	if err != nil {
 		Log.Error("GroupIsMem: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -853,7 +1054,7 @@ func (p *Core) UmGroupJoinRequest(req *gen_grpc.UmGroupJoinRequestReq) (*gen_grp
 	msg.ReceiverId.GroupId = req.GetGroupId()
 	msg.Msg.SenderUid = sessCtx.Uid
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_GroupJoinReq
-	err = p.chat.SendMsgToAdmins(msg)
+	err = p.chat.SendMsgToAdmins(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsgToAdmins: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -864,19 +1065,19 @@ func (p *Core) UmGroupJoinRequest(req *gen_grpc.UmGroupJoinRequestReq) (*gen_grp
 	return &res, nil
 }
 
-func (p *Core) UmGroupAccept(req *gen_grpc.UmGroupAcceptReq) (*gen_grpc.UmGroupAcceptRes, error) {
+func (p *Core) UmGroupAccept(ctx context.Context, req *gen_grpc.UmGroupAcceptReq) (*gen_grpc.UmGroupAcceptRes, error) {
 	var err error
 	var res gen_grpc.UmGroupAcceptRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return &res, nil
 	}
 
 	// 允许加入
-	err = p.userMgmt.GroupAccept(req.GetGroupId(), sessCtx.Uid, req.GetUid())
+	err = p.userMgmt.GroupAccept(ctx, req.GetGroupId(), sessCtx.Uid, req.GetUid())
 	if err != nil {
 		Log.Error("GroupAccept: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -889,7 +1090,7 @@ func (p *Core) UmGroupAccept(req *gen_grpc.UmGroupAcceptReq) (*gen_grpc.UmGroupA
 	msg.ReceiverId.GroupId = req.GetGroupId()
 	msg.Msg.SenderUid = req.GetUid()
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_GroupUserJoined
-	err = p.chat.SendMsg(msg)
+	err = p.chat.SendMsg(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -900,19 +1101,19 @@ func (p *Core) UmGroupAccept(req *gen_grpc.UmGroupAcceptReq) (*gen_grpc.UmGroupA
 	return &res, nil
 }
 
-func (p *Core) UmGroupReject(req *gen_grpc.UmGroupRejectReq) (*gen_grpc.UmGroupRejectRes, error) {
+func (p *Core) UmGroupReject(ctx context.Context, req *gen_grpc.UmGroupRejectReq) (*gen_grpc.UmGroupRejectRes, error) {
 	var err error
 	var res gen_grpc.UmGroupRejectRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return &res, nil
 	}
 
 	// 拒绝加入请求
-	err = p.userMgmt.GroupReject(req.GetGroupId(), sessCtx.Uid, req.GetUid())
+	err = p.userMgmt.GroupReject(ctx, req.GetGroupId(), sessCtx.Uid, req.GetUid())
 	if err != nil {
 		Log.Error("GroupReject: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -925,7 +1126,7 @@ func (p *Core) UmGroupReject(req *gen_grpc.UmGroupRejectReq) (*gen_grpc.UmGroupR
 	msg.ReceiverId.Uid = req.GetUid()
 	msg.Msg.SenderUid = sessCtx.Uid
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_GroupRejected
-	err = p.chat.SendMsg(msg)
+	err = p.chat.SendMsg(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -936,21 +1137,19 @@ func (p *Core) UmGroupReject(req *gen_grpc.UmGroupRejectReq) (*gen_grpc.UmGroupR
 	return &res, nil
 }
 
-func (p *Core) UmGroupLeave(req *gen_grpc.UmGroupLeaveReq) (*gen_grpc.UmGroupLeaveRes, error) {
+func (p *Core) UmGroupLeave(ctx context.Context, req *gen_grpc.UmGroupLeaveReq) (*gen_grpc.UmGroupLeaveRes, error) {
 	var err error
 	var res gen_grpc.UmGroupLeaveRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 判断是否仍群成员
-	inGroup, err := p.userMgmt.GroupIsMem(req.GetGroupId(), sessCtx.Uid)
+	inGroup, err := p.userMgmt.GroupIsMem(ctx, req.GetGroupId(), sessCtx.Uid)
 	if err != nil {
 		Log.Error("GroupIsMem: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -958,7 +1157,7 @@ func (p *Core) UmGroupLeave(req *gen_grpc.UmGroupLeaveReq) (*gen_grpc.UmGroupLea
 	}
 	if !inGroup {
 		// 仅删除消息
-		err = p.userMgmt.GroupClearMsg(req.GetGroupId(), sessCtx.Uid)
+		err = p.userMgmt.GroupClearMsg(ctx, req.GetGroupId(), sessCtx.Uid)
 		if err != nil {
 			Log.Error("GroupClearMsg: %s", err.Error())
 			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -969,7 +1168,7 @@ func (p *Core) UmGroupLeave(req *gen_grpc.UmGroupLeaveReq) (*gen_grpc.UmGroupLea
 	}
 
 	// 分配群聊消息序列号
-	seqId, err := p.chat.AllocateGroupSeqId(req.GetGroupId())
+	seqId, err := p.chat.AllocateGroupSeqId(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("AllocateGroupSeqId: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -978,7 +1177,7 @@ func (p *Core) UmGroupLeave(req *gen_grpc.UmGroupLeaveReq) (*gen_grpc.UmGroupLea
 
 	// 获取成员列表
 	var memList []uint64
-	memList, err = p.userMgmt.GroupGetMemList(req.GetGroupId())
+	memList, err = p.userMgmt.GroupGetMemList(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("GroupGetMemList: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -986,7 +1185,7 @@ func (p *Core) UmGroupLeave(req *gen_grpc.UmGroupLeaveReq) (*gen_grpc.UmGroupLea
 	}
 
 	// 离开群组
-	err = p.userMgmt.GroupLeave(req.GetGroupId(), sessCtx.Uid)
+	err = p.userMgmt.GroupLeave(ctx, req.GetGroupId(), sessCtx.Uid)
 	if err != nil {
 		Log.Error("GroupLeave: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1004,7 +1203,7 @@ func (p *Core) UmGroupLeave(req *gen_grpc.UmGroupLeaveReq) (*gen_grpc.UmGroupLea
 		msg.Msg.SenderUid = sessCtx.Uid
 		msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_GroupUserLeft
 		msg.ConvMsgId = seqId
-		err = p.chat.SendMsgToUser(uid, msg)
+		err = p.chat.SendMsgToUser(ctx, uid, msg)
 		if err != nil {
 			Log.Error("SendMsg: %s", err.Error())
 		}
@@ -1014,21 +1213,19 @@ func (p *Core) UmGroupLeave(req *gen_grpc.UmGroupLeaveReq) (*gen_grpc.UmGroupLea
 	return &res, nil
 }
 
-func (p *Core) UmGroupAddMem(req *gen_grpc.UmGroupAddMemReq) (*gen_grpc.UmGroupAddMemRes, error) {
+func (p *Core) UmGroupAddMem(ctx context.Context, req *gen_grpc.UmGroupAddMemReq) (*gen_grpc.UmGroupAddMemRes, error) {
 	var err error
 	var res gen_grpc.UmGroupAddMemRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 添加群成员
-	err = p.userMgmt.GroupAddMem(req.GetGroupId(), sessCtx.Uid, req.GetUid())
+	err = p.userMgmt.GroupAddMem(ctx, req.GetGroupId(), sessCtx.Uid, req.GetUid())
 	if err != nil {
 		Log.Error("GroupAddMem: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1041,7 +1238,7 @@ func (p *Core) UmGroupAddMem(req *gen_grpc.UmGroupAddMemReq) (*gen_grpc.UmGroupA
 	msg.ReceiverId.GroupId = req.GetGroupId()
 	msg.Msg.SenderUid = req.GetUid()
 	msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_GroupUserJoined
-	err = p.chat.SendMsg(msg)
+	err = p.chat.SendMsg(ctx, msg)
 	if err != nil {
 		Log.Error("SendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1052,21 +1249,19 @@ func (p *Core) UmGroupAddMem(req *gen_grpc.UmGroupAddMemReq) (*gen_grpc.UmGroupA
 	return &res, nil
 }
 
-func (p *Core) UmGroupDelMem(req *gen_grpc.UmGroupDelMemReq) (*gen_grpc.UmGroupDelMemRes, error) {
+func (p *Core) UmGroupDelMem(ctx context.Context, req *gen_grpc.UmGroupDelMemReq) (*gen_grpc.UmGroupDelMemRes, error) {
 	var err error
 	var res gen_grpc.UmGroupDelMemRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 分配群聊消息序列号
-	seqId, err := p.chat.AllocateGroupSeqId(req.GetGroupId())
+	seqId, err := p.chat.AllocateGroupSeqId(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("AllocateGroupSeqId: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1075,7 +1270,7 @@ func (p *Core) UmGroupDelMem(req *gen_grpc.UmGroupDelMemReq) (*gen_grpc.UmGroupD
 
 	// 获取成员列表
 	var memList []uint64
-	memList, err = p.userMgmt.GroupGetMemList(req.GetGroupId())
+	memList, err = p.userMgmt.GroupGetMemList(ctx, req.GetGroupId())
 	if err != nil {
 		Log.Error("GroupGetMemList: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1083,7 +1278,7 @@ func (p *Core) UmGroupDelMem(req *gen_grpc.UmGroupDelMemReq) (*gen_grpc.UmGroupD
 	}
 
 	// 移除群成员
-	err = p.userMgmt.GroupDelMem(req.GetGroupId(), sessCtx.Uid, req.GetUid())
+	err = p.userMgmt.GroupDelMem(ctx, req.GetGroupId(), sessCtx.Uid, req.GetUid())
 	if err != nil {
 		Log.Error("GroupDelMem: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1098,7 +1293,7 @@ func (p *Core) UmGroupDelMem(req *gen_grpc.UmGroupDelMemReq) (*gen_grpc.UmGroupD
 		msg.Msg.SenderUid = req.GetUid()
 		msg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_GroupUserRemoved
 		msg.ConvMsgId = seqId
-		err = p.chat.SendMsgToUser(uid, msg)
+		err = p.chat.SendMsgToUser(ctx, uid, msg)
 		if err != nil {
 			Log.Error("SendMsg: %s", err.Error())
 		}
@@ -1108,21 +1303,19 @@ func (p *Core) UmGroupDelMem(req *gen_grpc.UmGroupDelMemReq) (*gen_grpc.UmGroupD
 	return &res, nil
 }
 
-func (p *Core) UmGroupUpdateMem(req *gen_grpc.UmGroupUpdateMemReq) (*gen_grpc.UmGroupUpdateMemRes, error) {
+func (p *Core) UmGroupUpdateMem(ctx context.Context, req *gen_grpc.UmGroupUpdateMemReq) (*gen_grpc.UmGroupUpdateMemRes, error) {
 	var err error
 	var res gen_grpc.UmGroupUpdateMemRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 更新群成员
-	err = p.userMgmt.GroupUpdateMem(req.GetGroupId(), sessCtx.Uid, req.GetUid(), uint(req.GetRole()))
+	err = p.userMgmt.GroupUpdateMem(ctx, req.GetGroupId(), sessCtx.Uid, req.GetUid(), uint(req.GetRole()))
 	if err != nil {
 		Log.Error("GroupUpdateMem: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1133,16 +1326,14 @@ func (p *Core) UmGroupUpdateMem(req *gen_grpc.UmGroupUpdateMemReq) (*gen_grpc.Um
 	return &res, nil
 }
 
-func (p *Core) ChatSendMsg(req *gen_grpc.ChatSendMsgReq) (*gen_grpc.ChatSendMsgRes, error) {
+func (p *Core) ChatSendMsg(ctx context.Context, req *gen_grpc.ChatSendMsgReq) (*gen_grpc.ChatSendMsgRes, error) {
 	var err error
 	var res gen_grpc.ChatSendMsgRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
@@ -1150,7 +1341,7 @@ func (p *Core) ChatSendMsg(req *gen_grpc.ChatSendMsgReq) (*gen_grpc.ChatSendMsgR
 	switch x := req.GetConvMsg().GetReceiverId().GetPeerIdUnion().(type) {
 	case *gen_grpc.ChatPeerId_Uid:
 		// 判断是否为好友
-		isMutualContact, _, err := p.userMgmt.ContactGetRelation(sessCtx.Uid, x.Uid)
+		isMutualContact, _, err := p.userMgmt.ContactGetRelation(ctx, sessCtx.Uid, x.Uid)
 		if err != nil {
 			Log.Error("ContactGetRelation: %s", err.Error())
 			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1165,7 +1356,7 @@ func (p *Core) ChatSendMsg(req *gen_grpc.ChatSendMsgReq) (*gen_grpc.ChatSendMsgR
 		convMsg.ReceiverId.Uid = x.Uid
 	case *gen_grpc.ChatPeerId_GroupId:
 		// 判断是否为群成员
-		inGroup, err := p.userMgmt.GroupIsMem(x.GroupId, sessCtx.Uid)
+		inGroup, err := p.userMgmt.GroupIsMem(ctx, x.GroupId, sessCtx.Uid)
 		if err != nil {
 			Log.Error("GroupIsMem: %s", err.Error())
 			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1192,7 +1383,7 @@ func (p *Core) ChatSendMsg(req *gen_grpc.ChatSendMsgReq) (*gen_grpc.ChatSendMsgR
 
 	convMsg.RandMsgId = req.GetConvMsg().GetRandMsgId()
 
-	err = p.chat.SendMsg(convMsg)
+	err = p.chat.SendMsg(ctx, convMsg)
 	if err != nil {
 		Log.Error("ChatSendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1203,16 +1394,14 @@ func (p *Core) ChatSendMsg(req *gen_grpc.ChatSendMsgReq) (*gen_grpc.ChatSendMsgR
 	return &res, nil
 }
 
-func (p *Core) ChatMarkRead(req *gen_grpc.ChatMarkReadReq) (*gen_grpc.ChatMarkReadRes, error) {
+func (p *Core) ChatMarkRead(ctx context.Context, req *gen_grpc.ChatMarkReadReq) (*gen_grpc.ChatMarkReadRes, error) {
 	var err error
 	var res gen_grpc.ChatMarkReadRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
@@ -1235,7 +1424,7 @@ func (p *Core) ChatMarkRead(req *gen_grpc.ChatMarkReadReq) (*gen_grpc.ChatMarkRe
 	convMsg.Msg.MsgType = gen_grpc.ChatMsgType_emChatMsgType_MarkRead
 	convMsg.Msg.ReadMsgId = req.GetReadMsgId()
 
-	err = p.chat.SendMsg(convMsg)
+	err = p.chat.SendMsg(ctx, convMsg)
 	if err != nil {
 		Log.Error("ChatSendMsg: %s", err.Error())
 		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
@@ -1246,29 +1435,37 @@ func (p *Core) ChatMarkRead(req *gen_grpc.ChatMarkReadReq) (*gen_grpc.ChatMarkRe
 	return &res, nil
 }
 
-func (p *Core) GetUpdateList(req *gen_grpc.GetUpdateListReq) (*gen_grpc.GetUpdateListRes, error) {
+func (p *Core) GetUpdateList(ctx context.Context, req *gen_grpc.GetUpdateListReq) (*gen_grpc.GetUpdateListRes, error) {
 	var err error
 	var res gen_grpc.GetUpdateListRes
 
 	// 获取会话
 	var sessCtx *types.SessCtx
-	sessCtx, err = p.sessMgmt.GetSessCtx(types.SessId(req.GetSessId()))
-	if err != nil {
-		Log.Error("GetSessCtx: %s", err.Error())
-		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
 		return &res, nil
 	}
 
 	// 会话续期
-	err = p.sessMgmt.RenewSessCtx(sessCtx, p.sessTimoutS)
+	err = p.sessMgmt.RenewSessCtx(ctx, sessCtx, p.sessTimoutS)
 	if err != nil {
 		Log.Warn("RenewSessCtx: %v", err)
 	}
 
 	// 获取聊天消息
 	var msgList []types.ChatMsgOfConv
-	msgList, err = p.chat.GetChatMsgList(sessCtx.Uid, req.GetLocalSeqId())
+	maxCount := req.GetMaxCount()
+	if maxCount == 0 || maxCount > updateListMaxCount {
+		maxCount = updateListMaxCount
+	}
+	msgList, res.HasMore, err = p.chat.GetChatMsgList(ctx, sessCtx.Uid, req.GetLocalSeqId(), int(maxCount))
 	if err != nil {
+		if ctx.Err() != nil {
+			// 客户端已断开或超过截止时间，响应不会被读取
+			Log.Debug("GetChatMsgList: %s", err.Error())
+			res.ErrCode = gen_grpc.ErrCode_emErrCode_Timeout
+			return &res, nil
+		}
 		Log.Error("ChatGetMsgList: %s", err.Error())
 		if errors.Is(err, proj_err.ErrTimeout) {
 			res.ErrCode = gen_grpc.ErrCode_emErrCode_Timeout
@@ -1278,6 +1475,77 @@ func (p *Core) GetUpdateList(req *gen_grpc.GetUpdateListReq) (*gen_grpc.GetUpdat
 			return &res, nil
 		}
 	}
+	res.MsgList = convertChatMsgListToApi(msgList)
+
+	if len(msgList) > 0 {
+		res.SeqId = msgList[len(msgList)-1].SeqId
+	} else {
+		res.SeqId = req.GetLocalSeqId()
+	}
+
+	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
+	return &res, nil
+}
+
+func (p *Core) ChatGetHistory(ctx context.Context, req *gen_grpc.ChatGetHistoryReq) (*gen_grpc.ChatGetHistoryRes, error) {
+	var err error
+	var res gen_grpc.ChatGetHistoryRes
+
+	// 获取会话
+	var sessCtx *types.SessCtx
+	sessCtx, res.ErrCode = p.getSessCtx(ctx, req.GetSessId())
+	if res.ErrCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return &res, nil
+	}
+
+	var peerId types.PeerId
+	switch x := req.GetPeerId().GetPeerIdUnion().(type) {
+	case *gen_grpc.ChatPeerId_Uid:
+		// 只读取自己收件箱中的消息，不要求仍是好友
+		peerId.PeerIdType = types.EmPeerIdType_Uid
+		peerId.Uid = x.Uid
+	case *gen_grpc.ChatPeerId_GroupId:
+		// 判断是否为群成员
+		inGroup, err := p.userMgmt.GroupIsMem(ctx, x.GroupId, sessCtx.Uid)
+		if err != nil {
+			Log.Error("GroupIsMem: %s", err.Error())
+			res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+			return &res, nil
+		}
+		if !inGroup {
+			Log.Error("Not in group")
+			res.ErrCode = gen_grpc.ErrCode_emErrCode_UserNotInGroup
+			return &res, nil
+		}
+		peerId.PeerIdType = types.EmPeerIdType_GroupId
+		peerId.GroupId = x.GroupId
+	default:
+		Log.Error("Unknown PeerId type")
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+		return &res, nil
+	}
+
+	limit := req.GetLimit()
+	if limit == 0 {
+		limit = chatHistoryDefaultLimit
+	} else if limit > chatHistoryMaxLimit {
+		limit = chatHistoryMaxLimit
+	}
+
+	var msgList []types.ChatMsgOfConv
+	msgList, res.HasMore, err = p.chat.GetHistory(ctx, sessCtx.Uid, peerId, req.GetBeforeSeqId(), int(limit))
+	if err != nil {
+		Log.Error("GetHistory: %s", err.Error())
+		res.ErrCode = gen_grpc.ErrCode_emErrCode_UnknownErr
+		return &res, nil
+	}
+	res.MsgList = convertChatMsgListToApi(msgList)
+
+	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
+	return &res, nil
+}
+
+func convertChatMsgListToApi(msgList []types.ChatMsgOfConv) (apiMsgList []*gen_grpc.ChatConvMsg) {
 	for _, aConvMsg := range msgList {
 		aBoxMsgApi := &gen_grpc.ChatConvMsg{
 			SeqId: aConvMsg.SeqId,
@@ -1298,15 +1566,75 @@ func (p *Core) GetUpdateList(req *gen_grpc.GetUpdateListReq) (*gen_grpc.GetUpdat
 		aBoxMsgApi.Msg.SenderUid = aConvMsg.Msg.SenderUid
 		aBoxMsgApi.Msg.MsgContent = aConvMsg.Msg.MsgContent
 		aBoxMsgApi.Msg.ReadMsgId = aConvMsg.Msg.ReadMsgId
-		res.MsgList = append(res.MsgList, aBoxMsgApi)
+		apiMsgList = append(apiMsgList, aBoxMsgApi)
 	}
+	return apiMsgList
+}
 
-	if len(msgList) > 0 {
-		res.SeqId = msgList[len(msgList)-1].SeqId
-	} else {
-		res.SeqId = req.GetLocalSeqId()
+// Subscribe 推送新消息及心跳，直到客户端断开。send 由具体协议实现
+func (p *Core) Subscribe(ctx context.Context, req *gen_grpc.SubscribeReq, send func(*gen_grpc.SubscribeRes) error) error {
+	var err error
+
+	// 获取会话
+	var sessCtx *types.SessCtx
+	var errCode gen_grpc.ErrCode
+	sessCtx, errCode = p.getSessCtx(ctx, req.GetSessId())
+	if errCode != gen_grpc.ErrCode_emErrCode_Ok {
+		return send(&gen_grpc.SubscribeRes{ErrCode: errCode})
+	}
+
+	// 心跳间隔
+	heartbeatInterval := p.subscribeHeartbeatS
+	if req.GetHeartbeatIntervalS() != 0 {
+		heartbeatInterval = uint64(req.GetHeartbeatIntervalS())
+		if heartbeatInterval < subscribeMinHeartbeatS {
+			heartbeatInterval = subscribeMinHeartbeatS
+		} else if heartbeatInterval > subscribeMaxHeartbeatS {
+			heartbeatInterval = subscribeMaxHeartbeatS
+		}
 	}
 
-	res.ErrCode = gen_grpc.ErrCode_emErrCode_Ok
-	return &res, nil
+	onMsgList := func(msgList []types.ChatMsgOfConv, hasMore bool) error {
+		return send(&gen_grpc.SubscribeRes{
+			ErrCode: gen_grpc.ErrCode_emErrCode_Ok,
+			SeqId:   msgList[len(msgList)-1].SeqId,
+			MsgList: convertChatMsgListToApi(msgList),
+			HasMore: hasMore,
+		})
+	}
+
+	onHeartbeat := func(seqId uint64) error {
+		// 会话已注销则结束订阅，否则续期
+		err := p.sessMgmt.CheckSessCtx(ctx, sessCtx)
+		if err != nil {
+			sendErr := send(&gen_grpc.SubscribeRes{ErrCode: sessErrCode(err)})
+			if sendErr != nil {
+				return sendErr
+			}
+			return err
+		}
+		err = p.sessMgmt.RenewSessCtx(ctx, sessCtx, p.sessTimoutS)
+		if err != nil {
+			Log.Warn("RenewSessCtx: %v", err)
+		}
+		return send(&gen_grpc.SubscribeRes{
+			ErrCode:     gen_grpc.ErrCode_emErrCode_Ok,
+			SeqId:       seqId,
+			IsHeartbeat: true,
+		})
+	}
+
+	// 会话续期
+	err = p.sessMgmt.RenewSessCtx(ctx, sessCtx, p.sessTimoutS)
+	if err != nil {
+		Log.Warn("RenewSessCtx: %v", err)
+	}
+
+	err = p.chat.SubscribeMsgList(ctx, sessCtx.Uid, req.GetLocalSeqId(), int(updateListMaxCount),
+		time.Duration(heartbeatInterval)*time.Second, onMsgList, onHeartbeat)
+	if err != nil {
+		Log.Error("SubscribeMsgList: %s", err.Error())
+		return err
+	}
+	return nil
 }
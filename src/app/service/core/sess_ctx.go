@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"social_server/src/app/common/proj_err"
+	"social_server/src/app/common/types"
+	"social_server/src/gen/grpc"
+	. "social_server/src/utils/log"
+)
+
+type sessCtxKey struct{}
+
+type clientIpKey struct{}
+
+// WithClientIp 记录请求来源 IP，登录时保存到会话中
+func WithClientIp(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIpKey{}, ip)
+}
+
+func clientIpFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIpKey{}).(string)
+	return ip
+}
+
+// WithSessCtx 把已校验的会话放入 ctx，Core 方法优先使用它，不再按请求中的 sessId 查询
+func WithSessCtx(ctx context.Context, sessCtx *types.SessCtx) context.Context {
+	return context.WithValue(ctx, sessCtxKey{}, sessCtx)
+}
+
+// SessCtxFromContext 取出 WithSessCtx 放入的会话
+func SessCtxFromContext(ctx context.Context) (*types.SessCtx, bool) {
+	sessCtx, ok := ctx.Value(sessCtxKey{}).(*types.SessCtx)
+	return sessCtx, ok && sessCtx != nil
+}
+
+// ResolveSess 按 sessId 查询会话，启用访问令牌时 sessId 为访问令牌，只校验签名不查询缓存。
+// 会话不存在或已过期时返回 SessNotExisted，让客户端能区分已登出和服务端故障
+func (p *Core) ResolveSess(ctx context.Context, sessId string) (*types.SessCtx, gen_grpc.ErrCode) {
+	if sessId == "" {
+		return nil, gen_grpc.ErrCode_emErrCode_SessNotExisted
+	}
+	if p.sessMgmt.TokenMode() {
+		sessCtx, err := p.sessMgmt.VerifyAccessToken(sessId)
+		if err != nil {
+			return nil, sessErrCode(err)
+		}
+		return sessCtx, gen_grpc.ErrCode_emErrCode_Ok
+	}
+	return p.ResolveRefreshToken(ctx, sessId)
+}
+
+// ResolveRefreshToken 按会话 id 查询会话。启用访问令牌时会话 id 作为刷新令牌，只用于 SessRefresh
+func (p *Core) ResolveRefreshToken(ctx context.Context, sessId string) (*types.SessCtx, gen_grpc.ErrCode) {
+	if sessId == "" {
+		return nil, gen_grpc.ErrCode_emErrCode_SessNotExisted
+	}
+	sessCtx, err := p.sessMgmt.GetSessCtx(ctx, types.SessId(sessId))
+	if err != nil {
+		return nil, sessErrCode(err)
+	}
+	return sessCtx, gen_grpc.ErrCode_emErrCode_Ok
+}
+
+// TokenJwks 校验访问令牌用的公钥，未启用访问令牌时 ok 为 false
+func (p *Core) TokenJwks() (jwks []byte, ok bool, err error) {
+	if !p.sessMgmt.TokenMode() {
+		return nil, false, nil
+	}
+	jwks, err = p.sessMgmt.TokenJwks()
+	return jwks, true, err
+}
+
+// RevokedTokenSess 访问令牌的吊销列表，未启用访问令牌时 ok 为 false
+func (p *Core) RevokedTokenSess() (revoked map[string]uint64, ok bool) {
+	if !p.sessMgmt.TokenMode() {
+		return nil, false
+	}
+	return p.sessMgmt.RevokedTokenSess(), true
+}
+
+// getSessCtx 取得请求的会话。经过 gRPC 拦截器的请求直接使用 ctx 中的会话，
+// WebSocket、REST 等直接调用 Core 的请求按 sessId 查询
+func (p *Core) getSessCtx(ctx context.Context, sessId string) (*types.SessCtx, gen_grpc.ErrCode) {
+	if sessCtx, ok := SessCtxFromContext(ctx); ok {
+		return sessCtx, gen_grpc.ErrCode_emErrCode_Ok
+	}
+	return p.ResolveSess(ctx, sessId)
+}
+
+func sessErrCode(err error) gen_grpc.ErrCode {
+	if errors.Is(err, proj_err.ErrSessNotExisted) {
+		Log.Warn("GetSessCtx: %s", err.Error())
+		return gen_grpc.ErrCode_emErrCode_SessNotExisted
+	}
+	Log.Error("GetSessCtx: %s", err.Error())
+	return gen_grpc.ErrCode_emErrCode_UnknownErr
+}
+
+// truncateRunes 截断到最多 n 个字符
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"social_server/src/app/common/types"
+	"social_server/src/gen/grpc"
+	. "social_server/src/utils/log"
+	"sync"
+	"testing"
+)
+
+var setupLoggerOnce sync.Once
+
+// TestGetUpdateListPages 按 seqId 分页拉取，不重复不遗漏，最后一页 hasMore 为 false，
+// maxCount 为 0 或超过上限时按上限返回
+func TestGetUpdateListPages(t *testing.T) {
+	setupLoggerOnce.Do(SetupLogger)
+	t.Setenv("STORAGE_BACKEND", "memory")
+	t.Setenv("CACHE_BACKEND", "memory")
+	p := NewCore()
+	ctx := context.Background()
+
+	for _, username := range []string{"alice", "bob"} {
+		res, err := p.UmRegister(ctx, &gen_grpc.UmRegisterReq{Username: username, Password: "password123", Email: username + "@example.com"})
+		if err != nil || res.GetErrCode() != gen_grpc.ErrCode_emErrCode_Ok {
+			t.Fatalf("UmRegister: %v %v", res.GetErrCode(), err)
+		}
+	}
+	login := func(username string) *gen_grpc.SessUserLoginRes {
+		t.Helper()
+		res, err := p.SessUserLogin(ctx, &gen_grpc.SessUserLoginReq{Username: username, Password: "password123"})
+		if err != nil || res.GetErrCode() != gen_grpc.ErrCode_emErrCode_Ok {
+			t.Fatalf("SessUserLogin: %v %v", res.GetErrCode(), err)
+		}
+		return res
+	}
+	alice, bob := login("alice"), login("bob")
+
+	// 超过两页上限的消息
+	total := 2*int(updateListMaxCount) + 3
+	for i := 0; i < total; i++ {
+		err := p.chat.SendMsg(ctx, types.ChatMsgOfConv{
+			ReceiverId: types.PeerId{PeerIdType: types.EmPeerIdType_Uid, Uid: alice.GetUid()},
+			Msg:        types.ChatMsg{SenderUid: bob.GetUid(), MsgType: gen_grpc.ChatMsgType_emChatMsgType_Text, MsgContent: fmt.Sprint(i)},
+		})
+		if err != nil {
+			t.Fatalf("SendMsg: %v", err)
+		}
+	}
+
+	tests := []struct {
+		maxCount uint32
+		pageSize int
+	}{
+		{7, 7},
+		{updateListMaxCount, int(updateListMaxCount)},
+		{0, int(updateListMaxCount)},
+		{updateListMaxCount + 1, int(updateListMaxCount)},
+		{1 << 31, int(updateListMaxCount)},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.maxCount), func(t *testing.T) {
+			var localSeqId uint64
+			var got int
+			for page := 0; ; page++ {
+				res, err := p.GetUpdateList(ctx, &gen_grpc.GetUpdateListReq{SessId: alice.GetSessId(), LocalSeqId: localSeqId, MaxCount: tt.maxCount})
+				if err != nil || res.GetErrCode() != gen_grpc.ErrCode_emErrCode_Ok {
+					t.Fatalf("page %d: GetUpdateList: %v %v", page, res.GetErrCode(), err)
+				}
+				msgList := res.GetMsgList()
+				wantCount := total - got
+				if wantCount > tt.pageSize {
+					wantCount = tt.pageSize
+				}
+				if len(msgList) != wantCount {
+					t.Fatalf("page %d: got %d messages, want %d", page, len(msgList), wantCount)
+				}
+				for _, msg := range msgList {
+					if msg.GetSeqId() <= localSeqId {
+						t.Fatalf("page %d: seqId %d not after %d", page, msg.GetSeqId(), localSeqId)
+					}
+					if content := msg.GetMsg().GetMsgContent(); content != fmt.Sprint(got) {
+						t.Fatalf("page %d: got message %s, want %d", page, content, got)
+					}
+					localSeqId = msg.GetSeqId()
+					got++
+				}
+				if res.GetSeqId() != localSeqId {
+					t.Fatalf("page %d: next seqId %d, want the last message's %d", page, res.GetSeqId(), localSeqId)
+				}
+				if res.GetHasMore() != (got < total) {
+					t.Fatalf("page %d: hasMore %v after %d of %d messages", page, res.GetHasMore(), got, total)
+				}
+				if !res.GetHasMore() {
+					break
+				}
+			}
+			if got != total {
+				t.Fatalf("got %d messages, want %d", got, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package sess_mgmt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"social_server/src/app/data"
+	. "social_server/src/utils/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 登录防暴力破解：
+// 按用户名和 IP 分别统计最近 LOGIN_FAIL_WINDOW_S 秒内的登录失败次数。
+// 失败次数达到 LOGIN_FAIL_DELAY_AFTER 后，每次登录前先等待，等待时间随失败次数翻倍；
+// 达到 LOGIN_USER_MAX_FAILS 或 LOGIN_IP_MAX_FAILS 后锁定 LOGIN_LOCKOUT_S 秒，期间拒绝登录。
+// 部署在负载均衡或反向代理之后时，只有配置了 LOGIN_TRUSTED_PROXIES 才能取到客户端 IP，
+// 否则所有请求都是代理的 IP，少量错误密码就会锁定所有人的登录，因此 LOGIN_IP_MAX_FAILS 默认为 0
+
+const (
+	loginDelayBase = 500 * time.Millisecond
+	loginDelayMax  = 8 * time.Second
+)
+
+type LoginGuard struct {
+	cache        data.CacheStorage
+	windowS      uint64
+	lockoutS     uint64
+	delayAfter   uint64 // 为 0 时不等待
+	userMaxFails uint64 // 为 0 时不按用户名锁定
+	ipMaxFails   uint64 // 为 0 时不按 IP 锁定
+}
+
+func NewLoginGuard(cache data.CacheStorage) (*LoginGuard, error) {
+	p := &LoginGuard{cache: cache}
+	var err error
+	p.windowS, err = envSecs("LOGIN_FAIL_WINDOW_S", 15*60)
+	if err != nil {
+		return nil, err
+	}
+	p.lockoutS, err = envSecs("LOGIN_LOCKOUT_S", 15*60)
+	if err != nil {
+		return nil, err
+	}
+	p.delayAfter, err = envCount("LOGIN_FAIL_DELAY_AFTER", 3)
+	if err != nil {
+		return nil, err
+	}
+	p.userMaxFails, err = envCount("LOGIN_USER_MAX_FAILS", 10)
+	if err != nil {
+		return nil, err
+	}
+	p.ipMaxFails, err = envCount("LOGIN_IP_MAX_FAILS", 0)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// envCount 读取可以为 0 的次数
+func envCount(name string, def uint64) (uint64, error) {
+	s := os.Getenv(name)
+	if s == "" {
+		return def, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value: %s", name, s)
+	}
+	return v, nil
+}
+
+// LoginUserKey 用户名的计数 key。用户名查询不区分大小写，key 也统一为小写，
+// 否则换用大小写不同的写法就能得到新的计数
+func LoginUserKey(username string) string {
+	return "user:" + strings.ToLower(username)
+}
+
+// LoginIpKey IP 的计数 key
+func LoginIpKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginKeys 登录请求对应的 key 及其锁定阈值，IP 未知时只按用户名统计
+func (p *LoginGuard) loginKeys(username string, ip string) (keys []string, maxFails []uint64) {
+	keys = append(keys, LoginUserKey(username))
+	maxFails = append(maxFails, p.userMaxFails)
+	if ip != "" {
+		keys = append(keys, LoginIpKey(ip))
+		maxFails = append(maxFails, p.ipMaxFails)
+	}
+	return keys, maxFails
+}
+
+// loginDelay 失败 count 次后下次登录前的等待时间
+func (p *LoginGuard) loginDelay(count uint64) time.Duration {
+	if p.delayAfter == 0 || count < p.delayAfter {
+		return 0
+	}
+	delay := loginDelayBase
+	for i := p.delayAfter; i < count && delay < loginDelayMax; i++ {
+		delay *= 2
+	}
+	if delay > loginDelayMax {
+		delay = loginDelayMax
+	}
+	return delay
+}
+
+// Check 登录前检查。用户名或 IP 被锁定时返回剩余的锁定秒数；
+// 未锁定但失败次数较多时先等待，等待期间请求取消则返回 ctx 的错误
+func (p *LoginGuard) Check(ctx context.Context, username string, ip string) (lockedS uint64, err error) {
+	keys, _ := p.loginKeys(username, ip)
+	var maxCount uint64
+	for _, key := range keys {
+		ttlS, err := p.cache.GetLoginLockTtl(ctx, key)
+		if err != nil {
+			return 0, fmt.Errorf("cache.GetLoginLockTtl: %w", err)
+		}
+		if ttlS > 0 {
+			return ttlS, nil
+		}
+		if p.delayAfter == 0 {
+			continue
+		}
+		count, err := p.cache.GetLoginFailureCount(ctx, key, p.windowS)
+		if err != nil {
+			return 0, fmt.Errorf("cache.GetLoginFailureCount: %w", err)
+		}
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	delay := p.loginDelay(maxCount)
+	if delay == 0 {
+		return 0, nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return 0, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// Fail 记录一次登录失败，失败次数达到阈值时锁定并记录日志。锁定时返回锁定秒数
+func (p *LoginGuard) Fail(ctx context.Context, username string, ip string) (lockedS uint64, err error) {
+	keys, maxFails := p.loginKeys(username, ip)
+	for i, key := range keys {
+		count, err := p.cache.AddLoginFailure(ctx, key, p.windowS)
+		if err != nil {
+			return 0, fmt.Errorf("cache.AddLoginFailure: %w", err)
+		}
+		if maxFails[i] == 0 || count < maxFails[i] {
+			continue
+		}
+		err = p.cache.LockLogin(ctx, key, p.lockoutS)
+		if err != nil {
+			return 0, fmt.Errorf("cache.LockLogin: %w", err)
+		}
+		Log.Warn("login locked: %s, %d failures in %ds, username: %s, ip: %s, lockout: %ds",
+			key, count, p.windowS, username, ip, p.lockoutS)
+		lockedS = p.lockoutS
+	}
+	return lockedS, nil
+}
+
+// Succeed 登录成功后清除用户名的失败记录。IP 的失败记录保留，避免撞库时夹杂自己的账号来重置计数
+func (p *LoginGuard) Succeed(ctx context.Context, username string) error {
+	_, err := p.cache.ClearLoginFailures(ctx, LoginUserKey(username))
+	if err != nil {
+		return fmt.Errorf("cache.ClearLoginFailures: %w", err)
+	}
+	return nil
+}
+
+// Unlock 管理员解除锁定并清除失败记录，key 由 LoginUserKey 或 LoginIpKey 得到
+func (p *LoginGuard) Unlock(ctx context.Context, key string) (wasLocked bool, err error) {
+	wasLocked, err = p.cache.ClearLoginFailures(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("cache.ClearLoginFailures: %w", err)
+	}
+	Log.Warn("login unlocked: %s, was locked: %v", key, wasLocked)
+	return wasLocked, nil
+}
@@ -0,0 +1,142 @@
+package sess_mgmt
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"social_server/src/app/data"
+	. "social_server/src/utils/log"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+var setupLoggerOnce sync.Once
+
+// testLoginCaches 分别用两种缓存测试
+var testLoginCaches = []struct {
+	name     string
+	newCache func(t *testing.T) data.CacheStorage
+}{
+	{"memory", func(t *testing.T) data.CacheStorage {
+		return data.NewMemCache()
+	}},
+	{"redis", func(t *testing.T) data.CacheStorage {
+		mr := miniredis.RunT(t)
+		t.Setenv("REDIS_HOST", mr.Host())
+		t.Setenv("REDIS_PORT", mr.Port())
+		t.Setenv("REDIS_PASSWORD", "")
+		t.Setenv("REDIS_DB", "")
+		return data.NewCache()
+	}},
+}
+
+func TestLoginGuard(t *testing.T) {
+	setupLoggerOnce.Do(SetupLogger)
+	t.Setenv("LOGIN_FAIL_DELAY_AFTER", "0")
+	t.Setenv("LOGIN_USER_MAX_FAILS", "3")
+	t.Setenv("LOGIN_IP_MAX_FAILS", "5")
+
+	for _, tc := range testLoginCaches {
+		t.Run(tc.name, func(t *testing.T) {
+			guard, err := NewLoginGuard(tc.newCache(t))
+			if err != nil {
+				t.Fatalf("NewLoginGuard: %v", err)
+			}
+			ctx := context.Background()
+
+			checkLocked := func(username string, ip string, want bool) {
+				t.Helper()
+				lockedS, err := guard.Check(ctx, username, ip)
+				if err != nil {
+					t.Fatalf("Check: %v", err)
+				}
+				if (lockedS > 0) != want {
+					t.Fatalf("Check(%s, %s): locked for %ds, want locked %v", username, ip, lockedS, want)
+				}
+			}
+
+			// 登录成功清除用户名的失败次数
+			for i := 0; i < 2; i++ {
+				guard.Fail(ctx, "alice", "10.0.0.1")
+			}
+			guard.Succeed(ctx, "alice")
+			for i := 0; i < 2; i++ {
+				lockedS, err := guard.Fail(ctx, "alice", "10.0.0.2")
+				if err != nil || lockedS != 0 {
+					t.Fatalf("Fail %d: %d %v", i, lockedS, err)
+				}
+			}
+			checkLocked("alice", "10.0.0.2", false)
+
+			// 达到阈值后锁定用户名，其他用户不受影响
+			lockedS, err := guard.Fail(ctx, "alice", "10.0.0.3")
+			if err != nil || lockedS != guard.lockoutS {
+				t.Fatalf("Fail: %d %v, want locked for %ds", lockedS, err, guard.lockoutS)
+			}
+			checkLocked("alice", "10.0.0.4", true)
+			checkLocked("bob", "10.0.0.4", false)
+
+			wasLocked, err := guard.Unlock(ctx, LoginUserKey("alice"))
+			if err != nil || !wasLocked {
+				t.Fatalf("Unlock: %v %v", wasLocked, err)
+			}
+			checkLocked("alice", "10.0.0.4", false)
+
+			// 大小写不同的用户名共用计数
+			for i, username := range []string{"Carol", "CAROL", "carol"} {
+				lockedS, err := guard.Fail(ctx, username, "10.0.1."+strconv.Itoa(i))
+				if err != nil {
+					t.Fatalf("Fail: %v", err)
+				}
+				if want := i == 2; (lockedS > 0) != want {
+					t.Fatalf("Fail(%s): locked for %ds, want locked %v", username, lockedS, want)
+				}
+			}
+			checkLocked("cArOl", "10.0.1.9", true)
+			guard.Unlock(ctx, LoginUserKey("CAROL"))
+			checkLocked("carol", "10.0.1.9", false)
+
+			// 登录成功时清除同一个计数
+			guard.Fail(ctx, "Dave", "10.0.2.1")
+			guard.Fail(ctx, "DAVE", "10.0.2.1")
+			guard.Succeed(ctx, "dave")
+			lockedS, err = guard.Fail(ctx, "dave", "10.0.2.1")
+			if err != nil || lockedS != 0 {
+				t.Fatalf("Fail after Succeed: %d %v", lockedS, err)
+			}
+
+			// 同一 IP 尝试多个用户名时锁定 IP
+			for _, username := range []string{"u1", "u2", "u3", "u4", "u5"} {
+				guard.Fail(ctx, username, "10.0.0.9")
+			}
+			checkLocked("u6", "10.0.0.9", true)
+			checkLocked("u6", "10.0.0.10", false)
+			wasLocked, err = guard.Unlock(ctx, LoginIpKey("10.0.0.9"))
+			if err != nil || !wasLocked {
+				t.Fatalf("Unlock: %v %v", wasLocked, err)
+			}
+			checkLocked("u6", "10.0.0.9", false)
+		})
+	}
+}
+
+func TestLoginDelay(t *testing.T) {
+	guard := &LoginGuard{delayAfter: 3}
+	tests := []struct {
+		count uint64
+		want  time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, loginDelayBase},
+		{4, 2 * loginDelayBase},
+		{6, 8 * loginDelayBase},
+		{100, loginDelayMax},
+	}
+	for _, tt := range tests {
+		if got := guard.loginDelay(tt.count); got != tt.want {
+			t.Errorf("loginDelay(%d) = %v, want %v", tt.count, got, tt.want)
+		}
+	}
+}
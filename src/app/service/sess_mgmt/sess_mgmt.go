@@ -1,47 +1,212 @@
 package sess_mgmt
 
 import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
     "fmt"
+    "social_server/src/app/common/proj_err"
     "social_server/src/app/common/types"
     "social_server/src/app/data"
+    . "social_server/src/utils/log"
+    "sort"
 )
 
 type SessMgmt struct {
-    storage *data.DB
-    cache   *data.Cache
+    storage data.Storage
+    cache   data.CacheStorage
+    // tokens 未启用访问令牌时为 nil
+    tokens *tokenMgmt
 }
 
-func NewSessMgmt(storage *data.DB, cache *data.Cache) *SessMgmt {
+func NewSessMgmt(storage data.Storage, cache data.CacheStorage) (*SessMgmt, error) {
+    tokens, err := newTokenMgmt(cache)
+    if err != nil {
+        return nil, err
+    }
     return &SessMgmt{
         storage: storage,
         cache:   cache,
-    }
+        tokens:  tokens,
+    }, nil
 }
 
-func (p *SessMgmt) CreateSess(username string, uid uint64, expireAfterSecs uint64) (sessId types.SessId, err error) {
-    sessId, err = p.cache.CreateSess(username, uid, expireAfterSecs)
+func (p *SessMgmt) CreateSess(ctx context.Context, username string, uid uint64, device types.SessDevice, expireAfterSecs uint64) (sessId types.SessId, err error) {
+    sessId, err = p.cache.CreateSess(ctx, username, uid, device, expireAfterSecs)
     if err != nil {
         return "", fmt.Errorf("cache.CreateSess: %w", err)
     }
     return sessId, nil
 }
 
-func (p *SessMgmt) GetSessCtx(sessId types.SessId) (sessCtx *types.SessCtx, err error) {
-    return p.cache.GetSessCtx(sessId)
+func (p *SessMgmt) GetSessCtx(ctx context.Context, sessId types.SessId) (sessCtx *types.SessCtx, err error) {
+    sessCtx, err = p.cache.GetSessCtx(ctx, sessId)
+    if err != nil {
+        return nil, err
+    }
+    sessCtx.SessKey = SessKey(sessId)
+    return sessCtx, nil
 }
 
-func (p *SessMgmt) GetSessCtxByUid(uid uint64) (sessCtx *types.SessCtx, err error) {
-    return p.cache.GetSessCtxByUid(uid)
+func (p *SessMgmt) GetSessCtxByUid(ctx context.Context, uid uint64) (sessCtx *types.SessCtx, err error) {
+    return p.cache.GetSessCtxByUid(ctx, uid)
 }
 
-func (p *SessMgmt) RenewSessCtx(sessCtx *types.SessCtx, expireAfterSecs uint64) error {
-    return p.cache.RenewSessCtx(sessCtx, expireAfterSecs)
+// RenewSessCtx 会话续期。由访问令牌认证时不续期，会话在刷新访问令牌时续期
+func (p *SessMgmt) RenewSessCtx(ctx context.Context, sessCtx *types.SessCtx, expireAfterSecs uint64) error {
+    if sessCtx.ViaToken {
+        return nil
+    }
+    return p.cache.RenewSessCtx(ctx, sessCtx, expireAfterSecs)
 }
 
-func (p *SessMgmt) DeleteSess(sessId types.SessId) (err error) {
-    return p.cache.DeleteSess(sessId)
+// CheckSessCtx 检查会话是否仍然有效，用于长连接定期检查。无效时返回 proj_err.ErrSessNotExisted
+func (p *SessMgmt) CheckSessCtx(ctx context.Context, sessCtx *types.SessCtx) error {
+    if sessCtx.ViaToken {
+        return p.tokens.check(sessCtx)
+    }
+    _, err := p.cache.GetSessCtx(ctx, sessCtx.SessId)
+    return err
 }
 
-func (p *SessMgmt) DeleteUserSess(uid uint64) (err error) {
-    return p.cache.DeleteUserSess(uid)
+// DeleteSessCtx 下线会话，由访问令牌认证的会话按 sessKey 查找
+func (p *SessMgmt) DeleteSessCtx(ctx context.Context, sessCtx *types.SessCtx) (err error) {
+    if sessCtx.ViaToken {
+        _, err = p.DeleteUserSessByKey(ctx, sessCtx.Uid, sessCtx.SessKey)
+        return err
+    }
+    err = p.cache.DeleteSess(ctx, sessCtx.SessId)
+    if err != nil {
+        return err
+    }
+    p.revokeTokens(ctx, []string{SessKey(sessCtx.SessId)})
+    return nil
+}
+
+func (p *SessMgmt) DeleteUserSess(ctx context.Context, uid uint64) (err error) {
+    var sessKeys []string
+    if p.tokens != nil {
+        sessList, err := p.cache.GetUserSessList(ctx, uid)
+        if err != nil {
+            return fmt.Errorf("cache.GetUserSessList: %w", err)
+        }
+        for _, sessCtx := range sessList {
+            sessKeys = append(sessKeys, SessKey(sessCtx.SessId))
+        }
+    }
+    err = p.cache.DeleteUserSess(ctx, uid)
+    if err != nil {
+        return err
+    }
+    p.revokeTokens(ctx, sessKeys)
+    return nil
+}
+
+// SessKey 会话对外的标识，用于列出和下线其他设备的会话。由 sessId 哈希得到，不能当作 sessId 使用
+func SessKey(sessId types.SessId) string {
+    hash := sha256.Sum256([]byte(sessId))
+    return hex.EncodeToString(hash[:16])
+}
+
+// GetUserSessList 按创建时间排列用户的所有会话
+func (p *SessMgmt) GetUserSessList(ctx context.Context, uid uint64) (sessList []*types.SessCtx, err error) {
+    sessList, err = p.cache.GetUserSessList(ctx, uid)
+    if err != nil {
+        return nil, fmt.Errorf("cache.GetUserSessList: %w", err)
+    }
+    for _, sessCtx := range sessList {
+        sessCtx.SessKey = SessKey(sessCtx.SessId)
+    }
+    sort.Slice(sessList, func(i, j int) bool {
+        return sessList[i].CreatedAt < sessList[j].CreatedAt
+    })
+    return sessList, nil
+}
+
+// DeleteUserSessByKey 下线用户的一个会话，会话已不存在时返回 false
+func (p *SessMgmt) DeleteUserSessByKey(ctx context.Context, uid uint64, sessKey string) (deleted bool, err error) {
+    sessList, err := p.cache.GetUserSessList(ctx, uid)
+    if err != nil {
+        return false, fmt.Errorf("cache.GetUserSessList: %w", err)
+    }
+    for _, sessCtx := range sessList {
+        if SessKey(sessCtx.SessId) != sessKey {
+            continue
+        }
+        err = p.cache.DeleteSess(ctx, sessCtx.SessId)
+        if errors.Is(err, proj_err.ErrSessNotExisted) {
+            return false, nil
+        }
+        if err != nil {
+            return false, fmt.Errorf("cache.DeleteSess: %w", err)
+        }
+        p.revokeTokens(ctx, []string{sessKey})
+        return true, nil
+    }
+    return false, nil
+}
+
+// DeleteOtherUserSess 下线用户除 keepSessKey 以外的所有会话
+func (p *SessMgmt) DeleteOtherUserSess(ctx context.Context, uid uint64, keepSessKey string) error {
+    sessList, err := p.cache.GetUserSessList(ctx, uid)
+    if err != nil {
+        return fmt.Errorf("cache.GetUserSessList: %w", err)
+    }
+    var sessKeys []string
+    for _, sessCtx := range sessList {
+        sessKey := SessKey(sessCtx.SessId)
+        if sessKey == keepSessKey {
+            continue
+        }
+        err = p.cache.DeleteSess(ctx, sessCtx.SessId)
+        if err != nil && !errors.Is(err, proj_err.ErrSessNotExisted) {
+            return fmt.Errorf("cache.DeleteSess: %w", err)
+        }
+        sessKeys = append(sessKeys, sessKey)
+    }
+    p.revokeTokens(ctx, sessKeys)
+    return nil
+}
+
+// TokenMode 是否启用了访问令牌
+func (p *SessMgmt) TokenMode() bool {
+    return p.tokens != nil
+}
+
+// RefreshTokenTtlS 刷新令牌即会话的有效期，每次刷新时续期
+func (p *SessMgmt) RefreshTokenTtlS() uint64 {
+    return p.tokens.refreshTtlS
+}
+
+// IssueAccessToken 为会话签发访问令牌，expiresAt 为 Unix 秒
+func (p *SessMgmt) IssueAccessToken(sessCtx *types.SessCtx) (token string, expiresAt uint64, err error) {
+    return p.tokens.issue(sessCtx)
+}
+
+// VerifyAccessToken 校验访问令牌，不查询缓存。无效、过期或已吊销时返回 proj_err.ErrSessNotExisted
+func (p *SessMgmt) VerifyAccessToken(token string) (*types.SessCtx, error) {
+    return p.tokens.verify(token)
+}
+
+// TokenJwks 校验访问令牌用的公钥，JWK Set 格式
+func (p *SessMgmt) TokenJwks() ([]byte, error) {
+    return p.tokens.jwks()
+}
+
+// RevokedTokenSess 当前的吊销列表，sessKey 对应记录到期的 Unix 秒
+func (p *SessMgmt) RevokedTokenSess() map[string]uint64 {
+    return p.tokens.revokedList()
+}
+
+// revokeTokens 会话下线后吊销其访问令牌。
+// 写入缓存失败时只有本节点知道吊销，其他节点上已签发的令牌在过期前仍然有效，只记录日志
+func (p *SessMgmt) revokeTokens(ctx context.Context, sessKeys []string) {
+    if p.tokens == nil {
+        return
+    }
+    err := p.tokens.revoke(ctx, sessKeys)
+    if err != nil {
+        Log.Error("revoke tokens: %v", err)
+    }
 }
@@ -0,0 +1,320 @@
+package sess_mgmt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"os"
+	"social_server/src/app/common/proj_err"
+	"social_server/src/app/common/types"
+	"social_server/src/app/data"
+	. "social_server/src/utils/log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 访问令牌模式（AUTH_TOKEN_MODE=jwt）：
+// 登录时签发 Ed25519 签名的 JWT（alg 为 EdDSA），携带 uid、用户名和 sessKey，校验只需公钥，不查询缓存。
+// 会话本身作为刷新令牌保存在缓存中。会话下线时把 sessKey 加入吊销列表，保留到已签发的令牌全部过期，
+// 各节点定期把吊销列表同步到内存
+
+const (
+	AuthTokenModeSession = "session"
+	AuthTokenModeJwt     = "jwt"
+)
+
+const (
+	tokenIssuer                 = "social_server"
+	tokenRevocationSyncInterval = 5 * time.Second
+)
+
+type tokenKey struct {
+	kid        string
+	privateKey ed25519.PrivateKey
+}
+
+type accessTokenClaims struct {
+	Username string `json:"username"`
+	SessKey  string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+type tokenMgmt struct {
+	cache data.CacheStorage
+	// keys 第一个用于签名，其余只用于校验，轮换密钥时保留到旧令牌全部过期
+	keys        []tokenKey
+	accessTtlS  uint64
+	refreshTtlS uint64
+
+	revokedMu sync.RWMutex
+	revoked   map[string]uint64
+}
+
+// newTokenMgmt 读取 AUTH_TOKEN_MODE，未启用访问令牌时返回 nil
+func newTokenMgmt(cache data.CacheStorage) (*tokenMgmt, error) {
+	switch mode := os.Getenv("AUTH_TOKEN_MODE"); mode {
+	case "", AuthTokenModeSession:
+		return nil, nil
+	case AuthTokenModeJwt:
+	default:
+		return nil, fmt.Errorf("unknown AUTH_TOKEN_MODE: %s", mode)
+	}
+
+	keys, err := parseTokenKeys(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		return nil, fmt.Errorf("JWT_KEYS: %w", err)
+	}
+	accessTtlS, err := envSecs("ACCESS_TOKEN_TTL_S", 15*60)
+	if err != nil {
+		return nil, err
+	}
+	refreshTtlS, err := envSecs("REFRESH_TOKEN_TTL_S", 30*24*60*60)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &tokenMgmt{
+		cache:       cache,
+		keys:        keys,
+		accessTtlS:  accessTtlS,
+		refreshTtlS: refreshTtlS,
+		revoked:     make(map[string]uint64),
+	}
+	err = p.syncRevoked(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	go p.syncRevokedLoop()
+	return p, nil
+}
+
+func envSecs(name string, def uint64) (uint64, error) {
+	s := os.Getenv(name)
+	if s == "" {
+		return def, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || v == 0 {
+		return 0, fmt.Errorf("invalid %s value: %s", name, s)
+	}
+	return v, nil
+}
+
+// parseTokenKeys 解析逗号分隔的 kid:私钥，私钥为 base64 编码的 32 字节 Ed25519 种子
+func parseTokenKeys(s string) (keys []tokenKey, err error) {
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kid, seedStr, ok := strings.Cut(item, ":")
+		if !ok || kid == "" {
+			return nil, fmt.Errorf("invalid key %q, want kid:seed", item)
+		}
+		seed, err := base64.StdEncoding.DecodeString(seedStr)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kid, err)
+		}
+		if len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("key %s: seed is %d bytes, want %d", kid, len(seed), ed25519.SeedSize)
+		}
+		for _, k := range keys {
+			if k.kid == kid {
+				return nil, fmt.Errorf("duplicate kid %s", kid)
+			}
+		}
+		keys = append(keys, tokenKey{kid: kid, privateKey: ed25519.NewKeyFromSeed(seed)})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing key")
+	}
+	return keys, nil
+}
+
+// GenerateTokenKey 生成一个 JWT_KEYS 中的密钥
+func GenerateTokenKey(kid string) (string, error) {
+	seed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(seed)
+	if err != nil {
+		return "", fmt.Errorf("rand.Read: %w", err)
+	}
+	return kid + ":" + base64.StdEncoding.EncodeToString(seed), nil
+}
+
+func (p *tokenMgmt) issue(sessCtx *types.SessCtx) (token string, expiresAt uint64, err error) {
+	now := time.Now()
+	exp := now.Add(time.Duration(p.accessTtlS) * time.Second)
+	claims := accessTokenClaims{
+		Username: sessCtx.Username,
+		SessKey:  sessCtx.SessKey,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatUint(sessCtx.Uid, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+	key := p.keys[0]
+	t := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	t.Header["kid"] = key.kid
+	token, err = t.SignedString(key.privateKey)
+	if err != nil {
+		return "", 0, fmt.Errorf("SignedString: %w", err)
+	}
+	return token, uint64(exp.Unix()), nil
+}
+
+func (p *tokenMgmt) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	for _, k := range p.keys {
+		if k.kid == kid {
+			return k.privateKey.Public(), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown kid: %q", kid)
+}
+
+// verify 校验访问令牌，无效、过期或已吊销时返回 proj_err.ErrSessNotExisted
+func (p *tokenMgmt) verify(token string) (*types.SessCtx, error) {
+	var claims accessTokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, p.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", proj_err.ErrSessNotExisted, err)
+	}
+	uid, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || claims.SessKey == "" {
+		return nil, fmt.Errorf("%w: invalid claims", proj_err.ErrSessNotExisted)
+	}
+	if p.isRevoked(claims.SessKey) {
+		return nil, fmt.Errorf("%w: token revoked", proj_err.ErrSessNotExisted)
+	}
+
+	sessCtx := &types.SessCtx{
+		Uid:       uid,
+		Username:  claims.Username,
+		ExpiresAt: uint64(claims.ExpiresAt.Unix()),
+		SessKey:   claims.SessKey,
+		ViaToken:  true,
+	}
+	if claims.IssuedAt != nil {
+		sessCtx.CreatedAt = uint64(claims.IssuedAt.Unix())
+	}
+	return sessCtx, nil
+}
+
+// check 令牌过期或会话已下线时返回 proj_err.ErrSessNotExisted，用于长连接定期检查
+func (p *tokenMgmt) check(sessCtx *types.SessCtx) error {
+	if sessCtx.ExpiresAt <= uint64(time.Now().Unix()) {
+		return fmt.Errorf("%w: token expired", proj_err.ErrSessNotExisted)
+	}
+	if p.isRevoked(sessCtx.SessKey) {
+		return fmt.Errorf("%w: token revoked", proj_err.ErrSessNotExisted)
+	}
+	return nil
+}
+
+func (p *tokenMgmt) isRevoked(sessKey string) bool {
+	p.revokedMu.RLock()
+	defer p.revokedMu.RUnlock()
+	_, ok := p.revoked[sessKey]
+	return ok
+}
+
+// revoke 吊销会话已签发的访问令牌，保留到这些令牌全部过期
+func (p *tokenMgmt) revoke(ctx context.Context, sessKeys []string) error {
+	if len(sessKeys) == 0 {
+		return nil
+	}
+	until := uint64(time.Now().Unix()) + p.accessTtlS
+	p.revokedMu.Lock()
+	for _, sessKey := range sessKeys {
+		p.revoked[sessKey] = until
+	}
+	p.revokedMu.Unlock()
+
+	err := p.cache.RevokeTokenSess(ctx, sessKeys, until)
+	if err != nil {
+		return fmt.Errorf("cache.RevokeTokenSess: %w", err)
+	}
+	return nil
+}
+
+// syncRevoked 把缓存中的吊销列表合并到本节点的副本。
+// 本节点的吊销可能还没写入缓存或写入失败，副本中未过期的保留，过期的删除
+func (p *tokenMgmt) syncRevoked(ctx context.Context) error {
+	revoked, err := p.cache.GetRevokedTokenSess(ctx)
+	if err != nil {
+		return fmt.Errorf("cache.GetRevokedTokenSess: %w", err)
+	}
+	now := uint64(time.Now().Unix())
+	p.revokedMu.Lock()
+	for sessKey, until := range p.revoked {
+		if until > now && until > revoked[sessKey] {
+			revoked[sessKey] = until
+		}
+	}
+	p.revoked = revoked
+	p.revokedMu.Unlock()
+	return nil
+}
+
+func (p *tokenMgmt) syncRevokedLoop() {
+	ticker := time.NewTicker(tokenRevocationSyncInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		err := p.syncRevoked(context.Background())
+		if err != nil {
+			// 同步失败时副本不变，其他节点的吊销在下次同步成功时合并进来
+			Log.Warn("syncRevoked: %v", err)
+		}
+	}
+}
+
+// revokedList 返回吊销列表的副本
+func (p *tokenMgmt) revokedList() map[string]uint64 {
+	p.revokedMu.RLock()
+	defer p.revokedMu.RUnlock()
+	now := uint64(time.Now().Unix())
+	revoked := make(map[string]uint64, len(p.revoked))
+	for sessKey, until := range p.revoked {
+		if until > now {
+			revoked[sessKey] = until
+		}
+	}
+	return revoked
+}
+
+// jwks 以 JWK Set 格式返回校验用的公钥
+func (p *tokenMgmt) jwks() ([]byte, error) {
+	type jwk struct {
+		Kty string `json:"kty"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	for _, k := range p.keys {
+		set.Keys = append(set.Keys, jwk{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k.privateKey.Public().(ed25519.PublicKey)),
+			Kid: k.kid,
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Use: "sig",
+		})
+	}
+	return json.Marshal(set)
+}
@@ -0,0 +1,51 @@
+package sess_mgmt
+
+import (
+	"context"
+	"errors"
+	"social_server/src/app/data"
+	"testing"
+	"time"
+)
+
+// revokeFailingCache 吊销写入缓存失败
+type revokeFailingCache struct {
+	data.CacheStorage
+}
+
+func (p revokeFailingCache) RevokeTokenSess(ctx context.Context, sessKeys []string, until uint64) error {
+	return errors.New("cache unavailable")
+}
+
+// TestSyncRevokedKeepsLocal 同步吊销列表时保留本节点未写入缓存的吊销，删除已过期的
+func TestSyncRevokedKeepsLocal(t *testing.T) {
+	cache := data.NewMemCache()
+	p := &tokenMgmt{
+		cache:      revokeFailingCache{cache},
+		accessTtlS: 60,
+		revoked:    make(map[string]uint64),
+	}
+	ctx := context.Background()
+
+	// 其他节点的吊销
+	err := cache.RevokeTokenSess(ctx, []string{"remote"}, uint64(time.Now().Unix())+60)
+	if err != nil {
+		t.Fatalf("RevokeTokenSess: %v", err)
+	}
+	if err := p.revoke(ctx, []string{"local"}); err == nil {
+		t.Fatalf("revoke succeeded with a failing cache")
+	}
+	p.revokedMu.Lock()
+	p.revoked["expired"] = uint64(time.Now().Unix()) - 1
+	p.revokedMu.Unlock()
+
+	err = p.syncRevoked(ctx)
+	if err != nil {
+		t.Fatalf("syncRevoked: %v", err)
+	}
+	for sessKey, want := range map[string]bool{"remote": true, "local": true, "expired": false} {
+		if got := p.isRevoked(sessKey); got != want {
+			t.Errorf("isRevoked(%s) = %v, want %v", sessKey, got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package user_mgmt
+
+import (
+	"context"
+	"fmt"
+	"social_server/src/app/data"
+	. "social_server/src/utils/log"
+	"time"
+)
+
+// 联系人和群成员关系缓存，cache-aside：
+// 先查缓存，未命中时回源数据库并写回缓存；写数据库成功后清除相关缓存。
+// 回源期间缓存被清除时，写回按未命中时取得的 fillVersion 放弃，旧数据不会留在缓存中。
+// 同一个 key 的并发回源经 singleflight 合并为一次查询，避免缓存失效时大量请求同时打到数据库。
+// 缓存读写失败不影响请求，直接回源数据库
+
+// relationLoadTimeout 合并后的回源查询的超时
+const relationLoadTimeout = 10 * time.Second
+
+type contactRelation struct {
+	isMutualContact bool
+	remarkName      string
+}
+
+func contactListKey(uid uint64) string {
+	return fmt.Sprintf("contact:list:%d", uid)
+}
+
+func contactRelationKey(uid uint64, contactUid uint64) string {
+	return fmt.Sprintf("contact:relation:%d:%d", uid, contactUid)
+}
+
+func groupListKey(uid uint64) string {
+	return fmt.Sprintf("group:list:%d", uid)
+}
+
+func groupMemKey(groupId uint64, uid uint64) string {
+	return fmt.Sprintf("group:mem:%d:%d", groupId, uid)
+}
+
+// cacheHit 缓存是否命中。未命中以外的错误记录日志后按未命中处理
+func cacheHit(err error) bool {
+	if err == nil {
+		return true
+	}
+	if _, ok := err.(*data.CacheNotFoundError); !ok {
+		Log.Warn("cache error: %v", err)
+	}
+	return false
+}
+
+// loadShared 回源数据库，同一个 key 同时只有一个查询，其余调用方共享结果。
+// 查询不使用发起者的 ctx，发起者取消时不影响其他等待的调用方；每个调用方只按自己的 ctx 放弃等待
+func loadShared[T any](ctx context.Context, p *UserMgmt, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	ch := p.loadGroup.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.Background(), relationLoadTimeout)
+		defer cancel()
+		return load(loadCtx)
+	})
+	var zero T
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// invalidateContacts 清除两个用户之间双向的联系人缓存
+func (p *UserMgmt) invalidateContacts(ctx context.Context, uid uint64, contactUid uint64) {
+	// 进行中的回源可能读到旧数据，之后的请求不再共享它的结果
+	p.loadGroup.Forget(contactListKey(uid))
+	p.loadGroup.Forget(contactListKey(contactUid))
+	p.loadGroup.Forget(contactRelationKey(uid, contactUid))
+	p.loadGroup.Forget(contactRelationKey(contactUid, uid))
+	err := p.cache.ClearCacheContacts(ctx, uid, contactUid)
+	if err != nil {
+		Log.Error("clear cache contacts error: %v", err)
+	}
+}
+
+// invalidateGroupMembers 清除 uids 的群列表缓存和在群 groupId 中的成员关系缓存
+func (p *UserMgmt) invalidateGroupMembers(ctx context.Context, groupId uint64, uids ...uint64) {
+	for _, uid := range uids {
+		p.loadGroup.Forget(groupListKey(uid))
+		p.loadGroup.Forget(groupMemKey(groupId, uid))
+	}
+	err := p.cache.ClearCacheGroupMembers(ctx, groupId, uids)
+	if err != nil {
+		Log.Error("clear cache group members error: %v", err)
+	}
+}
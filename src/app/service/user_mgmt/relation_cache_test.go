@@ -0,0 +1,55 @@
+package user_mgmt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestLoadSharedCallerCancel 发起回源的调用方取消后，查询继续执行，其他等待的调用方拿到结果
+func TestLoadSharedCallerCancel(t *testing.T) {
+	p := &UserMgmt{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		close(started)
+		select {
+		case <-release:
+			return 42, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := loadShared(firstCtx, p, "k", load)
+		firstErr <- err
+	}()
+	<-started
+
+	second := make(chan int, 1)
+	go func() {
+		v, err := loadShared(context.Background(), p, "k", func(ctx context.Context) (int, error) {
+			t.Error("second load should share the in-flight one")
+			return 0, nil
+		})
+		if err != nil {
+			t.Errorf("second loadShared: %v", err)
+		}
+		second <- v
+	}()
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("first loadShared: %v, want context.Canceled", err)
+	}
+	// 等第二个调用方加入后再放行查询
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	if v := <-second; v != 42 {
+		t.Fatalf("second loadShared = %d, want 42", v)
+	}
+}
@@ -1,10 +1,15 @@
 package user_mgmt
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"golang.org/x/sync/singleflight"
 	"regexp"
+	"social_server/src/app/common/proj_err"
 	"social_server/src/app/common/types"
+	"social_server/src/app/common/utils"
 	"social_server/src/app/data"
 	. "social_server/src/utils/log"
 	"time"
@@ -12,11 +17,13 @@ import (
 )
 
 type UserMgmt struct {
-	storage *data.DB
-	cache *data.Cache
+	storage data.Storage
+	cache data.CacheStorage
+	// loadGroup 合并联系人和群组缓存未命中时的并发回源
+	loadGroup singleflight.Group
 }
 
-func NewUserMgmt(storage *data.DB, cache *data.Cache) *UserMgmt {
+func NewUserMgmt(storage data.Storage, cache data.CacheStorage) *UserMgmt {
 	return &UserMgmt{
 		storage: storage,
 		cache: cache,
@@ -98,77 +105,114 @@ func (p *UserMgmt) UserInfoValidate(param *types.UmUserInfoValidateParam) (err e
 	return nil
 }
 
-func (p *UserMgmt) IsUsernameExisted(userName string) (bool, error) {
+func (p *UserMgmt) IsUsernameExisted(ctx context.Context, userName string) (bool, error) {
 	// 查询数据库
-	isExist, err := p.storage.UserIsUsernameExisted(userName)
+	isExist, err := p.storage.UserIsUsernameExisted(ctx, userName)
 	if err != nil {
 		return false, err
 	}
 	return isExist, nil
 }
 
-func (p *UserMgmt) UserAuthenticate(param *types.UmUserAuthenticateParam) (pass bool, err error) {
+// UserAuthenticate 验证用户名和密码，param.Passphase 为明文密码。
+// 密码哈希是旧格式或参数已过时的，验证通过后升级为当前格式
+func (p *UserMgmt) UserAuthenticate(ctx context.Context, param *types.UmUserAuthenticateParam) (pass bool, err error) {
 	// 查询缓存
-	pass, err = p.cache.UserAuthenticate(param)
-	if err != nil {
-		_, ok := err.(*data.CacheNotFoundError)
-		if !ok {
-			return false, err
+	user, err := p.cache.GetUserAuth(ctx, param.Username)
+	fromCache := cacheHit(err)
+	if !fromCache {
+		// 若无缓存，查询数据库
+		user, err = p.storage.UserGetInfoByUsername(ctx, param.Username)
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.VerifyDummyPassword(param.Passphase)
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("UserGetInfoByUsername: %w", err)
 		}
-	} else {
-		return pass, nil
 	}
 
-	// 若无缓存，查询数据库，并更新缓存
-	pass, err = p.storage.UserAuthenticate(param)
+	// 验证密码
+	match, needRehash, err := utils.VerifyPassword(param.Passphase, user.Password)
 	if err != nil {
-		return false, err
+		return false, fmt.Errorf("VerifyPassword: %w", err)
+	}
+	if !match {
+		return false, nil
 	}
-	if pass {
-		err = p.cache.CacheUserAuthenticate(&types.UmUserInfo{
-			Uid:      0,
-			Username: param.Username,
-			Password: param.Passphase,
-			Email:    "",
+	if needRehash {
+		p.rehashPassword(ctx, user, param.Passphase)
+		return true, nil
+	}
+
+	// 更新缓存。登录名大小写不同时缓存键与修改密码时清除的键不一致，不缓存
+	if !fromCache && user.Username == param.Username {
+		err = p.cache.CacheUserAuthenticate(ctx, &types.UmUserInfo{
+			Uid:      user.Uid,
+			Username: user.Username,
+			Password: user.Password,
 		})
 		if err != nil {
 			Log.Warn("cache user authenticate error: %v", err)
 		}
 	}
 
-	return pass, nil
+	return true, nil
+}
+
+// rehashPassword 把密码哈希升级为当前格式。失败不影响本次登录，下次登录再升级
+func (p *UserMgmt) rehashPassword(ctx context.Context, user *types.UmUserInfo, password string) {
+	newPassHash, err := utils.HashPassword(password)
+	if err != nil {
+		Log.Error("HashPassword: %v", err)
+		return
+	}
+	// 密码刚被修改时不覆盖
+	updated, err := p.storage.UserUpdatePassHash(ctx, user.Uid, user.Password, newPassHash)
+	if err != nil {
+		Log.Error("UserUpdatePassHash: %v", err)
+		return
+	}
+	if !updated {
+		return
+	}
+	Log.Info("password hash of user %d upgraded", user.Uid)
+	err = p.cache.ClearCacheUserAuthenticate(ctx, user.Username)
+	if err != nil {
+		Log.Error("clear cache user authenticate error: %v", err)
+	}
 }
 
-func (p *UserMgmt) UserGetInfoByUsername(username string) (userInfo *types.UmUserInfo, err error) {
-	userInfo, err = p.storage.UserGetInfoByUsername(username)
+func (p *UserMgmt) UserGetInfoByUsername(ctx context.Context, username string) (userInfo *types.UmUserInfo, err error) {
+	userInfo, err = p.storage.UserGetInfoByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("UserGetInfoByUsername: %v", err)
 	}
 	return userInfo, nil
 }
 
-func (p *UserMgmt) Register(param *types.UmRegisterParam) (err error) {
-	_, err = p.storage.UserRegister(param)
+func (p *UserMgmt) Register(ctx context.Context, param *types.UmRegisterParam) (err error) {
+	_, err = p.storage.UserRegister(ctx, param)
 	return err
 }
 
-func (p *UserMgmt) Unregister(param *types.UmUnregisterParam) (err error) {
+func (p *UserMgmt) Unregister(ctx context.Context, param *types.UmUnregisterParam) (err error) {
 	// 查询用户信息
 	var userInfo *types.UmUserInfo
-	userInfo, err = p.storage.UserGetInfo(param.Uid)
+	userInfo, err = p.storage.UserGetInfo(ctx, param.Uid)
 	if err != nil {
 		return err
 	}
 
 	// 清除用户信息缓存
-	err = p.cache.ClearCacheUserAuthenticate(userInfo.Username)
+	err = p.cache.ClearCacheUserAuthenticate(ctx, userInfo.Username)
 	if err != nil {
 		Log.Error("clear cache user authenticate error: %v", err)
 		return err
 	}
 
 	// 更新数据库
-	err = p.storage.UserUnregister(param)
+	err = p.storage.UserUnregister(ctx, param)
 	if err != nil {
 		return err
 	}
@@ -176,7 +220,7 @@ func (p *UserMgmt) Unregister(param *types.UmUnregisterParam) (err error) {
 	// 启动协程，5秒后再删一次缓存
 	go func() {
 		time.Sleep(5 * time.Second)
-		err = p.cache.ClearCacheUserAuthenticate(userInfo.Username)
+		err = p.cache.ClearCacheUserAuthenticate(ctx, userInfo.Username)
 		if err != nil {
 			Log.Error("clear cache user authenticate error: %v", err)
 		}
@@ -185,25 +229,35 @@ func (p *UserMgmt) Unregister(param *types.UmUnregisterParam) (err error) {
 	return nil
 }
 
-func (p *UserMgmt) UserUpdateInfo(uid uint64, nickname string, email string, avatar string, password string, newPassword string) (err error) {
+// UserUpdateInfo 更新用户信息。password 为明文旧密码，非空时先验证；newPassword 为新密码的哈希，
+// 修改密码必须提供正确的旧密码，否则返回 proj_err.ErrWrongPassword
+func (p *UserMgmt) UserUpdateInfo(ctx context.Context, uid uint64, nickname string, email string, avatar string, password string, newPassword string) (err error) {
+	if newPassword != "" && password == "" {
+		return fmt.Errorf("%w: old password required", proj_err.ErrWrongPassword)
+	}
 	// 验证密码
 	var userInfo *types.UmUserInfo
 	if password != "" {
-		userInfo, err = p.storage.UserGetInfo(uid)
+		userInfo, err = p.storage.UserGetInfo(ctx, uid)
 		if err != nil {
 			return fmt.Errorf("UserGetInfo: %w", err)
 		}
-		if userInfo.Password != password {
-			return fmt.Errorf("wrong password")
+		match, _, err := utils.VerifyPassword(password, userInfo.Password)
+		if err != nil {
+			return fmt.Errorf("VerifyPassword: %w", err)
+		}
+		if !match {
+			return fmt.Errorf("%w", proj_err.ErrWrongPassword)
 		}
 	}
 	// 更新用户信息
-	err = p.storage.UserUpdateInfo(uid, nickname, email, avatar, newPassword)
+	err = p.storage.UserUpdateInfo(ctx, uid, nickname, email, avatar, newPassword)
 	if err != nil {
 		return fmt.Errorf("UserUpdateInfo: %w", err)
 	}
-	if password != "" {
-		err = p.cache.ClearCacheUserAuthenticate(userInfo.Username)
+	// 缓存中的旧密码哈希仍能通过认证，修改密码后清除
+	if newPassword != "" {
+		err = p.cache.ClearCacheUserAuthenticate(ctx, userInfo.Username)
 		if err != nil {
 			Log.Error("clear cache user authenticate error: %v", err)
 		}
@@ -211,167 +265,233 @@ func (p *UserMgmt) UserUpdateInfo(uid uint64, nickname string, email string, ava
 	return nil
 }
 
-func (p *UserMgmt) ContactGetList(uid uint64) (contactUidList []uint64, err error) {
-	contactUidList, err = p.storage.ContactGetList(uid)
-	if err != nil {
-		return nil, err
+func (p *UserMgmt) ContactGetList(ctx context.Context, uid uint64) (contactUidList []uint64, err error) {
+	// 查询缓存
+	contactUidList, fillVersion, err := p.cache.GetContactList(ctx, uid)
+	if cacheHit(err) {
+		return contactUidList, nil
 	}
-	return contactUidList, nil
+
+	// 若无缓存，查询数据库，并更新缓存
+	return loadShared(ctx, p, contactListKey(uid), func(ctx context.Context) ([]uint64, error) {
+		contactUidList, err := p.storage.ContactGetList(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		err = p.cache.CacheContactList(ctx, uid, fillVersion, contactUidList)
+		if err != nil {
+			Log.Warn("cache contact list error: %v", err)
+		}
+		return contactUidList, nil
+	})
 }
 
-func (p *UserMgmt) ContactGetRelation(uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, err error) {
-	isMutualContact, remarkName, err = p.storage.ContactGetRelation(uid, contactUid)
+func (p *UserMgmt) ContactGetRelation(ctx context.Context, uid uint64, contactUid uint64) (isMutualContact bool, remarkName string, err error) {
+	// 查询缓存
+	isMutualContact, remarkName, fillVersion, err := p.cache.GetContactRelation(ctx, uid, contactUid)
+	if cacheHit(err) {
+		return isMutualContact, remarkName, nil
+	}
+
+	// 若无缓存，查询数据库，并更新缓存
+	relation, err := loadShared(ctx, p, contactRelationKey(uid, contactUid), func(ctx context.Context) (contactRelation, error) {
+		isMutualContact, remarkName, err := p.storage.ContactGetRelation(ctx, uid, contactUid)
+		if err != nil {
+			return contactRelation{}, err
+		}
+		err = p.cache.CacheContactRelation(ctx, uid, contactUid, fillVersion, isMutualContact, remarkName)
+		if err != nil {
+			Log.Warn("cache contact relation error: %v", err)
+		}
+		return contactRelation{isMutualContact: isMutualContact, remarkName: remarkName}, nil
+	})
 	if err != nil {
 		return false, "", fmt.Errorf("ContactGetRelation: %w", err)
 	}
-	return isMutualContact, remarkName, nil
+	return relation.isMutualContact, relation.remarkName, nil
 }
 
-func (p *UserMgmt) ContactFind(username string) (userInfo *types.UmUserInfo, err error) {
-	userInfo, err = p.storage.UserGetInfoByUsername(username)
+func (p *UserMgmt) ContactFind(ctx context.Context, username string) (userInfo *types.UmUserInfo, err error) {
+	userInfo, err = p.storage.UserGetInfoByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("UserGetInfoByUsername: %v", err)
 	}
 	return userInfo, nil
 }
 
-func (p *UserMgmt) ContactGetInfo(uid uint64) (userInfo *types.UmUserInfo, err error) {
-	userInfo, err = p.storage.UserGetInfo(uid)
+func (p *UserMgmt) ContactGetInfo(ctx context.Context, uid uint64) (userInfo *types.UmUserInfo, err error) {
+	userInfo, err = p.storage.UserGetInfo(ctx, uid)
 	if err != nil {
 		return nil, fmt.Errorf("UserGetInfoByUsername: %v", err)
 	}
 	return userInfo, nil
 }
 
-func (p *UserMgmt) ContactAccept(uid uint64, contactUid uint64) (err error) {
+func (p *UserMgmt) ContactAccept(ctx context.Context, uid uint64, contactUid uint64) (err error) {
 	// 更新数据库
-	err = p.storage.ContactAccept(uid, contactUid)
+	err = p.storage.ContactAccept(ctx, uid, contactUid)
 	if err != nil {
 		return fmt.Errorf("ContactAdd: %v", err)
 	}
+	p.invalidateContacts(ctx, uid, contactUid)
 	return err
 }
 
-func (p *UserMgmt) ContactReject(uid uint64, contactUid uint64) (err error) {
+func (p *UserMgmt) ContactReject(ctx context.Context, uid uint64, contactUid uint64) (err error) {
 	// 更新数据库
-	err = p.storage.ContactReject(uid, contactUid)
+	err = p.storage.ContactReject(ctx, uid, contactUid)
 	if err != nil {
 		return fmt.Errorf("ContactReject: %v", err)
 	}
 	return err
 }
 
-func (p *UserMgmt) ContactDel(uid uint64, contactUid uint64) (err error) {
+func (p *UserMgmt) ContactDel(ctx context.Context, uid uint64, contactUid uint64) (err error) {
 	// 更新数据库
-	err = p.storage.ContactDel(uid, contactUid)
+	err = p.storage.ContactDel(ctx, uid, contactUid)
 	if err != nil {
 		return fmt.Errorf("ContactDel: %v", err)
 	}
+	p.invalidateContacts(ctx, uid, contactUid)
 	return err
 }
 
-func (p *UserMgmt) GroupGetList(uid uint64) (ConvIdList []uint64, err error) {
-	ConvIdList, err = p.storage.GroupGetList(uid)
-	if err != nil {
-		return nil, err
+func (p *UserMgmt) GroupGetList(ctx context.Context, uid uint64) (ConvIdList []uint64, err error) {
+	// 查询缓存
+	ConvIdList, fillVersion, err := p.cache.GroupGetList(ctx, uid)
+	if cacheHit(err) {
+		return ConvIdList, nil
 	}
-	return ConvIdList, nil
+
+	// 若无缓存，查询数据库，并更新缓存
+	return loadShared(ctx, p, groupListKey(uid), func(ctx context.Context) ([]uint64, error) {
+		ConvIdList, err := p.storage.GroupGetList(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		err = p.cache.CacheGroupConvList(ctx, uid, fillVersion, ConvIdList)
+		if err != nil {
+			Log.Warn("cache group list error: %v", err)
+		}
+		return ConvIdList, nil
+	})
 }
 
-func (p *UserMgmt) GroupGetInfo(groupId uint64) (groupInfo *types.UmGroupInfo, err error) {
-	groupInfo, err = p.storage.GroupGetInfo(groupId)
+func (p *UserMgmt) GroupGetInfo(ctx context.Context, groupId uint64) (groupInfo *types.UmGroupInfo, err error) {
+	groupInfo, err = p.storage.GroupGetInfo(ctx, groupId)
 	if err != nil {
 		return nil, fmt.Errorf("GroupGetInfo: %v", err)
 	}
 	return groupInfo, nil
 }
 
-func (p *UserMgmt) GroupUpdateInfo(groupId uint64, groupName string, avatar string) (err error) {
-	err = p.storage.GroupUpdateInfo(groupId, groupName, avatar)
+func (p *UserMgmt) GroupUpdateInfo(ctx context.Context, groupId uint64, groupName string, avatar string) (err error) {
+	err = p.storage.GroupUpdateInfo(ctx, groupId, groupName, avatar)
 	if err != nil {
 		return fmt.Errorf("GroupUpdateInfo: %w", err)
 	}
 	return nil
 }
 
-func (p *UserMgmt) GroupFind(groupId uint64) (groupInfo *types.UmGroupInfo, err error) {
-	groupInfo, err = p.storage.GroupGetInfo(groupId)
+func (p *UserMgmt) GroupFind(ctx context.Context, groupId uint64) (groupInfo *types.UmGroupInfo, err error) {
+	groupInfo, err = p.storage.GroupGetInfo(ctx, groupId)
 	if err != nil {
 		return nil, fmt.Errorf("GroupGetInfo: %v", err)
 	}
 	return groupInfo, nil
 }
 
-func (p *UserMgmt) GroupCreate(uid uint64, groupName string) (ConvId uint64, err error) {
+func (p *UserMgmt) GroupCreate(ctx context.Context, uid uint64, groupName string) (ConvId uint64, err error) {
 	// 更新数据库
-	ConvId, err = p.storage.GroupCreate(uid, groupName)
+	ConvId, err = p.storage.GroupCreate(ctx, uid, groupName)
 	if err != nil {
 		return 0, err
 	}
+	p.invalidateGroupMembers(ctx, ConvId, uid)
 	return ConvId, nil
 }
 
-func (p *UserMgmt) GroupDelete(uid uint64, groupId uint64) (err error) {
+func (p *UserMgmt) GroupDelete(ctx context.Context, uid uint64, groupId uint64) (err error) {
+	// 删除前取成员列表，用于清除缓存
+	memUidList, err := p.storage.GroupGetMemList(ctx, groupId)
+	if err != nil {
+		return fmt.Errorf("GroupGetMemList: %w", err)
+	}
 	// 更新数据库
-	err = p.storage.GroupDelete(uid, groupId)
+	err = p.storage.GroupDelete(ctx, uid, groupId)
 	if err != nil {
 		return fmt.Errorf("GroupDelete: %w", err)
 	}
+	p.invalidateGroupMembers(ctx, groupId, memUidList...)
 	return nil
 }
 
-func (p *UserMgmt) GroupGetMemList(groupId uint64) (uidList []uint64, err error) {
-	uidList, err = p.storage.GroupGetMemList(groupId)
+func (p *UserMgmt) GroupGetMemList(ctx context.Context, groupId uint64) (uidList []uint64, err error) {
+	uidList, err = p.storage.GroupGetMemList(ctx, groupId)
 	if err != nil {
 		return nil, fmt.Errorf("GroupGetMemList: %w", err)
 	}
 	return uidList, nil
 }
 
-func (p *UserMgmt) GroupGetAdminList(groupId uint64) (uidList []uint64, err error) {
-	uidList, err = p.storage.GroupGetAdminList(groupId)
+func (p *UserMgmt) GroupGetAdminList(ctx context.Context, groupId uint64) (uidList []uint64, err error) {
+	uidList, err = p.storage.GroupGetAdminList(ctx, groupId)
 	if err != nil {
 		return nil, fmt.Errorf("GroupGetAdminList: %w", err)
 	}
 	return uidList, nil
 }
 
-func (p *UserMgmt) GroupIsOwner(groupId uint64, uid uint64) (isOwner bool, err error) {
-	isOwner, err = p.storage.GroupIsOwner(groupId, uid)
+func (p *UserMgmt) GroupIsOwner(ctx context.Context, groupId uint64, uid uint64) (isOwner bool, err error) {
+	isOwner, err = p.storage.GroupIsOwner(ctx, groupId, uid)
 	if err != nil {
 		return false, fmt.Errorf("GroupIsOwner: %w", err)
 	}
 	return isOwner, nil
 }
 
-func (p *UserMgmt) GroupIsMem(groupId uint64, uid uint64) (inGroup bool, err error) {
-	inGroup, err = p.storage.GroupIsMem(groupId, uid)
-	if err != nil {
-		return false, err
+func (p *UserMgmt) GroupIsMem(ctx context.Context, groupId uint64, uid uint64) (inGroup bool, err error) {
+	// 查询缓存
+	inGroup, fillVersion, err := p.cache.IsUserInConv(ctx, groupId, uid)
+	if cacheHit(err) {
+		return inGroup, nil
 	}
-	return inGroup, nil
+
+	// 若无缓存，查询数据库，并更新缓存
+	return loadShared(ctx, p, groupMemKey(groupId, uid), func(ctx context.Context) (bool, error) {
+		inGroup, err := p.storage.GroupIsMem(ctx, groupId, uid)
+		if err != nil {
+			return false, err
+		}
+		err = p.cache.CacheIsUserInConv(ctx, groupId, uid, fillVersion, inGroup)
+		if err != nil {
+			Log.Warn("cache group membership error: %v", err)
+		}
+		return inGroup, nil
+	})
 }
 
-func (p *UserMgmt) GroupClearMsg(groupId uint64, uid uint64) (err error) {
-	err = p.storage.GroupClearMsg(groupId, uid)
+func (p *UserMgmt) GroupClearMsg(ctx context.Context, groupId uint64, uid uint64) (err error) {
+	err = p.storage.GroupClearMsg(ctx, groupId, uid)
 	if err != nil {
 		return fmt.Errorf("GroupClearMsg: %w", err)
 	}
 	return nil
 }
 
-func (p *UserMgmt) GroupLeave(groupId uint64, uid uint64) (err error) {
+func (p *UserMgmt) GroupLeave(ctx context.Context, groupId uint64, uid uint64) (err error) {
 	// 更新数据库
-	err = p.storage.GroupLeave(groupId, uid)
+	err = p.storage.GroupLeave(ctx, groupId, uid)
 	if err != nil {
 		return fmt.Errorf("GroupLeave: %w", err)
 	}
+	p.invalidateGroupMembers(ctx, groupId, uid)
 	return nil
 }
 
-func (p *UserMgmt) GroupAddMem(groupId uint64, adminUid uint64, uid uint64) (err error) {
+func (p *UserMgmt) GroupAddMem(ctx context.Context, groupId uint64, adminUid uint64, uid uint64) (err error) {
 	// 检查 adminUid 是否为管理员
-	isAdmin, err := p.storage.GroupIsAdmin(groupId, adminUid)
+	isAdmin, err := p.storage.GroupIsAdmin(ctx, groupId, adminUid)
 	if err != nil {
 		return fmt.Errorf("GroupIsAdmin: %w", err)
 	}
@@ -380,17 +500,18 @@ func (p *UserMgmt) GroupAddMem(groupId uint64, adminUid uint64, uid uint64) (err
 	}
 
 	// 更新数据库
-	err = p.storage.GroupAddMem(groupId, uid, 0)
+	err = p.storage.GroupAddMem(ctx, groupId, uid, 0)
 	if err != nil {
 		return fmt.Errorf("GroupAddMem: %w", err)
 	}
+	p.invalidateGroupMembers(ctx, groupId, uid)
 
 	return nil
 }
 
-func (p *UserMgmt) GroupDelMem(groupId uint64, adminUid uint64, uid uint64) (err error) {
+func (p *UserMgmt) GroupDelMem(ctx context.Context, groupId uint64, adminUid uint64, uid uint64) (err error) {
 	// 检查 adminUid 是否为管理员
-	isAdmin, err := p.storage.GroupIsAdmin(groupId, adminUid)
+	isAdmin, err := p.storage.GroupIsAdmin(ctx, groupId, adminUid)
 	if err != nil {
 		return fmt.Errorf("GroupIsAdmin: %w", err)
 	}
@@ -399,57 +520,59 @@ func (p *UserMgmt) GroupDelMem(groupId uint64, adminUid uint64, uid uint64) (err
 	}
 
 	// 更新数据库
-	err = p.storage.GroupDelMem(groupId, uid)
+	err = p.storage.GroupDelMem(ctx, groupId, uid)
 	if err != nil {
 		return fmt.Errorf("GroupDelMem: %w", err)
 	}
+	p.invalidateGroupMembers(ctx, groupId, uid)
 
 	return nil
 }
 
-func (p *UserMgmt) GroupAccept(groupId uint64, adminUid uint64, uid uint64) (err error) {
+func (p *UserMgmt) GroupAccept(ctx context.Context, groupId uint64, adminUid uint64, uid uint64) (err error) {
 	// 检查 adminUid 是否为管理员
-	isAdmin, err := p.storage.GroupIsAdmin(groupId, adminUid)
+	isAdmin, err := p.storage.GroupIsAdmin(ctx, groupId, adminUid)
 	if err != nil {
 		return fmt.Errorf("GroupIsAdmin: %w", err)
 	}
 	if !isAdmin {
 		// GroupIgnore
-		err = p.storage.GroupIgnore(groupId, uid)
+		err = p.storage.GroupIgnore(ctx, groupId, uid)
 		Log.Warn("uid is not the admin of the group")
 		return nil
 	}
 	// 更新数据库
-	err = p.storage.GroupAccept(groupId, uid)
+	err = p.storage.GroupAccept(ctx, groupId, uid)
 	if err != nil {
 		return fmt.Errorf("GroupAccept: %w", err)
 	}
+	p.invalidateGroupMembers(ctx, groupId, uid)
 	return nil
 }
 
-func (p *UserMgmt) GroupReject(groupId uint64, adminUid uint64, uid uint64) (err error) {
+func (p *UserMgmt) GroupReject(ctx context.Context, groupId uint64, adminUid uint64, uid uint64) (err error) {
 	// 检查 adminUid 是否为管理员
-	isAdmin, err := p.storage.GroupIsAdmin(groupId, adminUid)
+	isAdmin, err := p.storage.GroupIsAdmin(ctx, groupId, adminUid)
 	if err != nil {
 		return fmt.Errorf("GroupIsAdmin: %w", err)
 	}
 	if !isAdmin {
 		// GroupIgnore
-		err = p.storage.GroupIgnore(groupId, uid)
+		err = p.storage.GroupIgnore(ctx, groupId, uid)
 		Log.Warn("uid is not the admin of the group")
 		return nil
 	}
 	// 更新数据库
-	err = p.storage.GroupReject(groupId, uid)
+	err = p.storage.GroupReject(ctx, groupId, uid)
 	if err != nil {
 		return fmt.Errorf("GroupReject: %w", err)
 	}
 	return nil
 }
 
-func (p *UserMgmt) GroupUpdateMem(groupId uint64, adminUid uint64, uid uint64, role uint) (err error) {
+func (p *UserMgmt) GroupUpdateMem(ctx context.Context, groupId uint64, adminUid uint64, uid uint64, role uint) (err error) {
 	// 检查 adminUid 是否为管理员
-	isAdmin, err := p.storage.GroupIsAdmin(groupId, adminUid)
+	isAdmin, err := p.storage.GroupIsAdmin(ctx, groupId, adminUid)
 	if err != nil {
 		return fmt.Errorf("GroupIsAdmin: %w", err)
 	}
@@ -458,7 +581,7 @@ func (p *UserMgmt) GroupUpdateMem(groupId uint64, adminUid uint64, uid uint64, r
 	}
 
 	// 更新数据库
-	err = p.storage.GroupUpdateMem(groupId, uid, role)
+	err = p.storage.GroupUpdateMem(ctx, groupId, uid, role)
 	if err != nil {
 		return fmt.Errorf("GroupUpdateMem: %w", err)
 	}
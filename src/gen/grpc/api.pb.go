@@ -32,6 +32,7 @@ const (
 	ErrCode_emErrCode_UserNotRegistered     ErrCode = 200
 	ErrCode_emErrCode_UserAlreadyRegistered ErrCode = 201
 	ErrCode_emErrCode_UserFailedToAuth      ErrCode = 202
+	ErrCode_emErrCode_LoginLocked           ErrCode = 203 // 登录失败次数过多，暂时锁定
 	ErrCode_emErrCode_IsContact             ErrCode = 300
 	ErrCode_emErrCode_IsNotContact          ErrCode = 301
 	ErrCode_emErrCode_GroupNotExisted       ErrCode = 400
@@ -48,6 +49,7 @@ var (
 		200: "emErrCode_UserNotRegistered",
 		201: "emErrCode_UserAlreadyRegistered",
 		202: "emErrCode_UserFailedToAuth",
+		203: "emErrCode_LoginLocked",
 		300: "emErrCode_IsContact",
 		301: "emErrCode_IsNotContact",
 		400: "emErrCode_GroupNotExisted",
@@ -61,6 +63,7 @@ var (
 		"emErrCode_UserNotRegistered":     200,
 		"emErrCode_UserAlreadyRegistered": 201,
 		"emErrCode_UserFailedToAuth":      202,
+		"emErrCode_LoginLocked":           203,
 		"emErrCode_IsContact":             300,
 		"emErrCode_IsNotContact":          301,
 		"emErrCode_GroupNotExisted":       400,
@@ -180,8 +183,10 @@ type SessUserLoginReq struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Username   string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password   string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	DeviceName string `protobuf:"bytes,3,opt,name=deviceName,proto3" json:"deviceName,omitempty"` // 设备名，显示在会话列表中
+	Platform   string `protobuf:"bytes,4,opt,name=platform,proto3" json:"platform,omitempty"`     // 平台，如 ios、android、web
 }
 
 func (x *SessUserLoginReq) Reset() {
@@ -230,14 +235,32 @@ func (x *SessUserLoginReq) GetPassword() string {
 	return ""
 }
 
+func (x *SessUserLoginReq) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *SessUserLoginReq) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
 type SessUserLoginRes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ErrCode ErrCode `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
-	SessId  string  `protobuf:"bytes,2,opt,name=sessId,proto3" json:"sessId,omitempty"`
-	Uid     uint64  `protobuf:"varint,3,opt,name=uid,proto3" json:"uid,omitempty"`
+	ErrCode                ErrCode `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
+	SessId                 string  `protobuf:"bytes,2,opt,name=sessId,proto3" json:"sessId,omitempty"` // 启用访问令牌时为空
+	Uid                    uint64  `protobuf:"varint,3,opt,name=uid,proto3" json:"uid,omitempty"`
+	AccessToken            string  `protobuf:"bytes,4,opt,name=accessToken,proto3" json:"accessToken,omitempty"` // 启用访问令牌时返回，代替 sessId 使用
+	AccessTokenExpiresTsMs uint64  `protobuf:"varint,5,opt,name=accessTokenExpiresTsMs,proto3" json:"accessTokenExpiresTsMs,omitempty"`
+	RefreshToken           string  `protobuf:"bytes,6,opt,name=refreshToken,proto3" json:"refreshToken,omitempty"` // 用于 SessRefresh 换取新的访问令牌，只应发给 SessRefresh
+	RetryAfterS            uint64  `protobuf:"varint,7,opt,name=retryAfterS,proto3" json:"retryAfterS,omitempty"`  // errCode 为 emErrCode_LoginLocked 时剩余的锁定秒数
 }
 
 func (x *SessUserLoginRes) Reset() {
@@ -293,6 +316,34 @@ func (x *SessUserLoginRes) GetUid() uint64 {
 	return 0
 }
 
+func (x *SessUserLoginRes) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *SessUserLoginRes) GetAccessTokenExpiresTsMs() uint64 {
+	if x != nil {
+		return x.AccessTokenExpiresTsMs
+	}
+	return 0
+}
+
+func (x *SessUserLoginRes) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *SessUserLoginRes) GetRetryAfterS() uint64 {
+	if x != nil {
+		return x.RetryAfterS
+	}
+	return 0
+}
+
 type SessUserLogoutReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -387,6 +438,514 @@ func (x *SessUserLogoutRes) GetErrCode() ErrCode {
 	return ErrCode_emErrCode_Ok
 }
 
+// 用刷新令牌换取新的访问令牌，同时为刷新令牌续期。只在启用访问令牌时可用
+type SessRefreshReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RefreshToken string `protobuf:"bytes,1,opt,name=refreshToken,proto3" json:"refreshToken,omitempty"`
+}
+
+func (x *SessRefreshReq) Reset() {
+	*x = SessRefreshReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessRefreshReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessRefreshReq) ProtoMessage() {}
+
+func (x *SessRefreshReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessRefreshReq.ProtoReflect.Descriptor instead.
+func (*SessRefreshReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SessRefreshReq) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type SessRefreshRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrCode                ErrCode `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
+	AccessToken            string  `protobuf:"bytes,2,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
+	AccessTokenExpiresTsMs uint64  `protobuf:"varint,3,opt,name=accessTokenExpiresTsMs,proto3" json:"accessTokenExpiresTsMs,omitempty"`
+}
+
+func (x *SessRefreshRes) Reset() {
+	*x = SessRefreshRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessRefreshRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessRefreshRes) ProtoMessage() {}
+
+func (x *SessRefreshRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessRefreshRes.ProtoReflect.Descriptor instead.
+func (*SessRefreshRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SessRefreshRes) GetErrCode() ErrCode {
+	if x != nil {
+		return x.ErrCode
+	}
+	return ErrCode_emErrCode_Ok
+}
+
+func (x *SessRefreshRes) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *SessRefreshRes) GetAccessTokenExpiresTsMs() uint64 {
+	if x != nil {
+		return x.AccessTokenExpiresTsMs
+	}
+	return 0
+}
+
+// 一个登录中的设备
+type SessInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessKey      string `protobuf:"bytes,1,opt,name=sessKey,proto3" json:"sessKey,omitempty"` // 会话标识，用于下线该会话，不能当作 sessId 使用
+	DeviceName   string `protobuf:"bytes,2,opt,name=deviceName,proto3" json:"deviceName,omitempty"`
+	Platform     string `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
+	Ip           string `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"` // 登录时的 IP
+	CreateTsMs   uint64 `protobuf:"varint,5,opt,name=createTsMs,proto3" json:"createTsMs,omitempty"`
+	LastSeenTsMs uint64 `protobuf:"varint,6,opt,name=lastSeenTsMs,proto3" json:"lastSeenTsMs,omitempty"` // 最近一次拉取消息或心跳的时间
+	IsCurrent    bool   `protobuf:"varint,7,opt,name=isCurrent,proto3" json:"isCurrent,omitempty"`       // 是否为发起请求的会话
+}
+
+func (x *SessInfo) Reset() {
+	*x = SessInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessInfo) ProtoMessage() {}
+
+func (x *SessInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessInfo.ProtoReflect.Descriptor instead.
+func (*SessInfo) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SessInfo) GetSessKey() string {
+	if x != nil {
+		return x.SessKey
+	}
+	return ""
+}
+
+func (x *SessInfo) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *SessInfo) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *SessInfo) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *SessInfo) GetCreateTsMs() uint64 {
+	if x != nil {
+		return x.CreateTsMs
+	}
+	return 0
+}
+
+func (x *SessInfo) GetLastSeenTsMs() uint64 {
+	if x != nil {
+		return x.LastSeenTsMs
+	}
+	return 0
+}
+
+func (x *SessInfo) GetIsCurrent() bool {
+	if x != nil {
+		return x.IsCurrent
+	}
+	return false
+}
+
+// 列出当前用户所有登录中的设备
+type SessGetListReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessId string `protobuf:"bytes,1,opt,name=sessId,proto3" json:"sessId,omitempty"`
+}
+
+func (x *SessGetListReq) Reset() {
+	*x = SessGetListReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessGetListReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessGetListReq) ProtoMessage() {}
+
+func (x *SessGetListReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessGetListReq.ProtoReflect.Descriptor instead.
+func (*SessGetListReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SessGetListReq) GetSessId() string {
+	if x != nil {
+		return x.SessId
+	}
+	return ""
+}
+
+type SessGetListRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrCode  ErrCode     `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
+	SessList []*SessInfo `protobuf:"bytes,2,rep,name=sessList,proto3" json:"sessList,omitempty"` // 按登录时间升序
+}
+
+func (x *SessGetListRes) Reset() {
+	*x = SessGetListRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessGetListRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessGetListRes) ProtoMessage() {}
+
+func (x *SessGetListRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessGetListRes.ProtoReflect.Descriptor instead.
+func (*SessGetListRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SessGetListRes) GetErrCode() ErrCode {
+	if x != nil {
+		return x.ErrCode
+	}
+	return ErrCode_emErrCode_Ok
+}
+
+func (x *SessGetListRes) GetSessList() []*SessInfo {
+	if x != nil {
+		return x.SessList
+	}
+	return nil
+}
+
+// 下线一个会话，会话已不存在时也返回成功
+type SessRevokeReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessId  string `protobuf:"bytes,1,opt,name=sessId,proto3" json:"sessId,omitempty"`
+	SessKey string `protobuf:"bytes,2,opt,name=sessKey,proto3" json:"sessKey,omitempty"`
+}
+
+func (x *SessRevokeReq) Reset() {
+	*x = SessRevokeReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessRevokeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessRevokeReq) ProtoMessage() {}
+
+func (x *SessRevokeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessRevokeReq.ProtoReflect.Descriptor instead.
+func (*SessRevokeReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SessRevokeReq) GetSessId() string {
+	if x != nil {
+		return x.SessId
+	}
+	return ""
+}
+
+func (x *SessRevokeReq) GetSessKey() string {
+	if x != nil {
+		return x.SessKey
+	}
+	return ""
+}
+
+type SessRevokeRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrCode ErrCode `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
+}
+
+func (x *SessRevokeRes) Reset() {
+	*x = SessRevokeRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessRevokeRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessRevokeRes) ProtoMessage() {}
+
+func (x *SessRevokeRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessRevokeRes.ProtoReflect.Descriptor instead.
+func (*SessRevokeRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SessRevokeRes) GetErrCode() ErrCode {
+	if x != nil {
+		return x.ErrCode
+	}
+	return ErrCode_emErrCode_Ok
+}
+
+// 下线当前会话以外的所有会话
+type SessRevokeOthersReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessId string `protobuf:"bytes,1,opt,name=sessId,proto3" json:"sessId,omitempty"`
+}
+
+func (x *SessRevokeOthersReq) Reset() {
+	*x = SessRevokeOthersReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessRevokeOthersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessRevokeOthersReq) ProtoMessage() {}
+
+func (x *SessRevokeOthersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessRevokeOthersReq.ProtoReflect.Descriptor instead.
+func (*SessRevokeOthersReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SessRevokeOthersReq) GetSessId() string {
+	if x != nil {
+		return x.SessId
+	}
+	return ""
+}
+
+type SessRevokeOthersRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrCode ErrCode `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
+}
+
+func (x *SessRevokeOthersRes) Reset() {
+	*x = SessRevokeOthersRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessRevokeOthersRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessRevokeOthersRes) ProtoMessage() {}
+
+func (x *SessRevokeOthersRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessRevokeOthersRes.ProtoReflect.Descriptor instead.
+func (*SessRevokeOthersRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SessRevokeOthersRes) GetErrCode() ErrCode {
+	if x != nil {
+		return x.ErrCode
+	}
+	return ErrCode_emErrCode_Ok
+}
+
 // 用户管理接口参数
 type UmContactInfo struct {
 	state         protoimpl.MessageState
@@ -405,7 +964,7 @@ type UmContactInfo struct {
 func (x *UmContactInfo) Reset() {
 	*x = UmContactInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[4]
+		mi := &file_api_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -418,7 +977,7 @@ func (x *UmContactInfo) String() string {
 func (*UmContactInfo) ProtoMessage() {}
 
 func (x *UmContactInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[4]
+	mi := &file_api_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -431,7 +990,7 @@ func (x *UmContactInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactInfo.ProtoReflect.Descriptor instead.
 func (*UmContactInfo) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{4}
+	return file_api_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *UmContactInfo) GetUid() uint64 {
@@ -498,7 +1057,7 @@ type UmRegisterReq struct {
 func (x *UmRegisterReq) Reset() {
 	*x = UmRegisterReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[5]
+		mi := &file_api_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -511,7 +1070,7 @@ func (x *UmRegisterReq) String() string {
 func (*UmRegisterReq) ProtoMessage() {}
 
 func (x *UmRegisterReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[5]
+	mi := &file_api_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -524,7 +1083,7 @@ func (x *UmRegisterReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmRegisterReq.ProtoReflect.Descriptor instead.
 func (*UmRegisterReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{5}
+	return file_api_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *UmRegisterReq) GetUsername() string {
@@ -573,7 +1132,7 @@ type UmRegisterRes struct {
 func (x *UmRegisterRes) Reset() {
 	*x = UmRegisterRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[6]
+		mi := &file_api_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -586,7 +1145,7 @@ func (x *UmRegisterRes) String() string {
 func (*UmRegisterRes) ProtoMessage() {}
 
 func (x *UmRegisterRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[6]
+	mi := &file_api_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -599,7 +1158,7 @@ func (x *UmRegisterRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmRegisterRes.ProtoReflect.Descriptor instead.
 func (*UmRegisterRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UmRegisterRes) GetErrCode() ErrCode {
@@ -620,7 +1179,7 @@ type UmUnregisterReq struct {
 func (x *UmUnregisterReq) Reset() {
 	*x = UmUnregisterReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[7]
+		mi := &file_api_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -633,7 +1192,7 @@ func (x *UmUnregisterReq) String() string {
 func (*UmUnregisterReq) ProtoMessage() {}
 
 func (x *UmUnregisterReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[7]
+	mi := &file_api_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -646,7 +1205,7 @@ func (x *UmUnregisterReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmUnregisterReq.ProtoReflect.Descriptor instead.
 func (*UmUnregisterReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *UmUnregisterReq) GetSessId() string {
@@ -667,7 +1226,7 @@ type UmUnregisterRes struct {
 func (x *UmUnregisterRes) Reset() {
 	*x = UmUnregisterRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[8]
+		mi := &file_api_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -680,7 +1239,7 @@ func (x *UmUnregisterRes) String() string {
 func (*UmUnregisterRes) ProtoMessage() {}
 
 func (x *UmUnregisterRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[8]
+	mi := &file_api_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -693,7 +1252,7 @@ func (x *UmUnregisterRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmUnregisterRes.ProtoReflect.Descriptor instead.
 func (*UmUnregisterRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{8}
+	return file_api_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *UmUnregisterRes) GetErrCode() ErrCode {
@@ -712,14 +1271,14 @@ type UmUserUpdateInfoReq struct {
 	Nickname    string `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
 	Email       string `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
 	Avatar      string `protobuf:"bytes,4,opt,name=avatar,proto3" json:"avatar,omitempty"`
-	Password    string `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	Password    string `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"` // 旧密码，修改密码时必填
 	NewPassword string `protobuf:"bytes,6,opt,name=newPassword,proto3" json:"newPassword,omitempty"`
 }
 
 func (x *UmUserUpdateInfoReq) Reset() {
 	*x = UmUserUpdateInfoReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[9]
+		mi := &file_api_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -732,7 +1291,7 @@ func (x *UmUserUpdateInfoReq) String() string {
 func (*UmUserUpdateInfoReq) ProtoMessage() {}
 
 func (x *UmUserUpdateInfoReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[9]
+	mi := &file_api_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -745,7 +1304,7 @@ func (x *UmUserUpdateInfoReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmUserUpdateInfoReq.ProtoReflect.Descriptor instead.
 func (*UmUserUpdateInfoReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{9}
+	return file_api_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *UmUserUpdateInfoReq) GetSessId() string {
@@ -801,7 +1360,7 @@ type UmUserUpdateInfoRes struct {
 func (x *UmUserUpdateInfoRes) Reset() {
 	*x = UmUserUpdateInfoRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[10]
+		mi := &file_api_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -814,7 +1373,7 @@ func (x *UmUserUpdateInfoRes) String() string {
 func (*UmUserUpdateInfoRes) ProtoMessage() {}
 
 func (x *UmUserUpdateInfoRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[10]
+	mi := &file_api_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -827,7 +1386,7 @@ func (x *UmUserUpdateInfoRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmUserUpdateInfoRes.ProtoReflect.Descriptor instead.
 func (*UmUserUpdateInfoRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *UmUserUpdateInfoRes) GetErrCode() ErrCode {
@@ -848,7 +1407,7 @@ type UmContactGetListReq struct {
 func (x *UmContactGetListReq) Reset() {
 	*x = UmContactGetListReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[11]
+		mi := &file_api_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -861,7 +1420,7 @@ func (x *UmContactGetListReq) String() string {
 func (*UmContactGetListReq) ProtoMessage() {}
 
 func (x *UmContactGetListReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[11]
+	mi := &file_api_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -874,7 +1433,7 @@ func (x *UmContactGetListReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactGetListReq.ProtoReflect.Descriptor instead.
 func (*UmContactGetListReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{11}
+	return file_api_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *UmContactGetListReq) GetSessId() string {
@@ -896,7 +1455,7 @@ type UmContactGetListRes struct {
 func (x *UmContactGetListRes) Reset() {
 	*x = UmContactGetListRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[12]
+		mi := &file_api_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -909,7 +1468,7 @@ func (x *UmContactGetListRes) String() string {
 func (*UmContactGetListRes) ProtoMessage() {}
 
 func (x *UmContactGetListRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[12]
+	mi := &file_api_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -922,7 +1481,7 @@ func (x *UmContactGetListRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactGetListRes.ProtoReflect.Descriptor instead.
 func (*UmContactGetListRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{12}
+	return file_api_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *UmContactGetListRes) GetErrCode() ErrCode {
@@ -951,7 +1510,7 @@ type UmContactGetInfoReq struct {
 func (x *UmContactGetInfoReq) Reset() {
 	*x = UmContactGetInfoReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[13]
+		mi := &file_api_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -964,7 +1523,7 @@ func (x *UmContactGetInfoReq) String() string {
 func (*UmContactGetInfoReq) ProtoMessage() {}
 
 func (x *UmContactGetInfoReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[13]
+	mi := &file_api_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -977,7 +1536,7 @@ func (x *UmContactGetInfoReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactGetInfoReq.ProtoReflect.Descriptor instead.
 func (*UmContactGetInfoReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{13}
+	return file_api_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *UmContactGetInfoReq) GetSessId() string {
@@ -1006,7 +1565,7 @@ type UmContactGetInfoRes struct {
 func (x *UmContactGetInfoRes) Reset() {
 	*x = UmContactGetInfoRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[14]
+		mi := &file_api_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1019,7 +1578,7 @@ func (x *UmContactGetInfoRes) String() string {
 func (*UmContactGetInfoRes) ProtoMessage() {}
 
 func (x *UmContactGetInfoRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[14]
+	mi := &file_api_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1032,7 +1591,7 @@ func (x *UmContactGetInfoRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactGetInfoRes.ProtoReflect.Descriptor instead.
 func (*UmContactGetInfoRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{14}
+	return file_api_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *UmContactGetInfoRes) GetErrCode() ErrCode {
@@ -1061,7 +1620,7 @@ type UmContactFindReq struct {
 func (x *UmContactFindReq) Reset() {
 	*x = UmContactFindReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[15]
+		mi := &file_api_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1074,7 +1633,7 @@ func (x *UmContactFindReq) String() string {
 func (*UmContactFindReq) ProtoMessage() {}
 
 func (x *UmContactFindReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[15]
+	mi := &file_api_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1087,7 +1646,7 @@ func (x *UmContactFindReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactFindReq.ProtoReflect.Descriptor instead.
 func (*UmContactFindReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{15}
+	return file_api_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *UmContactFindReq) GetSessId() string {
@@ -1116,7 +1675,7 @@ type UmContactFindRes struct {
 func (x *UmContactFindRes) Reset() {
 	*x = UmContactFindRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[16]
+		mi := &file_api_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1129,7 +1688,7 @@ func (x *UmContactFindRes) String() string {
 func (*UmContactFindRes) ProtoMessage() {}
 
 func (x *UmContactFindRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[16]
+	mi := &file_api_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1142,7 +1701,7 @@ func (x *UmContactFindRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactFindRes.ProtoReflect.Descriptor instead.
 func (*UmContactFindRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{16}
+	return file_api_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *UmContactFindRes) GetErrCode() ErrCode {
@@ -1171,7 +1730,7 @@ type UmContactAddRequestReq struct {
 func (x *UmContactAddRequestReq) Reset() {
 	*x = UmContactAddRequestReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[17]
+		mi := &file_api_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1184,7 +1743,7 @@ func (x *UmContactAddRequestReq) String() string {
 func (*UmContactAddRequestReq) ProtoMessage() {}
 
 func (x *UmContactAddRequestReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[17]
+	mi := &file_api_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1197,7 +1756,7 @@ func (x *UmContactAddRequestReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactAddRequestReq.ProtoReflect.Descriptor instead.
 func (*UmContactAddRequestReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{17}
+	return file_api_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *UmContactAddRequestReq) GetSessId() string {
@@ -1225,7 +1784,7 @@ type UmContactAddRequestRes struct {
 func (x *UmContactAddRequestRes) Reset() {
 	*x = UmContactAddRequestRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[18]
+		mi := &file_api_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1238,7 +1797,7 @@ func (x *UmContactAddRequestRes) String() string {
 func (*UmContactAddRequestRes) ProtoMessage() {}
 
 func (x *UmContactAddRequestRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[18]
+	mi := &file_api_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1251,7 +1810,7 @@ func (x *UmContactAddRequestRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactAddRequestRes.ProtoReflect.Descriptor instead.
 func (*UmContactAddRequestRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{18}
+	return file_api_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *UmContactAddRequestRes) GetErrCode() ErrCode {
@@ -1273,7 +1832,7 @@ type UmContactAcceptReq struct {
 func (x *UmContactAcceptReq) Reset() {
 	*x = UmContactAcceptReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[19]
+		mi := &file_api_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1286,7 +1845,7 @@ func (x *UmContactAcceptReq) String() string {
 func (*UmContactAcceptReq) ProtoMessage() {}
 
 func (x *UmContactAcceptReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[19]
+	mi := &file_api_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1299,7 +1858,7 @@ func (x *UmContactAcceptReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactAcceptReq.ProtoReflect.Descriptor instead.
 func (*UmContactAcceptReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{19}
+	return file_api_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *UmContactAcceptReq) GetSessId() string {
@@ -1327,7 +1886,7 @@ type UmContactAcceptRes struct {
 func (x *UmContactAcceptRes) Reset() {
 	*x = UmContactAcceptRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[20]
+		mi := &file_api_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1340,7 +1899,7 @@ func (x *UmContactAcceptRes) String() string {
 func (*UmContactAcceptRes) ProtoMessage() {}
 
 func (x *UmContactAcceptRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[20]
+	mi := &file_api_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1353,7 +1912,7 @@ func (x *UmContactAcceptRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactAcceptRes.ProtoReflect.Descriptor instead.
 func (*UmContactAcceptRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{20}
+	return file_api_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *UmContactAcceptRes) GetErrCode() ErrCode {
@@ -1375,7 +1934,7 @@ type UmContactRejectReq struct {
 func (x *UmContactRejectReq) Reset() {
 	*x = UmContactRejectReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[21]
+		mi := &file_api_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1388,7 +1947,7 @@ func (x *UmContactRejectReq) String() string {
 func (*UmContactRejectReq) ProtoMessage() {}
 
 func (x *UmContactRejectReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[21]
+	mi := &file_api_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1401,7 +1960,7 @@ func (x *UmContactRejectReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactRejectReq.ProtoReflect.Descriptor instead.
 func (*UmContactRejectReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{21}
+	return file_api_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *UmContactRejectReq) GetSessId() string {
@@ -1429,7 +1988,7 @@ type UmContactRejectRes struct {
 func (x *UmContactRejectRes) Reset() {
 	*x = UmContactRejectRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[22]
+		mi := &file_api_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1442,7 +2001,7 @@ func (x *UmContactRejectRes) String() string {
 func (*UmContactRejectRes) ProtoMessage() {}
 
 func (x *UmContactRejectRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[22]
+	mi := &file_api_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1455,7 +2014,7 @@ func (x *UmContactRejectRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactRejectRes.ProtoReflect.Descriptor instead.
 func (*UmContactRejectRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{22}
+	return file_api_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *UmContactRejectRes) GetErrCode() ErrCode {
@@ -1477,7 +2036,7 @@ type UmContactDelReq struct {
 func (x *UmContactDelReq) Reset() {
 	*x = UmContactDelReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[23]
+		mi := &file_api_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1490,7 +2049,7 @@ func (x *UmContactDelReq) String() string {
 func (*UmContactDelReq) ProtoMessage() {}
 
 func (x *UmContactDelReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[23]
+	mi := &file_api_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1503,7 +2062,7 @@ func (x *UmContactDelReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactDelReq.ProtoReflect.Descriptor instead.
 func (*UmContactDelReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{23}
+	return file_api_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *UmContactDelReq) GetSessId() string {
@@ -1531,7 +2090,7 @@ type UmContactDelRes struct {
 func (x *UmContactDelRes) Reset() {
 	*x = UmContactDelRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[24]
+		mi := &file_api_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1544,7 +2103,7 @@ func (x *UmContactDelRes) String() string {
 func (*UmContactDelRes) ProtoMessage() {}
 
 func (x *UmContactDelRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[24]
+	mi := &file_api_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1557,7 +2116,7 @@ func (x *UmContactDelRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmContactDelRes.ProtoReflect.Descriptor instead.
 func (*UmContactDelRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{24}
+	return file_api_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *UmContactDelRes) GetErrCode() ErrCode {
@@ -1583,7 +2142,7 @@ type UmGroupInfo struct {
 func (x *UmGroupInfo) Reset() {
 	*x = UmGroupInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[25]
+		mi := &file_api_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1596,7 +2155,7 @@ func (x *UmGroupInfo) String() string {
 func (*UmGroupInfo) ProtoMessage() {}
 
 func (x *UmGroupInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[25]
+	mi := &file_api_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1609,7 +2168,7 @@ func (x *UmGroupInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupInfo.ProtoReflect.Descriptor instead.
 func (*UmGroupInfo) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{25}
+	return file_api_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *UmGroupInfo) GetGroupId() uint64 {
@@ -1665,7 +2224,7 @@ type UmGroupGetListReq struct {
 func (x *UmGroupGetListReq) Reset() {
 	*x = UmGroupGetListReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[26]
+		mi := &file_api_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1678,7 +2237,7 @@ func (x *UmGroupGetListReq) String() string {
 func (*UmGroupGetListReq) ProtoMessage() {}
 
 func (x *UmGroupGetListReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[26]
+	mi := &file_api_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1691,7 +2250,7 @@ func (x *UmGroupGetListReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupGetListReq.ProtoReflect.Descriptor instead.
 func (*UmGroupGetListReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{26}
+	return file_api_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *UmGroupGetListReq) GetSessId() string {
@@ -1713,7 +2272,7 @@ type UmGroupGetListRes struct {
 func (x *UmGroupGetListRes) Reset() {
 	*x = UmGroupGetListRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[27]
+		mi := &file_api_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1726,7 +2285,7 @@ func (x *UmGroupGetListRes) String() string {
 func (*UmGroupGetListRes) ProtoMessage() {}
 
 func (x *UmGroupGetListRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[27]
+	mi := &file_api_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1739,7 +2298,7 @@ func (x *UmGroupGetListRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupGetListRes.ProtoReflect.Descriptor instead.
 func (*UmGroupGetListRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{27}
+	return file_api_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *UmGroupGetListRes) GetErrCode() ErrCode {
@@ -1768,7 +2327,7 @@ type UmGroupGetInfoReq struct {
 func (x *UmGroupGetInfoReq) Reset() {
 	*x = UmGroupGetInfoReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[28]
+		mi := &file_api_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1781,7 +2340,7 @@ func (x *UmGroupGetInfoReq) String() string {
 func (*UmGroupGetInfoReq) ProtoMessage() {}
 
 func (x *UmGroupGetInfoReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[28]
+	mi := &file_api_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1794,7 +2353,7 @@ func (x *UmGroupGetInfoReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupGetInfoReq.ProtoReflect.Descriptor instead.
 func (*UmGroupGetInfoReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{28}
+	return file_api_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *UmGroupGetInfoReq) GetSessId() string {
@@ -1823,7 +2382,7 @@ type UmGroupGetInfoRes struct {
 func (x *UmGroupGetInfoRes) Reset() {
 	*x = UmGroupGetInfoRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[29]
+		mi := &file_api_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1836,7 +2395,7 @@ func (x *UmGroupGetInfoRes) String() string {
 func (*UmGroupGetInfoRes) ProtoMessage() {}
 
 func (x *UmGroupGetInfoRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[29]
+	mi := &file_api_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1849,7 +2408,7 @@ func (x *UmGroupGetInfoRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupGetInfoRes.ProtoReflect.Descriptor instead.
 func (*UmGroupGetInfoRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{29}
+	return file_api_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *UmGroupGetInfoRes) GetErrCode() ErrCode {
@@ -1880,7 +2439,7 @@ type UmGroupUpdateInfoReq struct {
 func (x *UmGroupUpdateInfoReq) Reset() {
 	*x = UmGroupUpdateInfoReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[30]
+		mi := &file_api_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1893,7 +2452,7 @@ func (x *UmGroupUpdateInfoReq) String() string {
 func (*UmGroupUpdateInfoReq) ProtoMessage() {}
 
 func (x *UmGroupUpdateInfoReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[30]
+	mi := &file_api_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1906,7 +2465,7 @@ func (x *UmGroupUpdateInfoReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupUpdateInfoReq.ProtoReflect.Descriptor instead.
 func (*UmGroupUpdateInfoReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{30}
+	return file_api_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *UmGroupUpdateInfoReq) GetSessId() string {
@@ -1948,7 +2507,7 @@ type UmGroupUpdateInfoRes struct {
 func (x *UmGroupUpdateInfoRes) Reset() {
 	*x = UmGroupUpdateInfoRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[31]
+		mi := &file_api_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1961,7 +2520,7 @@ func (x *UmGroupUpdateInfoRes) String() string {
 func (*UmGroupUpdateInfoRes) ProtoMessage() {}
 
 func (x *UmGroupUpdateInfoRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[31]
+	mi := &file_api_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1974,7 +2533,7 @@ func (x *UmGroupUpdateInfoRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupUpdateInfoRes.ProtoReflect.Descriptor instead.
 func (*UmGroupUpdateInfoRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{31}
+	return file_api_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *UmGroupUpdateInfoRes) GetErrCode() ErrCode {
@@ -1996,7 +2555,7 @@ type UmGroupFindReq struct {
 func (x *UmGroupFindReq) Reset() {
 	*x = UmGroupFindReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[32]
+		mi := &file_api_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2009,7 +2568,7 @@ func (x *UmGroupFindReq) String() string {
 func (*UmGroupFindReq) ProtoMessage() {}
 
 func (x *UmGroupFindReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[32]
+	mi := &file_api_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2022,7 +2581,7 @@ func (x *UmGroupFindReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupFindReq.ProtoReflect.Descriptor instead.
 func (*UmGroupFindReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{32}
+	return file_api_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *UmGroupFindReq) GetSessId() string {
@@ -2051,7 +2610,7 @@ type UmGroupFindRes struct {
 func (x *UmGroupFindRes) Reset() {
 	*x = UmGroupFindRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[33]
+		mi := &file_api_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2064,7 +2623,7 @@ func (x *UmGroupFindRes) String() string {
 func (*UmGroupFindRes) ProtoMessage() {}
 
 func (x *UmGroupFindRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[33]
+	mi := &file_api_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2077,7 +2636,7 @@ func (x *UmGroupFindRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupFindRes.ProtoReflect.Descriptor instead.
 func (*UmGroupFindRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{33}
+	return file_api_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *UmGroupFindRes) GetErrCode() ErrCode {
@@ -2106,7 +2665,7 @@ type UmGroupCreateReq struct {
 func (x *UmGroupCreateReq) Reset() {
 	*x = UmGroupCreateReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[34]
+		mi := &file_api_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2119,7 +2678,7 @@ func (x *UmGroupCreateReq) String() string {
 func (*UmGroupCreateReq) ProtoMessage() {}
 
 func (x *UmGroupCreateReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[34]
+	mi := &file_api_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2132,7 +2691,7 @@ func (x *UmGroupCreateReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupCreateReq.ProtoReflect.Descriptor instead.
 func (*UmGroupCreateReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{34}
+	return file_api_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *UmGroupCreateReq) GetSessId() string {
@@ -2161,7 +2720,7 @@ type UmGroupCreateRes struct {
 func (x *UmGroupCreateRes) Reset() {
 	*x = UmGroupCreateRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[35]
+		mi := &file_api_proto_msgTypes[44]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2174,7 +2733,7 @@ func (x *UmGroupCreateRes) String() string {
 func (*UmGroupCreateRes) ProtoMessage() {}
 
 func (x *UmGroupCreateRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[35]
+	mi := &file_api_proto_msgTypes[44]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2187,7 +2746,7 @@ func (x *UmGroupCreateRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupCreateRes.ProtoReflect.Descriptor instead.
 func (*UmGroupCreateRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{35}
+	return file_api_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *UmGroupCreateRes) GetErrCode() ErrCode {
@@ -2216,7 +2775,7 @@ type UmGroupDeleteReq struct {
 func (x *UmGroupDeleteReq) Reset() {
 	*x = UmGroupDeleteReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[36]
+		mi := &file_api_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2229,7 +2788,7 @@ func (x *UmGroupDeleteReq) String() string {
 func (*UmGroupDeleteReq) ProtoMessage() {}
 
 func (x *UmGroupDeleteReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[36]
+	mi := &file_api_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2242,7 +2801,7 @@ func (x *UmGroupDeleteReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupDeleteReq.ProtoReflect.Descriptor instead.
 func (*UmGroupDeleteReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{36}
+	return file_api_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *UmGroupDeleteReq) GetSessId() string {
@@ -2270,7 +2829,7 @@ type UmGroupDeleteRes struct {
 func (x *UmGroupDeleteRes) Reset() {
 	*x = UmGroupDeleteRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[37]
+		mi := &file_api_proto_msgTypes[46]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2283,7 +2842,7 @@ func (x *UmGroupDeleteRes) String() string {
 func (*UmGroupDeleteRes) ProtoMessage() {}
 
 func (x *UmGroupDeleteRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[37]
+	mi := &file_api_proto_msgTypes[46]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2296,7 +2855,7 @@ func (x *UmGroupDeleteRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupDeleteRes.ProtoReflect.Descriptor instead.
 func (*UmGroupDeleteRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{37}
+	return file_api_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *UmGroupDeleteRes) GetErrCode() ErrCode {
@@ -2318,7 +2877,7 @@ type UmGroupGetMemListReq struct {
 func (x *UmGroupGetMemListReq) Reset() {
 	*x = UmGroupGetMemListReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[38]
+		mi := &file_api_proto_msgTypes[47]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2331,7 +2890,7 @@ func (x *UmGroupGetMemListReq) String() string {
 func (*UmGroupGetMemListReq) ProtoMessage() {}
 
 func (x *UmGroupGetMemListReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[38]
+	mi := &file_api_proto_msgTypes[47]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2344,7 +2903,7 @@ func (x *UmGroupGetMemListReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupGetMemListReq.ProtoReflect.Descriptor instead.
 func (*UmGroupGetMemListReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{38}
+	return file_api_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *UmGroupGetMemListReq) GetSessId() string {
@@ -2373,7 +2932,7 @@ type UmGroupGetMemListRes struct {
 func (x *UmGroupGetMemListRes) Reset() {
 	*x = UmGroupGetMemListRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[39]
+		mi := &file_api_proto_msgTypes[48]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2386,7 +2945,7 @@ func (x *UmGroupGetMemListRes) String() string {
 func (*UmGroupGetMemListRes) ProtoMessage() {}
 
 func (x *UmGroupGetMemListRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[39]
+	mi := &file_api_proto_msgTypes[48]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2399,7 +2958,7 @@ func (x *UmGroupGetMemListRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupGetMemListRes.ProtoReflect.Descriptor instead.
 func (*UmGroupGetMemListRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{39}
+	return file_api_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *UmGroupGetMemListRes) GetErrCode() ErrCode {
@@ -2428,7 +2987,7 @@ type UmGroupJoinRequestReq struct {
 func (x *UmGroupJoinRequestReq) Reset() {
 	*x = UmGroupJoinRequestReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[40]
+		mi := &file_api_proto_msgTypes[49]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2441,7 +3000,7 @@ func (x *UmGroupJoinRequestReq) String() string {
 func (*UmGroupJoinRequestReq) ProtoMessage() {}
 
 func (x *UmGroupJoinRequestReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[40]
+	mi := &file_api_proto_msgTypes[49]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2454,7 +3013,7 @@ func (x *UmGroupJoinRequestReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupJoinRequestReq.ProtoReflect.Descriptor instead.
 func (*UmGroupJoinRequestReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{40}
+	return file_api_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *UmGroupJoinRequestReq) GetSessId() string {
@@ -2482,7 +3041,7 @@ type UmGroupJoinRequestRes struct {
 func (x *UmGroupJoinRequestRes) Reset() {
 	*x = UmGroupJoinRequestRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[41]
+		mi := &file_api_proto_msgTypes[50]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2495,7 +3054,7 @@ func (x *UmGroupJoinRequestRes) String() string {
 func (*UmGroupJoinRequestRes) ProtoMessage() {}
 
 func (x *UmGroupJoinRequestRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[41]
+	mi := &file_api_proto_msgTypes[50]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2508,7 +3067,7 @@ func (x *UmGroupJoinRequestRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupJoinRequestRes.ProtoReflect.Descriptor instead.
 func (*UmGroupJoinRequestRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{41}
+	return file_api_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *UmGroupJoinRequestRes) GetErrCode() ErrCode {
@@ -2531,7 +3090,7 @@ type UmGroupAcceptReq struct {
 func (x *UmGroupAcceptReq) Reset() {
 	*x = UmGroupAcceptReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[42]
+		mi := &file_api_proto_msgTypes[51]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2544,7 +3103,7 @@ func (x *UmGroupAcceptReq) String() string {
 func (*UmGroupAcceptReq) ProtoMessage() {}
 
 func (x *UmGroupAcceptReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[42]
+	mi := &file_api_proto_msgTypes[51]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2557,7 +3116,7 @@ func (x *UmGroupAcceptReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupAcceptReq.ProtoReflect.Descriptor instead.
 func (*UmGroupAcceptReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{42}
+	return file_api_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *UmGroupAcceptReq) GetSessId() string {
@@ -2592,7 +3151,7 @@ type UmGroupAcceptRes struct {
 func (x *UmGroupAcceptRes) Reset() {
 	*x = UmGroupAcceptRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[43]
+		mi := &file_api_proto_msgTypes[52]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2605,7 +3164,7 @@ func (x *UmGroupAcceptRes) String() string {
 func (*UmGroupAcceptRes) ProtoMessage() {}
 
 func (x *UmGroupAcceptRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[43]
+	mi := &file_api_proto_msgTypes[52]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2618,7 +3177,7 @@ func (x *UmGroupAcceptRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupAcceptRes.ProtoReflect.Descriptor instead.
 func (*UmGroupAcceptRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{43}
+	return file_api_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *UmGroupAcceptRes) GetErrCode() ErrCode {
@@ -2641,7 +3200,7 @@ type UmGroupRejectReq struct {
 func (x *UmGroupRejectReq) Reset() {
 	*x = UmGroupRejectReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[44]
+		mi := &file_api_proto_msgTypes[53]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2654,7 +3213,7 @@ func (x *UmGroupRejectReq) String() string {
 func (*UmGroupRejectReq) ProtoMessage() {}
 
 func (x *UmGroupRejectReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[44]
+	mi := &file_api_proto_msgTypes[53]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2667,7 +3226,7 @@ func (x *UmGroupRejectReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupRejectReq.ProtoReflect.Descriptor instead.
 func (*UmGroupRejectReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{44}
+	return file_api_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *UmGroupRejectReq) GetSessId() string {
@@ -2702,7 +3261,7 @@ type UmGroupRejectRes struct {
 func (x *UmGroupRejectRes) Reset() {
 	*x = UmGroupRejectRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[45]
+		mi := &file_api_proto_msgTypes[54]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2715,7 +3274,7 @@ func (x *UmGroupRejectRes) String() string {
 func (*UmGroupRejectRes) ProtoMessage() {}
 
 func (x *UmGroupRejectRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[45]
+	mi := &file_api_proto_msgTypes[54]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2728,7 +3287,7 @@ func (x *UmGroupRejectRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupRejectRes.ProtoReflect.Descriptor instead.
 func (*UmGroupRejectRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{45}
+	return file_api_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *UmGroupRejectRes) GetErrCode() ErrCode {
@@ -2750,7 +3309,7 @@ type UmGroupLeaveReq struct {
 func (x *UmGroupLeaveReq) Reset() {
 	*x = UmGroupLeaveReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[46]
+		mi := &file_api_proto_msgTypes[55]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2763,7 +3322,7 @@ func (x *UmGroupLeaveReq) String() string {
 func (*UmGroupLeaveReq) ProtoMessage() {}
 
 func (x *UmGroupLeaveReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[46]
+	mi := &file_api_proto_msgTypes[55]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2776,7 +3335,7 @@ func (x *UmGroupLeaveReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupLeaveReq.ProtoReflect.Descriptor instead.
 func (*UmGroupLeaveReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{46}
+	return file_api_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *UmGroupLeaveReq) GetSessId() string {
@@ -2804,7 +3363,7 @@ type UmGroupLeaveRes struct {
 func (x *UmGroupLeaveRes) Reset() {
 	*x = UmGroupLeaveRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[47]
+		mi := &file_api_proto_msgTypes[56]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2817,7 +3376,7 @@ func (x *UmGroupLeaveRes) String() string {
 func (*UmGroupLeaveRes) ProtoMessage() {}
 
 func (x *UmGroupLeaveRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[47]
+	mi := &file_api_proto_msgTypes[56]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2830,7 +3389,7 @@ func (x *UmGroupLeaveRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupLeaveRes.ProtoReflect.Descriptor instead.
 func (*UmGroupLeaveRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{47}
+	return file_api_proto_rawDescGZIP(), []int{56}
 }
 
 func (x *UmGroupLeaveRes) GetErrCode() ErrCode {
@@ -2853,7 +3412,7 @@ type UmGroupAddMemReq struct {
 func (x *UmGroupAddMemReq) Reset() {
 	*x = UmGroupAddMemReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[48]
+		mi := &file_api_proto_msgTypes[57]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2866,7 +3425,7 @@ func (x *UmGroupAddMemReq) String() string {
 func (*UmGroupAddMemReq) ProtoMessage() {}
 
 func (x *UmGroupAddMemReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[48]
+	mi := &file_api_proto_msgTypes[57]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2879,7 +3438,7 @@ func (x *UmGroupAddMemReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupAddMemReq.ProtoReflect.Descriptor instead.
 func (*UmGroupAddMemReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{48}
+	return file_api_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *UmGroupAddMemReq) GetSessId() string {
@@ -2914,7 +3473,7 @@ type UmGroupAddMemRes struct {
 func (x *UmGroupAddMemRes) Reset() {
 	*x = UmGroupAddMemRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[49]
+		mi := &file_api_proto_msgTypes[58]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2927,7 +3486,7 @@ func (x *UmGroupAddMemRes) String() string {
 func (*UmGroupAddMemRes) ProtoMessage() {}
 
 func (x *UmGroupAddMemRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[49]
+	mi := &file_api_proto_msgTypes[58]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2940,7 +3499,7 @@ func (x *UmGroupAddMemRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupAddMemRes.ProtoReflect.Descriptor instead.
 func (*UmGroupAddMemRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{49}
+	return file_api_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *UmGroupAddMemRes) GetErrCode() ErrCode {
@@ -2963,7 +3522,7 @@ type UmGroupDelMemReq struct {
 func (x *UmGroupDelMemReq) Reset() {
 	*x = UmGroupDelMemReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[50]
+		mi := &file_api_proto_msgTypes[59]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2976,7 +3535,7 @@ func (x *UmGroupDelMemReq) String() string {
 func (*UmGroupDelMemReq) ProtoMessage() {}
 
 func (x *UmGroupDelMemReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[50]
+	mi := &file_api_proto_msgTypes[59]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2989,7 +3548,7 @@ func (x *UmGroupDelMemReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupDelMemReq.ProtoReflect.Descriptor instead.
 func (*UmGroupDelMemReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{50}
+	return file_api_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *UmGroupDelMemReq) GetSessId() string {
@@ -3024,7 +3583,7 @@ type UmGroupDelMemRes struct {
 func (x *UmGroupDelMemRes) Reset() {
 	*x = UmGroupDelMemRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[51]
+		mi := &file_api_proto_msgTypes[60]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3037,7 +3596,7 @@ func (x *UmGroupDelMemRes) String() string {
 func (*UmGroupDelMemRes) ProtoMessage() {}
 
 func (x *UmGroupDelMemRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[51]
+	mi := &file_api_proto_msgTypes[60]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3050,7 +3609,7 @@ func (x *UmGroupDelMemRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupDelMemRes.ProtoReflect.Descriptor instead.
 func (*UmGroupDelMemRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{51}
+	return file_api_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *UmGroupDelMemRes) GetErrCode() ErrCode {
@@ -3074,7 +3633,7 @@ type UmGroupUpdateMemReq struct {
 func (x *UmGroupUpdateMemReq) Reset() {
 	*x = UmGroupUpdateMemReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[52]
+		mi := &file_api_proto_msgTypes[61]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3087,7 +3646,7 @@ func (x *UmGroupUpdateMemReq) String() string {
 func (*UmGroupUpdateMemReq) ProtoMessage() {}
 
 func (x *UmGroupUpdateMemReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[52]
+	mi := &file_api_proto_msgTypes[61]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3100,7 +3659,7 @@ func (x *UmGroupUpdateMemReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupUpdateMemReq.ProtoReflect.Descriptor instead.
 func (*UmGroupUpdateMemReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{52}
+	return file_api_proto_rawDescGZIP(), []int{61}
 }
 
 func (x *UmGroupUpdateMemReq) GetSessId() string {
@@ -3142,7 +3701,7 @@ type UmGroupUpdateMemRes struct {
 func (x *UmGroupUpdateMemRes) Reset() {
 	*x = UmGroupUpdateMemRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[53]
+		mi := &file_api_proto_msgTypes[62]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3155,7 +3714,7 @@ func (x *UmGroupUpdateMemRes) String() string {
 func (*UmGroupUpdateMemRes) ProtoMessage() {}
 
 func (x *UmGroupUpdateMemRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[53]
+	mi := &file_api_proto_msgTypes[62]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3168,7 +3727,7 @@ func (x *UmGroupUpdateMemRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UmGroupUpdateMemRes.ProtoReflect.Descriptor instead.
 func (*UmGroupUpdateMemRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{53}
+	return file_api_proto_rawDescGZIP(), []int{62}
 }
 
 func (x *UmGroupUpdateMemRes) GetErrCode() ErrCode {
@@ -3194,7 +3753,7 @@ type ChatPeerId struct {
 func (x *ChatPeerId) Reset() {
 	*x = ChatPeerId{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[54]
+		mi := &file_api_proto_msgTypes[63]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3207,7 +3766,7 @@ func (x *ChatPeerId) String() string {
 func (*ChatPeerId) ProtoMessage() {}
 
 func (x *ChatPeerId) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[54]
+	mi := &file_api_proto_msgTypes[63]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3220,7 +3779,7 @@ func (x *ChatPeerId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatPeerId.ProtoReflect.Descriptor instead.
 func (*ChatPeerId) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{54}
+	return file_api_proto_rawDescGZIP(), []int{63}
 }
 
 func (m *ChatPeerId) GetPeerIdUnion() isChatPeerId_PeerIdUnion {
@@ -3275,7 +3834,7 @@ type ChatMsg struct {
 func (x *ChatMsg) Reset() {
 	*x = ChatMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[55]
+		mi := &file_api_proto_msgTypes[64]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3288,7 +3847,7 @@ func (x *ChatMsg) String() string {
 func (*ChatMsg) ProtoMessage() {}
 
 func (x *ChatMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[55]
+	mi := &file_api_proto_msgTypes[64]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3301,7 +3860,7 @@ func (x *ChatMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatMsg.ProtoReflect.Descriptor instead.
 func (*ChatMsg) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{55}
+	return file_api_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *ChatMsg) GetSenderUid() uint64 {
@@ -3356,7 +3915,7 @@ type ChatConvMsg struct {
 func (x *ChatConvMsg) Reset() {
 	*x = ChatConvMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[56]
+		mi := &file_api_proto_msgTypes[65]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3369,7 +3928,7 @@ func (x *ChatConvMsg) String() string {
 func (*ChatConvMsg) ProtoMessage() {}
 
 func (x *ChatConvMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[56]
+	mi := &file_api_proto_msgTypes[65]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3382,7 +3941,7 @@ func (x *ChatConvMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatConvMsg.ProtoReflect.Descriptor instead.
 func (*ChatConvMsg) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{56}
+	return file_api_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *ChatConvMsg) GetSeqId() uint64 {
@@ -3446,7 +4005,7 @@ type ChatConvInfo struct {
 func (x *ChatConvInfo) Reset() {
 	*x = ChatConvInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[57]
+		mi := &file_api_proto_msgTypes[66]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3459,7 +4018,7 @@ func (x *ChatConvInfo) String() string {
 func (*ChatConvInfo) ProtoMessage() {}
 
 func (x *ChatConvInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[57]
+	mi := &file_api_proto_msgTypes[66]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3472,7 +4031,7 @@ func (x *ChatConvInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatConvInfo.ProtoReflect.Descriptor instead.
 func (*ChatConvInfo) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{57}
+	return file_api_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *ChatConvInfo) GetConvId() uint64 {
@@ -3501,7 +4060,7 @@ type ChatSendMsgReq struct {
 func (x *ChatSendMsgReq) Reset() {
 	*x = ChatSendMsgReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[58]
+		mi := &file_api_proto_msgTypes[67]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3514,7 +4073,7 @@ func (x *ChatSendMsgReq) String() string {
 func (*ChatSendMsgReq) ProtoMessage() {}
 
 func (x *ChatSendMsgReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[58]
+	mi := &file_api_proto_msgTypes[67]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3527,7 +4086,7 @@ func (x *ChatSendMsgReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatSendMsgReq.ProtoReflect.Descriptor instead.
 func (*ChatSendMsgReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{58}
+	return file_api_proto_rawDescGZIP(), []int{67}
 }
 
 func (x *ChatSendMsgReq) GetSessId() string {
@@ -3555,7 +4114,7 @@ type ChatSendMsgRes struct {
 func (x *ChatSendMsgRes) Reset() {
 	*x = ChatSendMsgRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[59]
+		mi := &file_api_proto_msgTypes[68]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3568,7 +4127,7 @@ func (x *ChatSendMsgRes) String() string {
 func (*ChatSendMsgRes) ProtoMessage() {}
 
 func (x *ChatSendMsgRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[59]
+	mi := &file_api_proto_msgTypes[68]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3581,7 +4140,7 @@ func (x *ChatSendMsgRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatSendMsgRes.ProtoReflect.Descriptor instead.
 func (*ChatSendMsgRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{59}
+	return file_api_proto_rawDescGZIP(), []int{68}
 }
 
 func (x *ChatSendMsgRes) GetErrCode() ErrCode {
@@ -3604,7 +4163,7 @@ type ChatMarkReadReq struct {
 func (x *ChatMarkReadReq) Reset() {
 	*x = ChatMarkReadReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[60]
+		mi := &file_api_proto_msgTypes[69]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3617,7 +4176,7 @@ func (x *ChatMarkReadReq) String() string {
 func (*ChatMarkReadReq) ProtoMessage() {}
 
 func (x *ChatMarkReadReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[60]
+	mi := &file_api_proto_msgTypes[69]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3630,7 +4189,7 @@ func (x *ChatMarkReadReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatMarkReadReq.ProtoReflect.Descriptor instead.
 func (*ChatMarkReadReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{60}
+	return file_api_proto_rawDescGZIP(), []int{69}
 }
 
 func (x *ChatMarkReadReq) GetSessId() string {
@@ -3665,7 +4224,7 @@ type ChatMarkReadRes struct {
 func (x *ChatMarkReadRes) Reset() {
 	*x = ChatMarkReadRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[61]
+		mi := &file_api_proto_msgTypes[70]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3678,7 +4237,7 @@ func (x *ChatMarkReadRes) String() string {
 func (*ChatMarkReadRes) ProtoMessage() {}
 
 func (x *ChatMarkReadRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[61]
+	mi := &file_api_proto_msgTypes[70]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3691,7 +4250,7 @@ func (x *ChatMarkReadRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatMarkReadRes.ProtoReflect.Descriptor instead.
 func (*ChatMarkReadRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{61}
+	return file_api_proto_rawDescGZIP(), []int{70}
 }
 
 func (x *ChatMarkReadRes) GetErrCode() ErrCode {
@@ -3701,6 +4260,141 @@ func (x *ChatMarkReadRes) GetErrCode() ErrCode {
 	return ErrCode_emErrCode_Ok
 }
 
+// 向前翻页读取一个会话的历史消息
+type ChatGetHistoryReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessId      string      `protobuf:"bytes,1,opt,name=sessId,proto3" json:"sessId,omitempty"`
+	PeerId      *ChatPeerId `protobuf:"bytes,2,opt,name=peerId,proto3" json:"peerId,omitempty"`
+	BeforeSeqId uint64      `protobuf:"varint,3,opt,name=beforeSeqId,proto3" json:"beforeSeqId,omitempty"` // 返回 seqId 小于此值的消息，为 0 时从最新一条开始
+	Limit       uint32      `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`             // 为 0 时使用默认值 20，最多 100
+}
+
+func (x *ChatGetHistoryReq) Reset() {
+	*x = ChatGetHistoryReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatGetHistoryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatGetHistoryReq) ProtoMessage() {}
+
+func (x *ChatGetHistoryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatGetHistoryReq.ProtoReflect.Descriptor instead.
+func (*ChatGetHistoryReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ChatGetHistoryReq) GetSessId() string {
+	if x != nil {
+		return x.SessId
+	}
+	return ""
+}
+
+func (x *ChatGetHistoryReq) GetPeerId() *ChatPeerId {
+	if x != nil {
+		return x.PeerId
+	}
+	return nil
+}
+
+func (x *ChatGetHistoryReq) GetBeforeSeqId() uint64 {
+	if x != nil {
+		return x.BeforeSeqId
+	}
+	return 0
+}
+
+func (x *ChatGetHistoryReq) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ChatGetHistoryRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrCode ErrCode        `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
+	MsgList []*ChatConvMsg `protobuf:"bytes,2,rep,name=msgList,proto3" json:"msgList,omitempty"`  // 按 seqId 升序，下一页以第一条的 seqId 作为 beforeSeqId
+	HasMore bool           `protobuf:"varint,3,opt,name=hasMore,proto3" json:"hasMore,omitempty"` // 是否还有更早的消息
+}
+
+func (x *ChatGetHistoryRes) Reset() {
+	*x = ChatGetHistoryRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatGetHistoryRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatGetHistoryRes) ProtoMessage() {}
+
+func (x *ChatGetHistoryRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatGetHistoryRes.ProtoReflect.Descriptor instead.
+func (*ChatGetHistoryRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *ChatGetHistoryRes) GetErrCode() ErrCode {
+	if x != nil {
+		return x.ErrCode
+	}
+	return ErrCode_emErrCode_Ok
+}
+
+func (x *ChatGetHistoryRes) GetMsgList() []*ChatConvMsg {
+	if x != nil {
+		return x.MsgList
+	}
+	return nil
+}
+
+func (x *ChatGetHistoryRes) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
 type GetUpdateListReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -3708,12 +4402,13 @@ type GetUpdateListReq struct {
 
 	SessId     string `protobuf:"bytes,1,opt,name=sessId,proto3" json:"sessId,omitempty"`
 	LocalSeqId uint64 `protobuf:"varint,2,opt,name=localSeqId,proto3" json:"localSeqId,omitempty"`
+	MaxCount   uint32 `protobuf:"varint,3,opt,name=maxCount,proto3" json:"maxCount,omitempty"` // 每页最多返回的消息数，为 0 或超过上限 500 时按上限返回
 }
 
 func (x *GetUpdateListReq) Reset() {
 	*x = GetUpdateListReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[62]
+		mi := &file_api_proto_msgTypes[73]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3726,7 +4421,7 @@ func (x *GetUpdateListReq) String() string {
 func (*GetUpdateListReq) ProtoMessage() {}
 
 func (x *GetUpdateListReq) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[62]
+	mi := &file_api_proto_msgTypes[73]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3739,7 +4434,7 @@ func (x *GetUpdateListReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUpdateListReq.ProtoReflect.Descriptor instead.
 func (*GetUpdateListReq) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{62}
+	return file_api_proto_rawDescGZIP(), []int{73}
 }
 
 func (x *GetUpdateListReq) GetSessId() string {
@@ -3756,20 +4451,28 @@ func (x *GetUpdateListReq) GetLocalSeqId() uint64 {
 	return 0
 }
 
+func (x *GetUpdateListReq) GetMaxCount() uint32 {
+	if x != nil {
+		return x.MaxCount
+	}
+	return 0
+}
+
 type GetUpdateListRes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	ErrCode ErrCode        `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
-	SeqId   uint64         `protobuf:"varint,2,opt,name=seqId,proto3" json:"seqId,omitempty"`
+	SeqId   uint64         `protobuf:"varint,2,opt,name=seqId,proto3" json:"seqId,omitempty"` // 下一页的 localSeqId
 	MsgList []*ChatConvMsg `protobuf:"bytes,3,rep,name=msgList,proto3" json:"msgList,omitempty"`
+	HasMore bool           `protobuf:"varint,4,opt,name=hasMore,proto3" json:"hasMore,omitempty"` // 为 true 时应立即以 seqId 继续拉取
 }
 
 func (x *GetUpdateListRes) Reset() {
 	*x = GetUpdateListRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_msgTypes[63]
+		mi := &file_api_proto_msgTypes[74]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3782,7 +4485,7 @@ func (x *GetUpdateListRes) String() string {
 func (*GetUpdateListRes) ProtoMessage() {}
 
 func (x *GetUpdateListRes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[63]
+	mi := &file_api_proto_msgTypes[74]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3795,7 +4498,7 @@ func (x *GetUpdateListRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUpdateListRes.ProtoReflect.Descriptor instead.
 func (*GetUpdateListRes) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{63}
+	return file_api_proto_rawDescGZIP(), []int{74}
 }
 
 func (x *GetUpdateListRes) GetErrCode() ErrCode {
@@ -3819,26 +4522,241 @@ func (x *GetUpdateListRes) GetMsgList() []*ChatConvMsg {
 	return nil
 }
 
+func (x *GetUpdateListRes) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// 订阅更新事件。有新消息时立即推送，空闲时定期推送心跳
+type SubscribeReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessId             string `protobuf:"bytes,1,opt,name=sessId,proto3" json:"sessId,omitempty"`
+	LocalSeqId         uint64 `protobuf:"varint,2,opt,name=localSeqId,proto3" json:"localSeqId,omitempty"`                 // 从此 seqId 之后开始推送
+	HeartbeatIntervalS uint32 `protobuf:"varint,3,opt,name=heartbeatIntervalS,proto3" json:"heartbeatIntervalS,omitempty"` // 心跳间隔，为 0 时使用服务端默认值
+}
+
+func (x *SubscribeReq) Reset() {
+	*x = SubscribeReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeReq) ProtoMessage() {}
+
+func (x *SubscribeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeReq.ProtoReflect.Descriptor instead.
+func (*SubscribeReq) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *SubscribeReq) GetSessId() string {
+	if x != nil {
+		return x.SessId
+	}
+	return ""
+}
+
+func (x *SubscribeReq) GetLocalSeqId() uint64 {
+	if x != nil {
+		return x.LocalSeqId
+	}
+	return 0
+}
+
+func (x *SubscribeReq) GetHeartbeatIntervalS() uint32 {
+	if x != nil {
+		return x.HeartbeatIntervalS
+	}
+	return 0
+}
+
+type SubscribeRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrCode     ErrCode        `protobuf:"varint,1,opt,name=errCode,proto3,enum=gen_grpc.ErrCode" json:"errCode,omitempty"`
+	SeqId       uint64         `protobuf:"varint,2,opt,name=seqId,proto3" json:"seqId,omitempty"`
+	MsgList     []*ChatConvMsg `protobuf:"bytes,3,rep,name=msgList,proto3" json:"msgList,omitempty"` // 每次最多 500 条，积压的消息分多次推送
+	IsHeartbeat bool           `protobuf:"varint,4,opt,name=isHeartbeat,proto3" json:"isHeartbeat,omitempty"`
+	HasMore     bool           `protobuf:"varint,5,opt,name=hasMore,proto3" json:"hasMore,omitempty"` // 为 true 时后面紧接着推送剩余消息
+}
+
+func (x *SubscribeRes) Reset() {
+	*x = SubscribeRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRes) ProtoMessage() {}
+
+func (x *SubscribeRes) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRes.ProtoReflect.Descriptor instead.
+func (*SubscribeRes) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *SubscribeRes) GetErrCode() ErrCode {
+	if x != nil {
+		return x.ErrCode
+	}
+	return ErrCode_emErrCode_Ok
+}
+
+func (x *SubscribeRes) GetSeqId() uint64 {
+	if x != nil {
+		return x.SeqId
+	}
+	return 0
+}
+
+func (x *SubscribeRes) GetMsgList() []*ChatConvMsg {
+	if x != nil {
+		return x.MsgList
+	}
+	return nil
+}
+
+func (x *SubscribeRes) GetIsHeartbeat() bool {
+	if x != nil {
+		return x.IsHeartbeat
+	}
+	return false
+}
+
+func (x *SubscribeRes) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
 var File_api_proto protoreflect.FileDescriptor
 
 var file_api_proto_rawDesc = []byte{
 	0x0a, 0x09, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x67, 0x65, 0x6e,
-	0x5f, 0x67, 0x72, 0x70, 0x63, 0x22, 0x4a, 0x0a, 0x10, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65,
-	0x72, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65,
-	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65,
-	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
-	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
-	0x64, 0x22, 0x69, 0x0a, 0x10, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67,
-	0x69, 0x6e, 0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f,
-	0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x2b, 0x0a, 0x11,
-	0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65,
-	0x71, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x22, 0x40, 0x0a, 0x11, 0x53, 0x65, 0x73,
-	0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x73, 0x12, 0x2b,
+	0x5f, 0x67, 0x72, 0x70, 0x63, 0x22, 0x86, 0x01, 0x0a, 0x10, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73,
+	0x65, 0x72, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x22, 0x89,
+	0x02, 0x0a, 0x10, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x69, 0x6e,
+	0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x36, 0x0a, 0x16,
+	0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78, 0x70, 0x69, 0x72,
+	0x65, 0x73, 0x54, 0x73, 0x4d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x16, 0x61, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73,
+	0x54, 0x73, 0x4d, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x72,
+	0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x72,
+	0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x53, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x72,
+	0x65, 0x74, 0x72, 0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x53, 0x22, 0x2b, 0x0a, 0x11, 0x53, 0x65,
+	0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x22, 0x40, 0x0a, 0x11, 0x53, 0x65, 0x73, 0x73, 0x55,
+	0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07,
+	0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65,
+	0x52, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x34, 0x0a, 0x0e, 0x53, 0x65, 0x73,
+	0x73, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x52, 0x65, 0x71, 0x12, 0x22, 0x0a, 0x0c, 0x72,
+	0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x97, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x52,
+	0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45,
+	0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x12, 0x36, 0x0a, 0x16, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x54, 0x73, 0x4d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x16, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x73, 0x54, 0x73, 0x4d, 0x73, 0x22, 0xd2, 0x01, 0x0a, 0x08, 0x53, 0x65,
+	0x73, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x4b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79,
+	0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x73, 0x4d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x73, 0x4d, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x54, 0x73, 0x4d, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x54, 0x73, 0x4d, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x28,
+	0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x22, 0x6d, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73,
+	0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x72,
+	0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67, 0x65,
+	0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x07,
+	0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x4c,
+	0x69, 0x73, 0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x73,
+	0x65, 0x73, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x41, 0x0a, 0x0d, 0x53, 0x65, 0x73, 0x73, 0x52,
+	0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x73, 0x73,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x22, 0x3c, 0x0a, 0x0d, 0x53, 0x65,
+	0x73, 0x73, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65,
+	0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67,
+	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52,
+	0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x2d, 0x0a, 0x13, 0x53, 0x65, 0x73, 0x73,
+	0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x22, 0x42, 0x0a, 0x13, 0x53, 0x65, 0x73, 0x73, 0x52,
+	0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x12, 0x2b,
 	0x0a, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
 	0x11, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f,
 	0x64, 0x65, 0x52, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0xcd, 0x01, 0x0a, 0x0d,
@@ -4171,207 +5089,276 @@ var file_api_proto_rawDesc = []byte{
 	0x61, 0x72, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x72,
 	0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67, 0x65,
 	0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x07,
-	0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x4a, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x73,
-	0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x73,
-	0x73, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x49,
-	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x65,
-	0x71, 0x49, 0x64, 0x22, 0x86, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x72, 0x72, 0x43,
-	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
-	0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x65, 0x72,
-	0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x65, 0x71, 0x49, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x07, 0x6d,
-	0x73, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x67,
-	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x76,
-	0x4d, 0x73, 0x67, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x2a, 0xc9, 0x02, 0x0a,
-	0x07, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x65, 0x6d, 0x45, 0x72,
-	0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x4f, 0x6b, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x65, 0x6d,
-	0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x45,
-	0x72, 0x72, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64,
-	0x65, 0x5f, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x65,
-	0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x53, 0x65, 0x73, 0x73, 0x4e, 0x6f, 0x74,
-	0x45, 0x78, 0x69, 0x73, 0x74, 0x65, 0x64, 0x10, 0x64, 0x12, 0x20, 0x0a, 0x1b, 0x65, 0x6d, 0x45,
-	0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x6f, 0x74, 0x52, 0x65,
-	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x10, 0xc8, 0x01, 0x12, 0x24, 0x0a, 0x1f, 0x65,
-	0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x55, 0x73, 0x65, 0x72, 0x41, 0x6c, 0x72,
-	0x65, 0x61, 0x64, 0x79, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x10, 0xc9,
-	0x01, 0x12, 0x1f, 0x0a, 0x1a, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x55,
-	0x73, 0x65, 0x72, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x54, 0x6f, 0x41, 0x75, 0x74, 0x68, 0x10,
-	0xca, 0x01, 0x12, 0x18, 0x0a, 0x13, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f,
-	0x49, 0x73, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x10, 0xac, 0x02, 0x12, 0x1b, 0x0a, 0x16,
-	0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x49, 0x73, 0x4e, 0x6f, 0x74, 0x43,
-	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x10, 0xad, 0x02, 0x12, 0x1e, 0x0a, 0x19, 0x65, 0x6d, 0x45,
-	0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x6f, 0x74, 0x45,
-	0x78, 0x69, 0x73, 0x74, 0x65, 0x64, 0x10, 0x90, 0x03, 0x12, 0x1d, 0x0a, 0x18, 0x65, 0x6d, 0x45,
-	0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x6f, 0x74, 0x49, 0x6e,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x10, 0x91, 0x03, 0x2a, 0xb7, 0x03, 0x0a, 0x0b, 0x43, 0x68, 0x61,
-	0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x65, 0x6d, 0x43, 0x68,
-	0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x54, 0x65, 0x78, 0x74, 0x10, 0x00,
-	0x12, 0x1a, 0x0a, 0x16, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70,
-	0x65, 0x5f, 0x4d, 0x61, 0x72, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x10, 0x32, 0x12, 0x1f, 0x0a, 0x1b,
-	0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x43, 0x6f,
-	0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71, 0x10, 0x64, 0x12, 0x1e, 0x0a,
-	0x1a, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x43,
-	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x10, 0x65, 0x12, 0x21, 0x0a,
-	0x1d, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x43,
-	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x66,
-	0x12, 0x20, 0x0a, 0x1c, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70,
-	0x65, 0x5f, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64,
-	0x10, 0x67, 0x12, 0x1f, 0x0a, 0x1a, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54,
-	0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
-	0x10, 0xc8, 0x01, 0x12, 0x1f, 0x0a, 0x1a, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67,
-	0x54, 0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x64, 0x10, 0xc9, 0x01, 0x12, 0x1f, 0x0a, 0x1a, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73,
-	0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4a, 0x6f, 0x69, 0x6e, 0x52,
-	0x65, 0x71, 0x10, 0xca, 0x01, 0x12, 0x22, 0x0a, 0x1d, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d,
-	0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x73, 0x65, 0x72,
-	0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x10, 0xcb, 0x01, 0x12, 0x20, 0x0a, 0x1b, 0x65, 0x6d, 0x43,
-	0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0xcc, 0x01, 0x12, 0x20, 0x0a, 0x1b, 0x65,
-	0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74, 0x10, 0xcd, 0x01, 0x12, 0x23, 0x0a,
-	0x1e, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x10,
-	0xce, 0x01, 0x32, 0x9f, 0x11, 0x0a, 0x07, 0x47, 0x72, 0x70, 0x63, 0x41, 0x70, 0x69, 0x12, 0x47,
-	0x0a, 0x0d, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12,
-	0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x55,
-	0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65,
-	0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c,
-	0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x12, 0x4a, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x55,
-	0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x12, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
-	0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67,
-	0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x91, 0x01, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x74,
+	0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x68, 0x61, 0x74, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x52, 0x06, 0x70, 0x65, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x53, 0x65, 0x71,
+	0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65,
+	0x53, 0x65, 0x71, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x8b, 0x01, 0x0a, 0x11,
+	0x43, 0x68, 0x61, 0x74, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x72,
+	0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2f,
+	0x0a, 0x07, 0x6d, 0x73, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43,
+	0x6f, 0x6e, 0x76, 0x4d, 0x73, 0x67, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x4d, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x68, 0x61, 0x73, 0x4d, 0x6f, 0x72, 0x65, 0x22, 0x66, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x65,
+	0x71, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x53, 0x65, 0x71, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0xa0, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x65, 0x72, 0x72, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x65, 0x71, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x07, 0x6d, 0x73, 0x67,
+	0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x67, 0x65, 0x6e,
+	0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x76, 0x4d, 0x73,
+	0x67, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x61,
+	0x73, 0x4d, 0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x61, 0x73,
+	0x4d, 0x6f, 0x72, 0x65, 0x22, 0x76, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a,
+	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0a, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x12,
+	0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x53, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62,
+	0x65, 0x61, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x22, 0xbe, 0x01, 0x0a,
+	0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x73, 0x12, 0x2b, 0x0a,
+	0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11,
+	0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64,
+	0x65, 0x52, 0x07, 0x65, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x65,
+	0x71, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x49, 0x64,
+	0x12, 0x2f, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61,
+	0x74, 0x43, 0x6f, 0x6e, 0x76, 0x4d, 0x73, 0x67, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x73, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x73, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62,
+	0x65, 0x61, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x4d, 0x6f, 0x72, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x61, 0x73, 0x4d, 0x6f, 0x72, 0x65, 0x2a, 0xe5, 0x02,
+	0x0a, 0x07, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x65, 0x6d, 0x45,
+	0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x4f, 0x6b, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x65,
+	0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e,
+	0x45, 0x72, 0x72, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f,
+	0x64, 0x65, 0x5f, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18,
+	0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x53, 0x65, 0x73, 0x73, 0x4e, 0x6f,
+	0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x65, 0x64, 0x10, 0x64, 0x12, 0x20, 0x0a, 0x1b, 0x65, 0x6d,
+	0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x6f, 0x74, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x10, 0xc8, 0x01, 0x12, 0x24, 0x0a, 0x1f,
+	0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x55, 0x73, 0x65, 0x72, 0x41, 0x6c,
+	0x72, 0x65, 0x61, 0x64, 0x79, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x10,
+	0xc9, 0x01, 0x12, 0x1f, 0x0a, 0x1a, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f,
+	0x55, 0x73, 0x65, 0x72, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x54, 0x6f, 0x41, 0x75, 0x74, 0x68,
+	0x10, 0xca, 0x01, 0x12, 0x1a, 0x0a, 0x15, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65,
+	0x5f, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x10, 0xcb, 0x01, 0x12,
+	0x18, 0x0a, 0x13, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x49, 0x73, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x10, 0xac, 0x02, 0x12, 0x1b, 0x0a, 0x16, 0x65, 0x6d, 0x45,
+	0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x5f, 0x49, 0x73, 0x4e, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x63, 0x74, 0x10, 0xad, 0x02, 0x12, 0x1e, 0x0a, 0x19, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43,
+	0x6f, 0x64, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x6f, 0x74, 0x45, 0x78, 0x69, 0x73,
+	0x74, 0x65, 0x64, 0x10, 0x90, 0x03, 0x12, 0x1d, 0x0a, 0x18, 0x65, 0x6d, 0x45, 0x72, 0x72, 0x43,
+	0x6f, 0x64, 0x65, 0x5f, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x6f, 0x74, 0x49, 0x6e, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x10, 0x91, 0x03, 0x2a, 0xb7, 0x03, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73,
+	0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d,
+	0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x54, 0x65, 0x78, 0x74, 0x10, 0x00, 0x12, 0x1a, 0x0a,
+	0x16, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x4d,
+	0x61, 0x72, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x10, 0x32, 0x12, 0x1f, 0x0a, 0x1b, 0x65, 0x6d, 0x43,
+	0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x43, 0x6f, 0x6e, 0x74, 0x61,
+	0x63, 0x74, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71, 0x10, 0x64, 0x12, 0x1e, 0x0a, 0x1a, 0x65, 0x6d,
+	0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x65, 0x64, 0x10, 0x65, 0x12, 0x21, 0x0a, 0x1d, 0x65, 0x6d,
+	0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x63, 0x74, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x66, 0x12, 0x20, 0x0a,
+	0x1c, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x67, 0x12,
+	0x1f, 0x0a, 0x1a, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65,
+	0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x10, 0xc8, 0x01,
+	0x12, 0x1f, 0x0a, 0x1a, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0xc9,
+	0x01, 0x12, 0x1f, 0x0a, 0x1a, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79,
+	0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x10,
+	0xca, 0x01, 0x12, 0x22, 0x0a, 0x1d, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54,
+	0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x73, 0x65, 0x72, 0x4a, 0x6f, 0x69,
+	0x6e, 0x65, 0x64, 0x10, 0xcb, 0x01, 0x12, 0x20, 0x0a, 0x1b, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x74,
+	0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0xcc, 0x01, 0x12, 0x20, 0x0a, 0x1b, 0x65, 0x6d, 0x43, 0x68,
+	0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55,
+	0x73, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74, 0x10, 0xcd, 0x01, 0x12, 0x23, 0x0a, 0x1e, 0x65, 0x6d,
+	0x43, 0x68, 0x61, 0x74, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x5f, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x10, 0xce, 0x01, 0x32,
+	0xc2, 0x14, 0x0a, 0x07, 0x47, 0x72, 0x70, 0x63, 0x41, 0x70, 0x69, 0x12, 0x47, 0x0a, 0x0d, 0x53,
+	0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1a, 0x2e, 0x67,
+	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72,
+	0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x69,
+	0x6e, 0x52, 0x65, 0x73, 0x12, 0x4a, 0x0a, 0x0e, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72,
+	0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x12, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
 	0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74,
-	0x52, 0x65, 0x73, 0x12, 0x3e, 0x0a, 0x0a, 0x55, 0x6d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
-	0x72, 0x12, 0x17, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x52,
-	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e, 0x67, 0x65, 0x6e,
-	0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
-	0x52, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0c, 0x55, 0x6d, 0x55, 0x6e, 0x72, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
-	0x6d, 0x55, 0x6e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x19,
-	0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x55, 0x6e, 0x72, 0x65,
-	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x50, 0x0a, 0x10, 0x55, 0x6d, 0x55,
-	0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1d, 0x2e,
-	0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x55, 0x73, 0x65, 0x72, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x67,
-	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x12, 0x50, 0x0a, 0x10, 0x55,
-	0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12,
-	0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e,
-	0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1d,
-	0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74,
-	0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x50, 0x0a,
-	0x10, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66,
-	0x6f, 0x12, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43,
-	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71,
-	0x1a, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f,
-	0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x12,
-	0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x46, 0x69, 0x6e, 0x64,
-	0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f,
-	0x6e, 0x74, 0x61, 0x63, 0x74, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67,
-	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63,
-	0x74, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x12, 0x59, 0x0a, 0x13, 0x55, 0x6d, 0x43, 0x6f,
-	0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x20, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e,
-	0x74, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65,
-	0x71, 0x1a, 0x20, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43,
-	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x52, 0x65, 0x73, 0x12, 0x4d, 0x0a, 0x0f, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74,
-	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x12, 0x1c, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70,
-	0x74, 0x52, 0x65, 0x71, 0x1a, 0x1c, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52,
-	0x65, 0x73, 0x12, 0x4d, 0x0a, 0x0f, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52,
-	0x65, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1c, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74,
-	0x52, 0x65, 0x71, 0x1a, 0x1c, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
-	0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65,
-	0x73, 0x12, 0x44, 0x0a, 0x0c, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x44, 0x65,
-	0x6c, 0x12, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43,
-	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x44, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x67,
-	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63,
-	0x74, 0x44, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x12, 0x4a, 0x0a, 0x0e, 0x55, 0x6d, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
-	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x4c,
-	0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74,
-	0x52, 0x65, 0x73, 0x12, 0x4a, 0x0a, 0x0e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65,
-	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52,
-	0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x12,
-	0x53, 0x0a, 0x11, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1e, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66,
-	0x6f, 0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66,
-	0x6f, 0x52, 0x65, 0x73, 0x12, 0x41, 0x0a, 0x0b, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x46,
-	0x69, 0x6e, 0x64, 0x12, 0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
-	0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e,
+	0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x65, 0x73, 0x73, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x73,
+	0x12, 0x41, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x47,
+	0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x12, 0x3e, 0x0a, 0x0a, 0x53, 0x65, 0x73, 0x73, 0x52, 0x65, 0x76, 0x6f, 0x6b,
+	0x65, 0x12, 0x17, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73,
+	0x73, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e, 0x67, 0x65, 0x6e,
+	0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
+	0x52, 0x65, 0x73, 0x12, 0x50, 0x0a, 0x10, 0x53, 0x65, 0x73, 0x73, 0x52, 0x65, 0x76, 0x6f, 0x6b,
+	0x65, 0x4f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4f, 0x74, 0x68,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x4f, 0x74, 0x68, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x12, 0x41, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73, 0x52, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x12, 0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x65, 0x73, 0x73, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x52, 0x65, 0x71, 0x1a, 0x18,
+	0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x52, 0x65,
+	0x66, 0x72, 0x65, 0x73, 0x68, 0x52, 0x65, 0x73, 0x12, 0x3e, 0x0a, 0x0a, 0x55, 0x6d, 0x52, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x17, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x55, 0x6d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a,
+	0x17, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x52, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0c, 0x55, 0x6d, 0x55, 0x6e,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x55, 0x6e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
+	0x6d, 0x55, 0x6e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x50,
+	0x0a, 0x10, 0x55, 0x6d, 0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d,
+	0x55, 0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
+	0x71, 0x1a, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x55,
+	0x73, 0x65, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73,
+	0x12, 0x50, 0x0a, 0x10, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
+	0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x65, 0x73, 0x12, 0x50, 0x0a, 0x10, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47,
+	0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63,
+	0x74, 0x46, 0x69, 0x6e, 0x64, 0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65,
+	0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x12, 0x59, 0x0a,
+	0x13, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x20, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x64, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x4d, 0x0a, 0x0f, 0x55, 0x6d, 0x43, 0x6f,
+	0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x12, 0x1c, 0x2e, 0x67, 0x65,
+	0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74,
+	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1c, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x41, 0x63,
+	0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x12, 0x4d, 0x0a, 0x0f, 0x55, 0x6d, 0x43, 0x6f, 0x6e,
+	0x74, 0x61, 0x63, 0x74, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1c, 0x2e, 0x67, 0x65, 0x6e,
+	0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52,
+	0x65, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1c, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0c, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x63, 0x74, 0x44, 0x65, 0x6c, 0x12, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x55, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x44, 0x65, 0x6c, 0x52, 0x65,
+	0x71, 0x1a, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x43,
+	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x44, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x12, 0x4a, 0x0a, 0x0e,
+	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1b,
+	0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x67, 0x65,
+	0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65,
+	0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x4a, 0x0a, 0x0e, 0x55, 0x6d, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1b, 0x2e, 0x67, 0x65, 0x6e,
+	0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x65, 0x73, 0x12, 0x53, 0x0a, 0x11, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1e, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x12, 0x41, 0x0a, 0x0b, 0x55, 0x6d, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x46, 0x69, 0x6e, 0x64, 0x12, 0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x46, 0x69, 0x6e, 0x64, 0x52,
+	0x65, 0x71, 0x1a, 0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d,
+	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x2e,
 	0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x46, 0x69, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
-	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
-	0x12, 0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x53,
+	0x0a, 0x11, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x4c,
+	0x69, 0x73, 0x74, 0x12, 0x1e, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
+	0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
+	0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x12, 0x56, 0x0a, 0x12, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4a, 0x6f,
+	0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4a, 0x6f, 0x69, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1f, 0x2e, 0x67, 0x65, 0x6e,
+	0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4a, 0x6f, 0x69,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x55,
+	0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x12, 0x1a, 0x2e, 0x67,
+	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x63, 0x63, 0x65, 0x70,
+	0x74, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
+	0x65, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65,
+	0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x44, 0x0a,
+	0x0c, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x12, 0x19, 0x2e,
 	0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x53, 0x0a, 0x11, 0x55, 0x6d, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1e,
-	0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1e,
-	0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x56,
-	0x0a, 0x12, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1f, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72,
-	0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
-	0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
-	0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x12,
-	0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74,
-	0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67,
-	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
-	0x65, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0c, 0x55, 0x6d, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x12, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
+	0x4c, 0x65, 0x61, 0x76, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
 	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x65, 0x61, 0x76, 0x65,
-	0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
-	0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x52, 0x65, 0x73, 0x12, 0x47,
-	0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x4d, 0x65, 0x6d, 0x12,
-	0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x41, 0x64, 0x64, 0x4d, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65,
-	0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x64,
-	0x64, 0x4d, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x44, 0x65, 0x6c, 0x4d, 0x65, 0x6d, 0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
-	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x4d, 0x65,
-	0x6d, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x4d, 0x65, 0x6d, 0x52, 0x65, 0x73,
-	0x12, 0x50, 0x0a, 0x10, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x4d, 0x65, 0x6d, 0x12, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x6d,
-	0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
-	0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x52,
-	0x65, 0x73, 0x12, 0x41, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x73,
-	0x67, 0x12, 0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61,
-	0x74, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x73, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x67, 0x65,
-	0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x4d,
-	0x73, 0x67, 0x52, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0c, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x61, 0x72,
-	0x6b, 0x52, 0x65, 0x61, 0x64, 0x12, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x61, 0x72, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71,
-	0x1a, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x74,
-	0x4d, 0x61, 0x72, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x47,
-	0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1a, 0x2e, 0x67,
-	0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
-	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x73,
-	0x74, 0x52, 0x65, 0x73, 0x42, 0x25, 0x5a, 0x23, 0x73, 0x6f, 0x63, 0x69, 0x61, 0x6c, 0x5f, 0x73,
+	0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x64,
+	0x64, 0x4d, 0x65, 0x6d, 0x12, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x4d, 0x65, 0x6d, 0x52, 0x65, 0x71,
+	0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x41, 0x64, 0x64, 0x4d, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d,
+	0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x4d, 0x65, 0x6d, 0x12, 0x1a, 0x2e,
+	0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x44, 0x65, 0x6c, 0x4d, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x44, 0x65, 0x6c, 0x4d,
+	0x65, 0x6d, 0x52, 0x65, 0x73, 0x12, 0x50, 0x0a, 0x10, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x6d, 0x12, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x4d, 0x65, 0x6d, 0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x55, 0x6d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x4d, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x12, 0x41, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x53,
+	0x65, 0x6e, 0x64, 0x4d, 0x73, 0x67, 0x12, 0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x73, 0x67, 0x52, 0x65, 0x71,
+	0x1a, 0x18, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x74,
+	0x53, 0x65, 0x6e, 0x64, 0x4d, 0x73, 0x67, 0x52, 0x65, 0x73, 0x12, 0x44, 0x0a, 0x0c, 0x43, 0x68,
+	0x61, 0x74, 0x4d, 0x61, 0x72, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x12, 0x19, 0x2e, 0x67, 0x65, 0x6e,
+	0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x61, 0x72, 0x6b, 0x52, 0x65,
+	0x61, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x61, 0x72, 0x6b, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73,
+	0x12, 0x4a, 0x0a, 0x0e, 0x43, 0x68, 0x61, 0x74, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x12, 0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68,
+	0x61, 0x74, 0x47, 0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x1a,
+	0x1b, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x47,
+	0x65, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x12, 0x47, 0x0a, 0x0d,
+	0x47, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x1a, 0x2e,
+	0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x67, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x73, 0x12, 0x3d, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x65, 0x6e,
+	0x5f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52,
+	0x65, 0x73, 0x30, 0x01, 0x42, 0x25, 0x5a, 0x23, 0x73, 0x6f, 0x63, 0x69, 0x61, 0x6c, 0x5f, 0x73,
 	0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x72,
 	0x70, 0x63, 0x3b, 0x67, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x33,
@@ -4390,7 +5377,7 @@ func file_api_proto_rawDescGZIP() []byte {
 }
 
 var file_api_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_proto_msgTypes = make([]protoimpl.MessageInfo, 64)
+var file_api_proto_msgTypes = make([]protoimpl.MessageInfo, 77)
 var file_api_proto_goTypes = []interface{}{
 	(ErrCode)(0),                   // 0: gen_grpc.ErrCode
 	(ChatMsgType)(0),               // 1: gen_grpc.ChatMsgType
@@ -4398,172 +5385,207 @@ var file_api_proto_goTypes = []interface{}{
 	(*SessUserLoginRes)(nil),       // 3: gen_grpc.SessUserLoginRes
 	(*SessUserLogoutReq)(nil),      // 4: gen_grpc.SessUserLogoutReq
 	(*SessUserLogoutRes)(nil),      // 5: gen_grpc.SessUserLogoutRes
-	(*UmContactInfo)(nil),          // 6: gen_grpc.UmContactInfo
-	(*UmRegisterReq)(nil),          // 7: gen_grpc.UmRegisterReq
-	(*UmRegisterRes)(nil),          // 8: gen_grpc.UmRegisterRes
-	(*UmUnregisterReq)(nil),        // 9: gen_grpc.UmUnregisterReq
-	(*UmUnregisterRes)(nil),        // 10: gen_grpc.UmUnregisterRes
-	(*UmUserUpdateInfoReq)(nil),    // 11: gen_grpc.UmUserUpdateInfoReq
-	(*UmUserUpdateInfoRes)(nil),    // 12: gen_grpc.UmUserUpdateInfoRes
-	(*UmContactGetListReq)(nil),    // 13: gen_grpc.UmContactGetListReq
-	(*UmContactGetListRes)(nil),    // 14: gen_grpc.UmContactGetListRes
-	(*UmContactGetInfoReq)(nil),    // 15: gen_grpc.UmContactGetInfoReq
-	(*UmContactGetInfoRes)(nil),    // 16: gen_grpc.UmContactGetInfoRes
-	(*UmContactFindReq)(nil),       // 17: gen_grpc.UmContactFindReq
-	(*UmContactFindRes)(nil),       // 18: gen_grpc.UmContactFindRes
-	(*UmContactAddRequestReq)(nil), // 19: gen_grpc.UmContactAddRequestReq
-	(*UmContactAddRequestRes)(nil), // 20: gen_grpc.UmContactAddRequestRes
-	(*UmContactAcceptReq)(nil),     // 21: gen_grpc.UmContactAcceptReq
-	(*UmContactAcceptRes)(nil),     // 22: gen_grpc.UmContactAcceptRes
-	(*UmContactRejectReq)(nil),     // 23: gen_grpc.UmContactRejectReq
-	(*UmContactRejectRes)(nil),     // 24: gen_grpc.UmContactRejectRes
-	(*UmContactDelReq)(nil),        // 25: gen_grpc.UmContactDelReq
-	(*UmContactDelRes)(nil),        // 26: gen_grpc.UmContactDelRes
-	(*UmGroupInfo)(nil),            // 27: gen_grpc.UmGroupInfo
-	(*UmGroupGetListReq)(nil),      // 28: gen_grpc.UmGroupGetListReq
-	(*UmGroupGetListRes)(nil),      // 29: gen_grpc.UmGroupGetListRes
-	(*UmGroupGetInfoReq)(nil),      // 30: gen_grpc.UmGroupGetInfoReq
-	(*UmGroupGetInfoRes)(nil),      // 31: gen_grpc.UmGroupGetInfoRes
-	(*UmGroupUpdateInfoReq)(nil),   // 32: gen_grpc.UmGroupUpdateInfoReq
-	(*UmGroupUpdateInfoRes)(nil),   // 33: gen_grpc.UmGroupUpdateInfoRes
-	(*UmGroupFindReq)(nil),         // 34: gen_grpc.UmGroupFindReq
-	(*UmGroupFindRes)(nil),         // 35: gen_grpc.UmGroupFindRes
-	(*UmGroupCreateReq)(nil),       // 36: gen_grpc.UmGroupCreateReq
-	(*UmGroupCreateRes)(nil),       // 37: gen_grpc.UmGroupCreateRes
-	(*UmGroupDeleteReq)(nil),       // 38: gen_grpc.UmGroupDeleteReq
-	(*UmGroupDeleteRes)(nil),       // 39: gen_grpc.UmGroupDeleteRes
-	(*UmGroupGetMemListReq)(nil),   // 40: gen_grpc.UmGroupGetMemListReq
-	(*UmGroupGetMemListRes)(nil),   // 41: gen_grpc.UmGroupGetMemListRes
-	(*UmGroupJoinRequestReq)(nil),  // 42: gen_grpc.UmGroupJoinRequestReq
-	(*UmGroupJoinRequestRes)(nil),  // 43: gen_grpc.UmGroupJoinRequestRes
-	(*UmGroupAcceptReq)(nil),       // 44: gen_grpc.UmGroupAcceptReq
-	(*UmGroupAcceptRes)(nil),       // 45: gen_grpc.UmGroupAcceptRes
-	(*UmGroupRejectReq)(nil),       // 46: gen_grpc.UmGroupRejectReq
-	(*UmGroupRejectRes)(nil),       // 47: gen_grpc.UmGroupRejectRes
-	(*UmGroupLeaveReq)(nil),        // 48: gen_grpc.UmGroupLeaveReq
-	(*UmGroupLeaveRes)(nil),        // 49: gen_grpc.UmGroupLeaveRes
-	(*UmGroupAddMemReq)(nil),       // 50: gen_grpc.UmGroupAddMemReq
-	(*UmGroupAddMemRes)(nil),       // 51: gen_grpc.UmGroupAddMemRes
-	(*UmGroupDelMemReq)(nil),       // 52: gen_grpc.UmGroupDelMemReq
-	(*UmGroupDelMemRes)(nil),       // 53: gen_grpc.UmGroupDelMemRes
-	(*UmGroupUpdateMemReq)(nil),    // 54: gen_grpc.UmGroupUpdateMemReq
-	(*UmGroupUpdateMemRes)(nil),    // 55: gen_grpc.UmGroupUpdateMemRes
-	(*ChatPeerId)(nil),             // 56: gen_grpc.ChatPeerId
-	(*ChatMsg)(nil),                // 57: gen_grpc.ChatMsg
-	(*ChatConvMsg)(nil),            // 58: gen_grpc.ChatConvMsg
-	(*ChatConvInfo)(nil),           // 59: gen_grpc.ChatConvInfo
-	(*ChatSendMsgReq)(nil),         // 60: gen_grpc.ChatSendMsgReq
-	(*ChatSendMsgRes)(nil),         // 61: gen_grpc.ChatSendMsgRes
-	(*ChatMarkReadReq)(nil),        // 62: gen_grpc.ChatMarkReadReq
-	(*ChatMarkReadRes)(nil),        // 63: gen_grpc.ChatMarkReadRes
-	(*GetUpdateListReq)(nil),       // 64: gen_grpc.GetUpdateListReq
-	(*GetUpdateListRes)(nil),       // 65: gen_grpc.GetUpdateListRes
+	(*SessRefreshReq)(nil),         // 6: gen_grpc.SessRefreshReq
+	(*SessRefreshRes)(nil),         // 7: gen_grpc.SessRefreshRes
+	(*SessInfo)(nil),               // 8: gen_grpc.SessInfo
+	(*SessGetListReq)(nil),         // 9: gen_grpc.SessGetListReq
+	(*SessGetListRes)(nil),         // 10: gen_grpc.SessGetListRes
+	(*SessRevokeReq)(nil),          // 11: gen_grpc.SessRevokeReq
+	(*SessRevokeRes)(nil),          // 12: gen_grpc.SessRevokeRes
+	(*SessRevokeOthersReq)(nil),    // 13: gen_grpc.SessRevokeOthersReq
+	(*SessRevokeOthersRes)(nil),    // 14: gen_grpc.SessRevokeOthersRes
+	(*UmContactInfo)(nil),          // 15: gen_grpc.UmContactInfo
+	(*UmRegisterReq)(nil),          // 16: gen_grpc.UmRegisterReq
+	(*UmRegisterRes)(nil),          // 17: gen_grpc.UmRegisterRes
+	(*UmUnregisterReq)(nil),        // 18: gen_grpc.UmUnregisterReq
+	(*UmUnregisterRes)(nil),        // 19: gen_grpc.UmUnregisterRes
+	(*UmUserUpdateInfoReq)(nil),    // 20: gen_grpc.UmUserUpdateInfoReq
+	(*UmUserUpdateInfoRes)(nil),    // 21: gen_grpc.UmUserUpdateInfoRes
+	(*UmContactGetListReq)(nil),    // 22: gen_grpc.UmContactGetListReq
+	(*UmContactGetListRes)(nil),    // 23: gen_grpc.UmContactGetListRes
+	(*UmContactGetInfoReq)(nil),    // 24: gen_grpc.UmContactGetInfoReq
+	(*UmContactGetInfoRes)(nil),    // 25: gen_grpc.UmContactGetInfoRes
+	(*UmContactFindReq)(nil),       // 26: gen_grpc.UmContactFindReq
+	(*UmContactFindRes)(nil),       // 27: gen_grpc.UmContactFindRes
+	(*UmContactAddRequestReq)(nil), // 28: gen_grpc.UmContactAddRequestReq
+	(*UmContactAddRequestRes)(nil), // 29: gen_grpc.UmContactAddRequestRes
+	(*UmContactAcceptReq)(nil),     // 30: gen_grpc.UmContactAcceptReq
+	(*UmContactAcceptRes)(nil),     // 31: gen_grpc.UmContactAcceptRes
+	(*UmContactRejectReq)(nil),     // 32: gen_grpc.UmContactRejectReq
+	(*UmContactRejectRes)(nil),     // 33: gen_grpc.UmContactRejectRes
+	(*UmContactDelReq)(nil),        // 34: gen_grpc.UmContactDelReq
+	(*UmContactDelRes)(nil),        // 35: gen_grpc.UmContactDelRes
+	(*UmGroupInfo)(nil),            // 36: gen_grpc.UmGroupInfo
+	(*UmGroupGetListReq)(nil),      // 37: gen_grpc.UmGroupGetListReq
+	(*UmGroupGetListRes)(nil),      // 38: gen_grpc.UmGroupGetListRes
+	(*UmGroupGetInfoReq)(nil),      // 39: gen_grpc.UmGroupGetInfoReq
+	(*UmGroupGetInfoRes)(nil),      // 40: gen_grpc.UmGroupGetInfoRes
+	(*UmGroupUpdateInfoReq)(nil),   // 41: gen_grpc.UmGroupUpdateInfoReq
+	(*UmGroupUpdateInfoRes)(nil),   // 42: gen_grpc.UmGroupUpdateInfoRes
+	(*UmGroupFindReq)(nil),         // 43: gen_grpc.UmGroupFindReq
+	(*UmGroupFindRes)(nil),         // 44: gen_grpc.UmGroupFindRes
+	(*UmGroupCreateReq)(nil),       // 45: gen_grpc.UmGroupCreateReq
+	(*UmGroupCreateRes)(nil),       // 46: gen_grpc.UmGroupCreateRes
+	(*UmGroupDeleteReq)(nil),       // 47: gen_grpc.UmGroupDeleteReq
+	(*UmGroupDeleteRes)(nil),       // 48: gen_grpc.UmGroupDeleteRes
+	(*UmGroupGetMemListReq)(nil),   // 49: gen_grpc.UmGroupGetMemListReq
+	(*UmGroupGetMemListRes)(nil),   // 50: gen_grpc.UmGroupGetMemListRes
+	(*UmGroupJoinRequestReq)(nil),  // 51: gen_grpc.UmGroupJoinRequestReq
+	(*UmGroupJoinRequestRes)(nil),  // 52: gen_grpc.UmGroupJoinRequestRes
+	(*UmGroupAcceptReq)(nil),       // 53: gen_grpc.UmGroupAcceptReq
+	(*UmGroupAcceptRes)(nil),       // 54: gen_grpc.UmGroupAcceptRes
+	(*UmGroupRejectReq)(nil),       // 55: gen_grpc.UmGroupRejectReq
+	(*UmGroupRejectRes)(nil),       // 56: gen_grpc.UmGroupRejectRes
+	(*UmGroupLeaveReq)(nil),        // 57: gen_grpc.UmGroupLeaveReq
+	(*UmGroupLeaveRes)(nil),        // 58: gen_grpc.UmGroupLeaveRes
+	(*UmGroupAddMemReq)(nil),       // 59: gen_grpc.UmGroupAddMemReq
+	(*UmGroupAddMemRes)(nil),       // 60: gen_grpc.UmGroupAddMemRes
+	(*UmGroupDelMemReq)(nil),       // 61: gen_grpc.UmGroupDelMemReq
+	(*UmGroupDelMemRes)(nil),       // 62: gen_grpc.UmGroupDelMemRes
+	(*UmGroupUpdateMemReq)(nil),    // 63: gen_grpc.UmGroupUpdateMemReq
+	(*UmGroupUpdateMemRes)(nil),    // 64: gen_grpc.UmGroupUpdateMemRes
+	(*ChatPeerId)(nil),             // 65: gen_grpc.ChatPeerId
+	(*ChatMsg)(nil),                // 66: gen_grpc.ChatMsg
+	(*ChatConvMsg)(nil),            // 67: gen_grpc.ChatConvMsg
+	(*ChatConvInfo)(nil),           // 68: gen_grpc.ChatConvInfo
+	(*ChatSendMsgReq)(nil),         // 69: gen_grpc.ChatSendMsgReq
+	(*ChatSendMsgRes)(nil),         // 70: gen_grpc.ChatSendMsgRes
+	(*ChatMarkReadReq)(nil),        // 71: gen_grpc.ChatMarkReadReq
+	(*ChatMarkReadRes)(nil),        // 72: gen_grpc.ChatMarkReadRes
+	(*ChatGetHistoryReq)(nil),      // 73: gen_grpc.ChatGetHistoryReq
+	(*ChatGetHistoryRes)(nil),      // 74: gen_grpc.ChatGetHistoryRes
+	(*GetUpdateListReq)(nil),       // 75: gen_grpc.GetUpdateListReq
+	(*GetUpdateListRes)(nil),       // 76: gen_grpc.GetUpdateListRes
+	(*SubscribeReq)(nil),           // 77: gen_grpc.SubscribeReq
+	(*SubscribeRes)(nil),           // 78: gen_grpc.SubscribeRes
 }
 var file_api_proto_depIdxs = []int32{
 	0,  // 0: gen_grpc.SessUserLoginRes.errCode:type_name -> gen_grpc.ErrCode
 	0,  // 1: gen_grpc.SessUserLogoutRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 2: gen_grpc.UmRegisterRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 3: gen_grpc.UmUnregisterRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 4: gen_grpc.UmUserUpdateInfoRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 5: gen_grpc.UmContactGetListRes.errCode:type_name -> gen_grpc.ErrCode
-	6,  // 6: gen_grpc.UmContactGetListRes.contactList:type_name -> gen_grpc.UmContactInfo
-	0,  // 7: gen_grpc.UmContactGetInfoRes.errCode:type_name -> gen_grpc.ErrCode
-	6,  // 8: gen_grpc.UmContactGetInfoRes.userInfo:type_name -> gen_grpc.UmContactInfo
-	0,  // 9: gen_grpc.UmContactFindRes.errCode:type_name -> gen_grpc.ErrCode
-	6,  // 10: gen_grpc.UmContactFindRes.userInfo:type_name -> gen_grpc.UmContactInfo
-	0,  // 11: gen_grpc.UmContactAddRequestRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 12: gen_grpc.UmContactAcceptRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 13: gen_grpc.UmContactRejectRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 14: gen_grpc.UmContactDelRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 15: gen_grpc.UmGroupGetListRes.errCode:type_name -> gen_grpc.ErrCode
-	27, // 16: gen_grpc.UmGroupGetListRes.groupList:type_name -> gen_grpc.UmGroupInfo
-	0,  // 17: gen_grpc.UmGroupGetInfoRes.errCode:type_name -> gen_grpc.ErrCode
-	27, // 18: gen_grpc.UmGroupGetInfoRes.groupInfo:type_name -> gen_grpc.UmGroupInfo
-	0,  // 19: gen_grpc.UmGroupUpdateInfoRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 20: gen_grpc.UmGroupFindRes.errCode:type_name -> gen_grpc.ErrCode
-	27, // 21: gen_grpc.UmGroupFindRes.groupInfo:type_name -> gen_grpc.UmGroupInfo
-	0,  // 22: gen_grpc.UmGroupCreateRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 23: gen_grpc.UmGroupDeleteRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 24: gen_grpc.UmGroupGetMemListRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 25: gen_grpc.UmGroupJoinRequestRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 26: gen_grpc.UmGroupAcceptRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 27: gen_grpc.UmGroupRejectRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 28: gen_grpc.UmGroupLeaveRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 29: gen_grpc.UmGroupAddMemRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 30: gen_grpc.UmGroupDelMemRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 31: gen_grpc.UmGroupUpdateMemRes.errCode:type_name -> gen_grpc.ErrCode
-	1,  // 32: gen_grpc.ChatMsg.msgType:type_name -> gen_grpc.ChatMsgType
-	56, // 33: gen_grpc.ChatConvMsg.receiverId:type_name -> gen_grpc.ChatPeerId
-	57, // 34: gen_grpc.ChatConvMsg.msg:type_name -> gen_grpc.ChatMsg
-	58, // 35: gen_grpc.ChatSendMsgReq.convMsg:type_name -> gen_grpc.ChatConvMsg
-	0,  // 36: gen_grpc.ChatSendMsgRes.errCode:type_name -> gen_grpc.ErrCode
-	56, // 37: gen_grpc.ChatMarkReadReq.convId:type_name -> gen_grpc.ChatPeerId
-	0,  // 38: gen_grpc.ChatMarkReadRes.errCode:type_name -> gen_grpc.ErrCode
-	0,  // 39: gen_grpc.GetUpdateListRes.errCode:type_name -> gen_grpc.ErrCode
-	58, // 40: gen_grpc.GetUpdateListRes.msgList:type_name -> gen_grpc.ChatConvMsg
-	2,  // 41: gen_grpc.GrpcApi.SessUserLogin:input_type -> gen_grpc.SessUserLoginReq
-	4,  // 42: gen_grpc.GrpcApi.SessUserLogout:input_type -> gen_grpc.SessUserLogoutReq
-	7,  // 43: gen_grpc.GrpcApi.UmRegister:input_type -> gen_grpc.UmRegisterReq
-	9,  // 44: gen_grpc.GrpcApi.UmUnregister:input_type -> gen_grpc.UmUnregisterReq
-	11, // 45: gen_grpc.GrpcApi.UmUserUpdateInfo:input_type -> gen_grpc.UmUserUpdateInfoReq
-	13, // 46: gen_grpc.GrpcApi.UmContactGetList:input_type -> gen_grpc.UmContactGetListReq
-	15, // 47: gen_grpc.GrpcApi.UmContactGetInfo:input_type -> gen_grpc.UmContactGetInfoReq
-	17, // 48: gen_grpc.GrpcApi.UmContactFind:input_type -> gen_grpc.UmContactFindReq
-	19, // 49: gen_grpc.GrpcApi.UmContactAddRequest:input_type -> gen_grpc.UmContactAddRequestReq
-	21, // 50: gen_grpc.GrpcApi.UmContactAccept:input_type -> gen_grpc.UmContactAcceptReq
-	23, // 51: gen_grpc.GrpcApi.UmContactReject:input_type -> gen_grpc.UmContactRejectReq
-	25, // 52: gen_grpc.GrpcApi.UmContactDel:input_type -> gen_grpc.UmContactDelReq
-	28, // 53: gen_grpc.GrpcApi.UmGroupGetList:input_type -> gen_grpc.UmGroupGetListReq
-	30, // 54: gen_grpc.GrpcApi.UmGroupGetInfo:input_type -> gen_grpc.UmGroupGetInfoReq
-	32, // 55: gen_grpc.GrpcApi.UmGroupUpdateInfo:input_type -> gen_grpc.UmGroupUpdateInfoReq
-	34, // 56: gen_grpc.GrpcApi.UmGroupFind:input_type -> gen_grpc.UmGroupFindReq
-	36, // 57: gen_grpc.GrpcApi.UmGroupCreate:input_type -> gen_grpc.UmGroupCreateReq
-	38, // 58: gen_grpc.GrpcApi.UmGroupDelete:input_type -> gen_grpc.UmGroupDeleteReq
-	40, // 59: gen_grpc.GrpcApi.UmGroupGetMemList:input_type -> gen_grpc.UmGroupGetMemListReq
-	42, // 60: gen_grpc.GrpcApi.UmGroupJoinRequest:input_type -> gen_grpc.UmGroupJoinRequestReq
-	44, // 61: gen_grpc.GrpcApi.UmGroupAccept:input_type -> gen_grpc.UmGroupAcceptReq
-	46, // 62: gen_grpc.GrpcApi.UmGroupReject:input_type -> gen_grpc.UmGroupRejectReq
-	48, // 63: gen_grpc.GrpcApi.UmGroupLeave:input_type -> gen_grpc.UmGroupLeaveReq
-	50, // 64: gen_grpc.GrpcApi.UmGroupAddMem:input_type -> gen_grpc.UmGroupAddMemReq
-	52, // 65: gen_grpc.GrpcApi.UmGroupDelMem:input_type -> gen_grpc.UmGroupDelMemReq
-	54, // 66: gen_grpc.GrpcApi.UmGroupUpdateMem:input_type -> gen_grpc.UmGroupUpdateMemReq
-	60, // 67: gen_grpc.GrpcApi.ChatSendMsg:input_type -> gen_grpc.ChatSendMsgReq
-	62, // 68: gen_grpc.GrpcApi.ChatMarkRead:input_type -> gen_grpc.ChatMarkReadReq
-	64, // 69: gen_grpc.GrpcApi.GetUpdateList:input_type -> gen_grpc.GetUpdateListReq
-	3,  // 70: gen_grpc.GrpcApi.SessUserLogin:output_type -> gen_grpc.SessUserLoginRes
-	5,  // 71: gen_grpc.GrpcApi.SessUserLogout:output_type -> gen_grpc.SessUserLogoutRes
-	8,  // 72: gen_grpc.GrpcApi.UmRegister:output_type -> gen_grpc.UmRegisterRes
-	10, // 73: gen_grpc.GrpcApi.UmUnregister:output_type -> gen_grpc.UmUnregisterRes
-	12, // 74: gen_grpc.GrpcApi.UmUserUpdateInfo:output_type -> gen_grpc.UmUserUpdateInfoRes
-	14, // 75: gen_grpc.GrpcApi.UmContactGetList:output_type -> gen_grpc.UmContactGetListRes
-	16, // 76: gen_grpc.GrpcApi.UmContactGetInfo:output_type -> gen_grpc.UmContactGetInfoRes
-	18, // 77: gen_grpc.GrpcApi.UmContactFind:output_type -> gen_grpc.UmContactFindRes
-	20, // 78: gen_grpc.GrpcApi.UmContactAddRequest:output_type -> gen_grpc.UmContactAddRequestRes
-	22, // 79: gen_grpc.GrpcApi.UmContactAccept:output_type -> gen_grpc.UmContactAcceptRes
-	24, // 80: gen_grpc.GrpcApi.UmContactReject:output_type -> gen_grpc.UmContactRejectRes
-	26, // 81: gen_grpc.GrpcApi.UmContactDel:output_type -> gen_grpc.UmContactDelRes
-	29, // 82: gen_grpc.GrpcApi.UmGroupGetList:output_type -> gen_grpc.UmGroupGetListRes
-	31, // 83: gen_grpc.GrpcApi.UmGroupGetInfo:output_type -> gen_grpc.UmGroupGetInfoRes
-	33, // 84: gen_grpc.GrpcApi.UmGroupUpdateInfo:output_type -> gen_grpc.UmGroupUpdateInfoRes
-	35, // 85: gen_grpc.GrpcApi.UmGroupFind:output_type -> gen_grpc.UmGroupFindRes
-	37, // 86: gen_grpc.GrpcApi.UmGroupCreate:output_type -> gen_grpc.UmGroupCreateRes
-	39, // 87: gen_grpc.GrpcApi.UmGroupDelete:output_type -> gen_grpc.UmGroupDeleteRes
-	41, // 88: gen_grpc.GrpcApi.UmGroupGetMemList:output_type -> gen_grpc.UmGroupGetMemListRes
-	43, // 89: gen_grpc.GrpcApi.UmGroupJoinRequest:output_type -> gen_grpc.UmGroupJoinRequestRes
-	45, // 90: gen_grpc.GrpcApi.UmGroupAccept:output_type -> gen_grpc.UmGroupAcceptRes
-	47, // 91: gen_grpc.GrpcApi.UmGroupReject:output_type -> gen_grpc.UmGroupRejectRes
-	49, // 92: gen_grpc.GrpcApi.UmGroupLeave:output_type -> gen_grpc.UmGroupLeaveRes
-	51, // 93: gen_grpc.GrpcApi.UmGroupAddMem:output_type -> gen_grpc.UmGroupAddMemRes
-	53, // 94: gen_grpc.GrpcApi.UmGroupDelMem:output_type -> gen_grpc.UmGroupDelMemRes
-	55, // 95: gen_grpc.GrpcApi.UmGroupUpdateMem:output_type -> gen_grpc.UmGroupUpdateMemRes
-	61, // 96: gen_grpc.GrpcApi.ChatSendMsg:output_type -> gen_grpc.ChatSendMsgRes
-	63, // 97: gen_grpc.GrpcApi.ChatMarkRead:output_type -> gen_grpc.ChatMarkReadRes
-	65, // 98: gen_grpc.GrpcApi.GetUpdateList:output_type -> gen_grpc.GetUpdateListRes
-	70, // [70:99] is the sub-list for method output_type
-	41, // [41:70] is the sub-list for method input_type
-	41, // [41:41] is the sub-list for extension type_name
-	41, // [41:41] is the sub-list for extension extendee
-	0,  // [0:41] is the sub-list for field type_name
+	0,  // 2: gen_grpc.SessRefreshRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 3: gen_grpc.SessGetListRes.errCode:type_name -> gen_grpc.ErrCode
+	8,  // 4: gen_grpc.SessGetListRes.sessList:type_name -> gen_grpc.SessInfo
+	0,  // 5: gen_grpc.SessRevokeRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 6: gen_grpc.SessRevokeOthersRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 7: gen_grpc.UmRegisterRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 8: gen_grpc.UmUnregisterRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 9: gen_grpc.UmUserUpdateInfoRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 10: gen_grpc.UmContactGetListRes.errCode:type_name -> gen_grpc.ErrCode
+	15, // 11: gen_grpc.UmContactGetListRes.contactList:type_name -> gen_grpc.UmContactInfo
+	0,  // 12: gen_grpc.UmContactGetInfoRes.errCode:type_name -> gen_grpc.ErrCode
+	15, // 13: gen_grpc.UmContactGetInfoRes.userInfo:type_name -> gen_grpc.UmContactInfo
+	0,  // 14: gen_grpc.UmContactFindRes.errCode:type_name -> gen_grpc.ErrCode
+	15, // 15: gen_grpc.UmContactFindRes.userInfo:type_name -> gen_grpc.UmContactInfo
+	0,  // 16: gen_grpc.UmContactAddRequestRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 17: gen_grpc.UmContactAcceptRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 18: gen_grpc.UmContactRejectRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 19: gen_grpc.UmContactDelRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 20: gen_grpc.UmGroupGetListRes.errCode:type_name -> gen_grpc.ErrCode
+	36, // 21: gen_grpc.UmGroupGetListRes.groupList:type_name -> gen_grpc.UmGroupInfo
+	0,  // 22: gen_grpc.UmGroupGetInfoRes.errCode:type_name -> gen_grpc.ErrCode
+	36, // 23: gen_grpc.UmGroupGetInfoRes.groupInfo:type_name -> gen_grpc.UmGroupInfo
+	0,  // 24: gen_grpc.UmGroupUpdateInfoRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 25: gen_grpc.UmGroupFindRes.errCode:type_name -> gen_grpc.ErrCode
+	36, // 26: gen_grpc.UmGroupFindRes.groupInfo:type_name -> gen_grpc.UmGroupInfo
+	0,  // 27: gen_grpc.UmGroupCreateRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 28: gen_grpc.UmGroupDeleteRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 29: gen_grpc.UmGroupGetMemListRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 30: gen_grpc.UmGroupJoinRequestRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 31: gen_grpc.UmGroupAcceptRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 32: gen_grpc.UmGroupRejectRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 33: gen_grpc.UmGroupLeaveRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 34: gen_grpc.UmGroupAddMemRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 35: gen_grpc.UmGroupDelMemRes.errCode:type_name -> gen_grpc.ErrCode
+	0,  // 36: gen_grpc.UmGroupUpdateMemRes.errCode:type_name -> gen_grpc.ErrCode
+	1,  // 37: gen_grpc.ChatMsg.msgType:type_name -> gen_grpc.ChatMsgType
+	65, // 38: gen_grpc.ChatConvMsg.receiverId:type_name -> gen_grpc.ChatPeerId
+	66, // 39: gen_grpc.ChatConvMsg.msg:type_name -> gen_grpc.ChatMsg
+	67, // 40: gen_grpc.ChatSendMsgReq.convMsg:type_name -> gen_grpc.ChatConvMsg
+	0,  // 41: gen_grpc.ChatSendMsgRes.errCode:type_name -> gen_grpc.ErrCode
+	65, // 42: gen_grpc.ChatMarkReadReq.convId:type_name -> gen_grpc.ChatPeerId
+	0,  // 43: gen_grpc.ChatMarkReadRes.errCode:type_name -> gen_grpc.ErrCode
+	65, // 44: gen_grpc.ChatGetHistoryReq.peerId:type_name -> gen_grpc.ChatPeerId
+	0,  // 45: gen_grpc.ChatGetHistoryRes.errCode:type_name -> gen_grpc.ErrCode
+	67, // 46: gen_grpc.ChatGetHistoryRes.msgList:type_name -> gen_grpc.ChatConvMsg
+	0,  // 47: gen_grpc.GetUpdateListRes.errCode:type_name -> gen_grpc.ErrCode
+	67, // 48: gen_grpc.GetUpdateListRes.msgList:type_name -> gen_grpc.ChatConvMsg
+	0,  // 49: gen_grpc.SubscribeRes.errCode:type_name -> gen_grpc.ErrCode
+	67, // 50: gen_grpc.SubscribeRes.msgList:type_name -> gen_grpc.ChatConvMsg
+	2,  // 51: gen_grpc.GrpcApi.SessUserLogin:input_type -> gen_grpc.SessUserLoginReq
+	4,  // 52: gen_grpc.GrpcApi.SessUserLogout:input_type -> gen_grpc.SessUserLogoutReq
+	9,  // 53: gen_grpc.GrpcApi.SessGetList:input_type -> gen_grpc.SessGetListReq
+	11, // 54: gen_grpc.GrpcApi.SessRevoke:input_type -> gen_grpc.SessRevokeReq
+	13, // 55: gen_grpc.GrpcApi.SessRevokeOthers:input_type -> gen_grpc.SessRevokeOthersReq
+	6,  // 56: gen_grpc.GrpcApi.SessRefresh:input_type -> gen_grpc.SessRefreshReq
+	16, // 57: gen_grpc.GrpcApi.UmRegister:input_type -> gen_grpc.UmRegisterReq
+	18, // 58: gen_grpc.GrpcApi.UmUnregister:input_type -> gen_grpc.UmUnregisterReq
+	20, // 59: gen_grpc.GrpcApi.UmUserUpdateInfo:input_type -> gen_grpc.UmUserUpdateInfoReq
+	22, // 60: gen_grpc.GrpcApi.UmContactGetList:input_type -> gen_grpc.UmContactGetListReq
+	24, // 61: gen_grpc.GrpcApi.UmContactGetInfo:input_type -> gen_grpc.UmContactGetInfoReq
+	26, // 62: gen_grpc.GrpcApi.UmContactFind:input_type -> gen_grpc.UmContactFindReq
+	28, // 63: gen_grpc.GrpcApi.UmContactAddRequest:input_type -> gen_grpc.UmContactAddRequestReq
+	30, // 64: gen_grpc.GrpcApi.UmContactAccept:input_type -> gen_grpc.UmContactAcceptReq
+	32, // 65: gen_grpc.GrpcApi.UmContactReject:input_type -> gen_grpc.UmContactRejectReq
+	34, // 66: gen_grpc.GrpcApi.UmContactDel:input_type -> gen_grpc.UmContactDelReq
+	37, // 67: gen_grpc.GrpcApi.UmGroupGetList:input_type -> gen_grpc.UmGroupGetListReq
+	39, // 68: gen_grpc.GrpcApi.UmGroupGetInfo:input_type -> gen_grpc.UmGroupGetInfoReq
+	41, // 69: gen_grpc.GrpcApi.UmGroupUpdateInfo:input_type -> gen_grpc.UmGroupUpdateInfoReq
+	43, // 70: gen_grpc.GrpcApi.UmGroupFind:input_type -> gen_grpc.UmGroupFindReq
+	45, // 71: gen_grpc.GrpcApi.UmGroupCreate:input_type -> gen_grpc.UmGroupCreateReq
+	47, // 72: gen_grpc.GrpcApi.UmGroupDelete:input_type -> gen_grpc.UmGroupDeleteReq
+	49, // 73: gen_grpc.GrpcApi.UmGroupGetMemList:input_type -> gen_grpc.UmGroupGetMemListReq
+	51, // 74: gen_grpc.GrpcApi.UmGroupJoinRequest:input_type -> gen_grpc.UmGroupJoinRequestReq
+	53, // 75: gen_grpc.GrpcApi.UmGroupAccept:input_type -> gen_grpc.UmGroupAcceptReq
+	55, // 76: gen_grpc.GrpcApi.UmGroupReject:input_type -> gen_grpc.UmGroupRejectReq
+	57, // 77: gen_grpc.GrpcApi.UmGroupLeave:input_type -> gen_grpc.UmGroupLeaveReq
+	59, // 78: gen_grpc.GrpcApi.UmGroupAddMem:input_type -> gen_grpc.UmGroupAddMemReq
+	61, // 79: gen_grpc.GrpcApi.UmGroupDelMem:input_type -> gen_grpc.UmGroupDelMemReq
+	63, // 80: gen_grpc.GrpcApi.UmGroupUpdateMem:input_type -> gen_grpc.UmGroupUpdateMemReq
+	69, // 81: gen_grpc.GrpcApi.ChatSendMsg:input_type -> gen_grpc.ChatSendMsgReq
+	71, // 82: gen_grpc.GrpcApi.ChatMarkRead:input_type -> gen_grpc.ChatMarkReadReq
+	73, // 83: gen_grpc.GrpcApi.ChatGetHistory:input_type -> gen_grpc.ChatGetHistoryReq
+	75, // 84: gen_grpc.GrpcApi.GetUpdateList:input_type -> gen_grpc.GetUpdateListReq
+	77, // 85: gen_grpc.GrpcApi.Subscribe:input_type -> gen_grpc.SubscribeReq
+	3,  // 86: gen_grpc.GrpcApi.SessUserLogin:output_type -> gen_grpc.SessUserLoginRes
+	5,  // 87: gen_grpc.GrpcApi.SessUserLogout:output_type -> gen_grpc.SessUserLogoutRes
+	10, // 88: gen_grpc.GrpcApi.SessGetList:output_type -> gen_grpc.SessGetListRes
+	12, // 89: gen_grpc.GrpcApi.SessRevoke:output_type -> gen_grpc.SessRevokeRes
+	14, // 90: gen_grpc.GrpcApi.SessRevokeOthers:output_type -> gen_grpc.SessRevokeOthersRes
+	7,  // 91: gen_grpc.GrpcApi.SessRefresh:output_type -> gen_grpc.SessRefreshRes
+	17, // 92: gen_grpc.GrpcApi.UmRegister:output_type -> gen_grpc.UmRegisterRes
+	19, // 93: gen_grpc.GrpcApi.UmUnregister:output_type -> gen_grpc.UmUnregisterRes
+	21, // 94: gen_grpc.GrpcApi.UmUserUpdateInfo:output_type -> gen_grpc.UmUserUpdateInfoRes
+	23, // 95: gen_grpc.GrpcApi.UmContactGetList:output_type -> gen_grpc.UmContactGetListRes
+	25, // 96: gen_grpc.GrpcApi.UmContactGetInfo:output_type -> gen_grpc.UmContactGetInfoRes
+	27, // 97: gen_grpc.GrpcApi.UmContactFind:output_type -> gen_grpc.UmContactFindRes
+	29, // 98: gen_grpc.GrpcApi.UmContactAddRequest:output_type -> gen_grpc.UmContactAddRequestRes
+	31, // 99: gen_grpc.GrpcApi.UmContactAccept:output_type -> gen_grpc.UmContactAcceptRes
+	33, // 100: gen_grpc.GrpcApi.UmContactReject:output_type -> gen_grpc.UmContactRejectRes
+	35, // 101: gen_grpc.GrpcApi.UmContactDel:output_type -> gen_grpc.UmContactDelRes
+	38, // 102: gen_grpc.GrpcApi.UmGroupGetList:output_type -> gen_grpc.UmGroupGetListRes
+	40, // 103: gen_grpc.GrpcApi.UmGroupGetInfo:output_type -> gen_grpc.UmGroupGetInfoRes
+	42, // 104: gen_grpc.GrpcApi.UmGroupUpdateInfo:output_type -> gen_grpc.UmGroupUpdateInfoRes
+	44, // 105: gen_grpc.GrpcApi.UmGroupFind:output_type -> gen_grpc.UmGroupFindRes
+	46, // 106: gen_grpc.GrpcApi.UmGroupCreate:output_type -> gen_grpc.UmGroupCreateRes
+	48, // 107: gen_grpc.GrpcApi.UmGroupDelete:output_type -> gen_grpc.UmGroupDeleteRes
+	50, // 108: gen_grpc.GrpcApi.UmGroupGetMemList:output_type -> gen_grpc.UmGroupGetMemListRes
+	52, // 109: gen_grpc.GrpcApi.UmGroupJoinRequest:output_type -> gen_grpc.UmGroupJoinRequestRes
+	54, // 110: gen_grpc.GrpcApi.UmGroupAccept:output_type -> gen_grpc.UmGroupAcceptRes
+	56, // 111: gen_grpc.GrpcApi.UmGroupReject:output_type -> gen_grpc.UmGroupRejectRes
+	58, // 112: gen_grpc.GrpcApi.UmGroupLeave:output_type -> gen_grpc.UmGroupLeaveRes
+	60, // 113: gen_grpc.GrpcApi.UmGroupAddMem:output_type -> gen_grpc.UmGroupAddMemRes
+	62, // 114: gen_grpc.GrpcApi.UmGroupDelMem:output_type -> gen_grpc.UmGroupDelMemRes
+	64, // 115: gen_grpc.GrpcApi.UmGroupUpdateMem:output_type -> gen_grpc.UmGroupUpdateMemRes
+	70, // 116: gen_grpc.GrpcApi.ChatSendMsg:output_type -> gen_grpc.ChatSendMsgRes
+	72, // 117: gen_grpc.GrpcApi.ChatMarkRead:output_type -> gen_grpc.ChatMarkReadRes
+	74, // 118: gen_grpc.GrpcApi.ChatGetHistory:output_type -> gen_grpc.ChatGetHistoryRes
+	76, // 119: gen_grpc.GrpcApi.GetUpdateList:output_type -> gen_grpc.GetUpdateListRes
+	78, // 120: gen_grpc.GrpcApi.Subscribe:output_type -> gen_grpc.SubscribeRes
+	86, // [86:121] is the sub-list for method output_type
+	51, // [51:86] is the sub-list for method input_type
+	51, // [51:51] is the sub-list for extension type_name
+	51, // [51:51] is the sub-list for extension extendee
+	0,  // [0:51] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_init() }
@@ -4621,7 +5643,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactInfo); i {
+			switch v := v.(*SessRefreshReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4633,7 +5655,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmRegisterReq); i {
+			switch v := v.(*SessRefreshRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4645,7 +5667,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmRegisterRes); i {
+			switch v := v.(*SessInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4657,7 +5679,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmUnregisterReq); i {
+			switch v := v.(*SessGetListReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4669,7 +5691,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmUnregisterRes); i {
+			switch v := v.(*SessGetListRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4681,7 +5703,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmUserUpdateInfoReq); i {
+			switch v := v.(*SessRevokeReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4693,7 +5715,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmUserUpdateInfoRes); i {
+			switch v := v.(*SessRevokeRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4705,7 +5727,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactGetListReq); i {
+			switch v := v.(*SessRevokeOthersReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4717,7 +5739,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactGetListRes); i {
+			switch v := v.(*SessRevokeOthersRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4729,7 +5751,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactGetInfoReq); i {
+			switch v := v.(*UmContactInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4741,7 +5763,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactGetInfoRes); i {
+			switch v := v.(*UmRegisterReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4753,7 +5775,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactFindReq); i {
+			switch v := v.(*UmRegisterRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4765,7 +5787,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactFindRes); i {
+			switch v := v.(*UmUnregisterReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4777,7 +5799,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactAddRequestReq); i {
+			switch v := v.(*UmUnregisterRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4789,7 +5811,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactAddRequestRes); i {
+			switch v := v.(*UmUserUpdateInfoReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4801,7 +5823,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactAcceptReq); i {
+			switch v := v.(*UmUserUpdateInfoRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4813,7 +5835,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactAcceptRes); i {
+			switch v := v.(*UmContactGetListReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4825,7 +5847,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactRejectReq); i {
+			switch v := v.(*UmContactGetListRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4837,7 +5859,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactRejectRes); i {
+			switch v := v.(*UmContactGetInfoReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4849,7 +5871,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactDelReq); i {
+			switch v := v.(*UmContactGetInfoRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4861,7 +5883,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmContactDelRes); i {
+			switch v := v.(*UmContactFindReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4873,7 +5895,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupInfo); i {
+			switch v := v.(*UmContactFindRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4885,7 +5907,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupGetListReq); i {
+			switch v := v.(*UmContactAddRequestReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4897,7 +5919,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupGetListRes); i {
+			switch v := v.(*UmContactAddRequestRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4909,7 +5931,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupGetInfoReq); i {
+			switch v := v.(*UmContactAcceptReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4921,7 +5943,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupGetInfoRes); i {
+			switch v := v.(*UmContactAcceptRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4933,7 +5955,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupUpdateInfoReq); i {
+			switch v := v.(*UmContactRejectReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4945,7 +5967,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupUpdateInfoRes); i {
+			switch v := v.(*UmContactRejectRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4957,7 +5979,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupFindReq); i {
+			switch v := v.(*UmContactDelReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4969,7 +5991,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupFindRes); i {
+			switch v := v.(*UmContactDelRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4981,7 +6003,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupCreateReq); i {
+			switch v := v.(*UmGroupInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4993,7 +6015,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupCreateRes); i {
+			switch v := v.(*UmGroupGetListReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5005,7 +6027,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupDeleteReq); i {
+			switch v := v.(*UmGroupGetListRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5017,7 +6039,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupDeleteRes); i {
+			switch v := v.(*UmGroupGetInfoReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5029,7 +6051,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupGetMemListReq); i {
+			switch v := v.(*UmGroupGetInfoRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5041,7 +6063,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupGetMemListRes); i {
+			switch v := v.(*UmGroupUpdateInfoReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5053,7 +6075,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupJoinRequestReq); i {
+			switch v := v.(*UmGroupUpdateInfoRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5065,7 +6087,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupJoinRequestRes); i {
+			switch v := v.(*UmGroupFindReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5077,7 +6099,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupAcceptReq); i {
+			switch v := v.(*UmGroupFindRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5089,7 +6111,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupAcceptRes); i {
+			switch v := v.(*UmGroupCreateReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5101,7 +6123,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupRejectReq); i {
+			switch v := v.(*UmGroupCreateRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5113,7 +6135,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupRejectRes); i {
+			switch v := v.(*UmGroupDeleteReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5125,7 +6147,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupLeaveReq); i {
+			switch v := v.(*UmGroupDeleteRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5137,7 +6159,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupLeaveRes); i {
+			switch v := v.(*UmGroupGetMemListReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5149,7 +6171,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupAddMemReq); i {
+			switch v := v.(*UmGroupGetMemListRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5161,7 +6183,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupAddMemRes); i {
+			switch v := v.(*UmGroupJoinRequestReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5173,7 +6195,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupDelMemReq); i {
+			switch v := v.(*UmGroupJoinRequestRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5185,7 +6207,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupDelMemRes); i {
+			switch v := v.(*UmGroupAcceptReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5197,7 +6219,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupUpdateMemReq); i {
+			switch v := v.(*UmGroupAcceptRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5209,7 +6231,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UmGroupUpdateMemRes); i {
+			switch v := v.(*UmGroupRejectReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5221,7 +6243,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatPeerId); i {
+			switch v := v.(*UmGroupRejectRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5233,7 +6255,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatMsg); i {
+			switch v := v.(*UmGroupLeaveReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5245,7 +6267,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatConvMsg); i {
+			switch v := v.(*UmGroupLeaveRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5257,7 +6279,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatConvInfo); i {
+			switch v := v.(*UmGroupAddMemReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5269,7 +6291,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatSendMsgReq); i {
+			switch v := v.(*UmGroupAddMemRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5281,7 +6303,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatSendMsgRes); i {
+			switch v := v.(*UmGroupDelMemReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5293,7 +6315,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatMarkReadReq); i {
+			switch v := v.(*UmGroupDelMemRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5305,7 +6327,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChatMarkReadRes); i {
+			switch v := v.(*UmGroupUpdateMemReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5317,7 +6339,7 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetUpdateListReq); i {
+			switch v := v.(*UmGroupUpdateMemRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5329,6 +6351,138 @@ func file_api_proto_init() {
 			}
 		}
 		file_api_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatPeerId); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatConvMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatConvInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatSendMsgReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatSendMsgRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatMarkReadReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatMarkReadRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatGetHistoryReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatGetHistoryRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUpdateListReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetUpdateListRes); i {
 			case 0:
 				return &v.state
@@ -5340,8 +6494,32 @@ func file_api_proto_init() {
 				return nil
 			}
 		}
+		file_api_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_api_proto_msgTypes[54].OneofWrappers = []interface{}{
+	file_api_proto_msgTypes[63].OneofWrappers = []interface{}{
 		(*ChatPeerId_Uid)(nil),
 		(*ChatPeerId_GroupId)(nil),
 	}
@@ -5351,7 +6529,7 @@ func file_api_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   64,
+			NumMessages:   77,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,10 @@ type GrpcApiClient interface {
 	// 会话
 	SessUserLogin(ctx context.Context, in *SessUserLoginReq, opts ...grpc.CallOption) (*SessUserLoginRes, error)
 	SessUserLogout(ctx context.Context, in *SessUserLogoutReq, opts ...grpc.CallOption) (*SessUserLogoutRes, error)
+	SessGetList(ctx context.Context, in *SessGetListReq, opts ...grpc.CallOption) (*SessGetListRes, error)
+	SessRevoke(ctx context.Context, in *SessRevokeReq, opts ...grpc.CallOption) (*SessRevokeRes, error)
+	SessRevokeOthers(ctx context.Context, in *SessRevokeOthersReq, opts ...grpc.CallOption) (*SessRevokeOthersRes, error)
+	SessRefresh(ctx context.Context, in *SessRefreshReq, opts ...grpc.CallOption) (*SessRefreshRes, error)
 	// 用户管理
 	UmRegister(ctx context.Context, in *UmRegisterReq, opts ...grpc.CallOption) (*UmRegisterRes, error)
 	UmUnregister(ctx context.Context, in *UmUnregisterReq, opts ...grpc.CallOption) (*UmUnregisterRes, error)
@@ -55,8 +59,10 @@ type GrpcApiClient interface {
 	// 聊天
 	ChatSendMsg(ctx context.Context, in *ChatSendMsgReq, opts ...grpc.CallOption) (*ChatSendMsgRes, error)
 	ChatMarkRead(ctx context.Context, in *ChatMarkReadReq, opts ...grpc.CallOption) (*ChatMarkReadRes, error)
+	ChatGetHistory(ctx context.Context, in *ChatGetHistoryReq, opts ...grpc.CallOption) (*ChatGetHistoryRes, error)
 	// 更新事件
 	GetUpdateList(ctx context.Context, in *GetUpdateListReq, opts ...grpc.CallOption) (*GetUpdateListRes, error)
+	Subscribe(ctx context.Context, in *SubscribeReq, opts ...grpc.CallOption) (GrpcApi_SubscribeClient, error)
 }
 
 type grpcApiClient struct {
@@ -85,6 +91,42 @@ func (c *grpcApiClient) SessUserLogout(ctx context.Context, in *SessUserLogoutRe
 	return out, nil
 }
 
+func (c *grpcApiClient) SessGetList(ctx context.Context, in *SessGetListReq, opts ...grpc.CallOption) (*SessGetListRes, error) {
+	out := new(SessGetListRes)
+	err := c.cc.Invoke(ctx, "/gen_grpc.GrpcApi/SessGetList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grpcApiClient) SessRevoke(ctx context.Context, in *SessRevokeReq, opts ...grpc.CallOption) (*SessRevokeRes, error) {
+	out := new(SessRevokeRes)
+	err := c.cc.Invoke(ctx, "/gen_grpc.GrpcApi/SessRevoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grpcApiClient) SessRevokeOthers(ctx context.Context, in *SessRevokeOthersReq, opts ...grpc.CallOption) (*SessRevokeOthersRes, error) {
+	out := new(SessRevokeOthersRes)
+	err := c.cc.Invoke(ctx, "/gen_grpc.GrpcApi/SessRevokeOthers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *grpcApiClient) SessRefresh(ctx context.Context, in *SessRefreshReq, opts ...grpc.CallOption) (*SessRefreshRes, error) {
+	out := new(SessRefreshRes)
+	err := c.cc.Invoke(ctx, "/gen_grpc.GrpcApi/SessRefresh", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *grpcApiClient) UmRegister(ctx context.Context, in *UmRegisterReq, opts ...grpc.CallOption) (*UmRegisterRes, error) {
 	out := new(UmRegisterRes)
 	err := c.cc.Invoke(ctx, "/gen_grpc.GrpcApi/UmRegister", in, out, opts...)
@@ -319,6 +361,15 @@ func (c *grpcApiClient) ChatMarkRead(ctx context.Context, in *ChatMarkReadReq, o
 	return out, nil
 }
 
+func (c *grpcApiClient) ChatGetHistory(ctx context.Context, in *ChatGetHistoryReq, opts ...grpc.CallOption) (*ChatGetHistoryRes, error) {
+	out := new(ChatGetHistoryRes)
+	err := c.cc.Invoke(ctx, "/gen_grpc.GrpcApi/ChatGetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *grpcApiClient) GetUpdateList(ctx context.Context, in *GetUpdateListReq, opts ...grpc.CallOption) (*GetUpdateListRes, error) {
 	out := new(GetUpdateListRes)
 	err := c.cc.Invoke(ctx, "/gen_grpc.GrpcApi/GetUpdateList", in, out, opts...)
@@ -328,6 +379,38 @@ func (c *grpcApiClient) GetUpdateList(ctx context.Context, in *GetUpdateListReq,
 	return out, nil
 }
 
+func (c *grpcApiClient) Subscribe(ctx context.Context, in *SubscribeReq, opts ...grpc.CallOption) (GrpcApi_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GrpcApi_ServiceDesc.Streams[0], "/gen_grpc.GrpcApi/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpcApiSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GrpcApi_SubscribeClient interface {
+	Recv() (*SubscribeRes, error)
+	grpc.ClientStream
+}
+
+type grpcApiSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *grpcApiSubscribeClient) Recv() (*SubscribeRes, error) {
+	m := new(SubscribeRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // GrpcApiServer is the server API for GrpcApi service.
 // All implementations must embed UnimplementedGrpcApiServer
 // for forward compatibility
@@ -335,6 +418,10 @@ type GrpcApiServer interface {
 	// 会话
 	SessUserLogin(context.Context, *SessUserLoginReq) (*SessUserLoginRes, error)
 	SessUserLogout(context.Context, *SessUserLogoutReq) (*SessUserLogoutRes, error)
+	SessGetList(context.Context, *SessGetListReq) (*SessGetListRes, error)
+	SessRevoke(context.Context, *SessRevokeReq) (*SessRevokeRes, error)
+	SessRevokeOthers(context.Context, *SessRevokeOthersReq) (*SessRevokeOthersRes, error)
+	SessRefresh(context.Context, *SessRefreshReq) (*SessRefreshRes, error)
 	// 用户管理
 	UmRegister(context.Context, *UmRegisterReq) (*UmRegisterRes, error)
 	UmUnregister(context.Context, *UmUnregisterReq) (*UmUnregisterRes, error)
@@ -365,8 +452,10 @@ type GrpcApiServer interface {
 	// 聊天
 	ChatSendMsg(context.Context, *ChatSendMsgReq) (*ChatSendMsgRes, error)
 	ChatMarkRead(context.Context, *ChatMarkReadReq) (*ChatMarkReadRes, error)
+	ChatGetHistory(context.Context, *ChatGetHistoryReq) (*ChatGetHistoryRes, error)
 	// 更新事件
 	GetUpdateList(context.Context, *GetUpdateListReq) (*GetUpdateListRes, error)
+	Subscribe(*SubscribeReq, GrpcApi_SubscribeServer) error
 	mustEmbedUnimplementedGrpcApiServer()
 }
 
@@ -380,6 +469,18 @@ func (UnimplementedGrpcApiServer) SessUserLogin(context.Context, *SessUserLoginR
 func (UnimplementedGrpcApiServer) SessUserLogout(context.Context, *SessUserLogoutReq) (*SessUserLogoutRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SessUserLogout not implemented")
 }
+func (UnimplementedGrpcApiServer) SessGetList(context.Context, *SessGetListReq) (*SessGetListRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SessGetList not implemented")
+}
+func (UnimplementedGrpcApiServer) SessRevoke(context.Context, *SessRevokeReq) (*SessRevokeRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SessRevoke not implemented")
+}
+func (UnimplementedGrpcApiServer) SessRevokeOthers(context.Context, *SessRevokeOthersReq) (*SessRevokeOthersRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SessRevokeOthers not implemented")
+}
+func (UnimplementedGrpcApiServer) SessRefresh(context.Context, *SessRefreshReq) (*SessRefreshRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SessRefresh not implemented")
+}
 func (UnimplementedGrpcApiServer) UmRegister(context.Context, *UmRegisterReq) (*UmRegisterRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UmRegister not implemented")
 }
@@ -458,9 +559,15 @@ func (UnimplementedGrpcApiServer) ChatSendMsg(context.Context, *ChatSendMsgReq)
 func (UnimplementedGrpcApiServer) ChatMarkRead(context.Context, *ChatMarkReadReq) (*ChatMarkReadRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ChatMarkRead not implemented")
 }
+func (UnimplementedGrpcApiServer) ChatGetHistory(context.Context, *ChatGetHistoryReq) (*ChatGetHistoryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChatGetHistory not implemented")
+}
 func (UnimplementedGrpcApiServer) GetUpdateList(context.Context, *GetUpdateListReq) (*GetUpdateListRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUpdateList not implemented")
 }
+func (UnimplementedGrpcApiServer) Subscribe(*SubscribeReq, GrpcApi_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
 func (UnimplementedGrpcApiServer) mustEmbedUnimplementedGrpcApiServer() {}
 
 // UnsafeGrpcApiServer may be embedded to opt out of forward compatibility for this service.
@@ -510,6 +617,78 @@ func _GrpcApi_SessUserLogout_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GrpcApi_SessGetList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessGetListReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcApiServer).SessGetList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gen_grpc.GrpcApi/SessGetList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcApiServer).SessGetList(ctx, req.(*SessGetListReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcApi_SessRevoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessRevokeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcApiServer).SessRevoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gen_grpc.GrpcApi/SessRevoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcApiServer).SessRevoke(ctx, req.(*SessRevokeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcApi_SessRevokeOthers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessRevokeOthersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcApiServer).SessRevokeOthers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gen_grpc.GrpcApi/SessRevokeOthers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcApiServer).SessRevokeOthers(ctx, req.(*SessRevokeOthersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GrpcApi_SessRefresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessRefreshReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcApiServer).SessRefresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gen_grpc.GrpcApi/SessRefresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcApiServer).SessRefresh(ctx, req.(*SessRefreshReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GrpcApi_UmRegister_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UmRegisterReq)
 	if err := dec(in); err != nil {
@@ -978,6 +1157,24 @@ func _GrpcApi_ChatMarkRead_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GrpcApi_ChatGetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChatGetHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GrpcApiServer).ChatGetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gen_grpc.GrpcApi/ChatGetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GrpcApiServer).ChatGetHistory(ctx, req.(*ChatGetHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GrpcApi_GetUpdateList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetUpdateListReq)
 	if err := dec(in); err != nil {
@@ -996,6 +1193,27 @@ func _GrpcApi_GetUpdateList_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GrpcApi_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GrpcApiServer).Subscribe(m, &grpcApiSubscribeServer{stream})
+}
+
+type GrpcApi_SubscribeServer interface {
+	Send(*SubscribeRes) error
+	grpc.ServerStream
+}
+
+type grpcApiSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *grpcApiSubscribeServer) Send(m *SubscribeRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // GrpcApi_ServiceDesc is the grpc.ServiceDesc for GrpcApi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1011,6 +1229,22 @@ var GrpcApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SessUserLogout",
 			Handler:    _GrpcApi_SessUserLogout_Handler,
 		},
+		{
+			MethodName: "SessGetList",
+			Handler:    _GrpcApi_SessGetList_Handler,
+		},
+		{
+			MethodName: "SessRevoke",
+			Handler:    _GrpcApi_SessRevoke_Handler,
+		},
+		{
+			MethodName: "SessRevokeOthers",
+			Handler:    _GrpcApi_SessRevokeOthers_Handler,
+		},
+		{
+			MethodName: "SessRefresh",
+			Handler:    _GrpcApi_SessRefresh_Handler,
+		},
 		{
 			MethodName: "UmRegister",
 			Handler:    _GrpcApi_UmRegister_Handler,
@@ -1115,11 +1349,21 @@ var GrpcApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ChatMarkRead",
 			Handler:    _GrpcApi_ChatMarkRead_Handler,
 		},
+		{
+			MethodName: "ChatGetHistory",
+			Handler:    _GrpcApi_ChatGetHistory_Handler,
+		},
 		{
 			MethodName: "GetUpdateList",
 			Handler:    _GrpcApi_GetUpdateList_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _GrpcApi_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "api.proto",
 }
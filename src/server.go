@@ -1,13 +1,193 @@
 package main
 
 import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "social_server/src/app/data"
     "social_server/src/app/service/api"
+    "social_server/src/app/service/core"
+    "social_server/src/app/service/sess_mgmt"
     . "social_server/src/utils/log"
+    "strconv"
+    "time"
 )
 
+const migrateUsage = "usage: migrate up [N] | down [N] | status"
+
+// runMigrate 执行表结构迁移
+func runMigrate(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(migrateUsage)
+	}
+	steps := 0
+	if len(args) == 2 {
+		var err error
+		steps, err = strconv.Atoi(args[1])
+		if err != nil || steps <= 0 {
+			return fmt.Errorf("invalid number of migrations: %s", args[1])
+		}
+	}
+
+	core.LoadEnv()
+	storage, err := data.NewSqlStorage()
+	if err != nil {
+		return err
+	}
+	err = storage.Open()
+	if err != nil {
+		return fmt.Errorf("Open: %w", err)
+	}
+	defer storage.Close()
+
+	// 配置了分片时主库之后依次迁移各分片
+	shards := storage.ShardDBs()
+	if len(shards) > 0 {
+		fmt.Println("main:")
+	}
+	err = migrateDB(storage, args[0], steps)
+	if err != nil {
+		return err
+	}
+	for i, shard := range shards {
+		fmt.Printf("shard %d:\n", i)
+		err = shard.Open()
+		if err != nil {
+			return fmt.Errorf("shard %d Open: %w", i, err)
+		}
+		// 分片随 storage.Close 关闭
+		err = migrateDB(shard, args[0], steps)
+		if err != nil {
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func migrateDB(storage *data.DB, cmd string, steps int) error {
+	switch cmd {
+	case "up":
+		done, err := storage.MigrateUp(steps)
+		for _, m := range done {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "down":
+		done, err := storage.MigrateDown(steps)
+		for _, m := range done {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		version, err := storage.SchemaVersion()
+		if err != nil {
+			return err
+		}
+		status, err := storage.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("schema version: %d\n", version)
+		for _, s := range status {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %04d_%s\n", state, s.Migration.Version, s.Migration.Name)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+const loginUnlockUsage = "usage: login-unlock user <username> | ip <ip>"
+
+// runLoginUnlock 解除用户名或 IP 的登录锁定
+func runLoginUnlock(args []string) error {
+	if len(args) != 2 {
+		return errors.New(loginUnlockUsage)
+	}
+	var key string
+	switch args[0] {
+	case "user":
+		key = sess_mgmt.LoginUserKey(args[1])
+	case "ip":
+		key = sess_mgmt.LoginIpKey(args[1])
+	default:
+		return errors.New(loginUnlockUsage)
+	}
+
+	core.LoadEnv()
+	// 内存缓存的锁定记录在服务进程内，重启服务即解除
+	if backend := data.CacheBackend(); backend != data.CacheBackendRedis {
+		return fmt.Errorf("CACHE_BACKEND is %s, lockouts are kept in the server process", backend)
+	}
+	guard, err := sess_mgmt.NewLoginGuard(data.NewCache())
+	if err != nil {
+		return err
+	}
+	wasLocked, err := guard.Unlock(context.Background(), key)
+	if err != nil {
+		return err
+	}
+	if wasLocked {
+		fmt.Printf("unlocked %s\n", key)
+	} else {
+		fmt.Printf("%s was not locked, failure count cleared\n", key)
+	}
+	return nil
+}
+
 func main() {
 	SetupLogger()
 
+	// 子命令
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "openapi":
+			// 输出 REST API 的 OpenAPI 文档
+			data, err := api.MarshalOpenApiDoc()
+			if err != nil {
+				Log.Error("MarshalOpenApiDoc: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		case "jwt-keygen":
+			// 生成一个 JWT_KEYS 中的签名密钥，kid 默认为当天日期
+			kid := time.Now().Format("20060102")
+			if len(os.Args) > 2 {
+				kid = os.Args[2]
+			}
+			key, err := sess_mgmt.GenerateTokenKey(kid)
+			if err != nil {
+				Log.Error("GenerateTokenKey: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(key)
+			return
+		case "login-unlock":
+			err := runLoginUnlock(os.Args[2:])
+			if err != nil {
+				Log.Error("login-unlock: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "migrate":
+			err := runMigrate(os.Args[2:])
+			if err != nil {
+				Log.Error("migrate: %v", err)
+				os.Exit(1)
+			}
+			return
+		default:
+			Log.Error("unknown command: %s", os.Args[1])
+			os.Exit(1)
+		}
+	}
+
 	modAPi := api.NewModApi()
 	err := modAPi.StartRpcServer()
 	if err != nil {